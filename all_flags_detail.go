@@ -0,0 +1,251 @@
+package ldclient
+
+import (
+	gocontext "context"
+	"strings"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldeval "github.com/launchdarkly/go-server-sdk-evaluation/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+)
+
+const (
+	allFlagsDetailFuncName   = "LDClient.AllFlagsDetail"
+	allFlagsDetailExFuncName = "LDClient.AllFlagsDetailCtx"
+)
+
+// AllFlagsDetailOption is the interface for optional parameters that can be passed to
+// [LDClient.AllFlagsDetail].
+//
+// If several options that select flags are passed, a flag is only evaluated if it satisfies all of them.
+type AllFlagsDetailOption interface {
+	apply(*allFlagsDetailOptions)
+}
+
+type allFlagsDetailOptions struct {
+	keys       []string
+	keyPrefix  string
+	predicates []func(*ldmodel.FeatureFlag) bool
+	withEvents bool
+	withHooks  bool
+}
+
+type allFlagsDetailKeysOption []string
+type allFlagsDetailKeyPrefixOption string
+type allFlagsDetailFilterOption func(*ldmodel.FeatureFlag) bool
+type allFlagsDetailWithEventsOption struct{}
+type allFlagsDetailWithHooksOption struct{}
+
+// AllFlagsDetailKeys is an option that can be passed to [LDClient.AllFlagsDetail]. It specifies that only
+// the flags with the given keys should be evaluated. If a key does not match any flag, the result for that
+// key has the error kind [ldreason.EvalErrorFlagNotFound], just as it would for a single evaluation.
+//
+// This is also the most efficient way to evaluate a small number of flags, since the other flags do not
+// need to be read from the data store.
+func AllFlagsDetailKeys(keys ...string) AllFlagsDetailOption {
+	return allFlagsDetailKeysOption(keys)
+}
+
+// AllFlagsDetailKeyPrefix is an option that can be passed to [LDClient.AllFlagsDetail]. It specifies that
+// only flags whose keys begin with the given prefix should be evaluated.
+func AllFlagsDetailKeyPrefix(prefix string) AllFlagsDetailOption {
+	return allFlagsDetailKeyPrefixOption(prefix)
+}
+
+// AllFlagsDetailFilter is an option that can be passed to [LDClient.AllFlagsDetail]. It specifies that only
+// flags for which the predicate returns true should be evaluated. The predicate must not modify the flag.
+//
+//	// evaluate only the flags that are available to client-side SDKs
+//	details := client.AllFlagsDetail(context, ldclient.AllFlagsDetailFilter(func(flag *ldmodel.FeatureFlag) bool {
+//	    return flag.ClientSideAvailability.UsingEnvironmentID
+//	}))
+func AllFlagsDetailFilter(predicate func(flag *ldmodel.FeatureFlag) bool) AllFlagsDetailOption {
+	return allFlagsDetailFilterOption(predicate)
+}
+
+// AllFlagsDetailWithEvents is an option that can be passed to [LDClient.AllFlagsDetail]. It specifies that
+// an analytics event should be generated for each evaluation, as if each flag had been evaluated with
+// [LDClient.JSONVariationDetail]. By default, AllFlagsDetail does not generate any events.
+func AllFlagsDetailWithEvents() AllFlagsDetailOption {
+	return allFlagsDetailWithEventsOption{}
+}
+
+// AllFlagsDetailWithHooks is an option that can be passed to [LDClient.AllFlagsDetail]. It specifies that
+// the evaluation series of any configured hooks should be run for each flag that is evaluated, with the
+// method name "LDClient.AllFlagsDetail" or "LDClient.AllFlagsDetailCtx". By default, hooks are not run.
+func AllFlagsDetailWithHooks() AllFlagsDetailOption {
+	return allFlagsDetailWithHooksOption{}
+}
+
+func (o allFlagsDetailKeysOption) apply(options *allFlagsDetailOptions) {
+	options.keys = append(options.keys, o...)
+}
+
+func (o allFlagsDetailKeyPrefixOption) apply(options *allFlagsDetailOptions) {
+	options.keyPrefix = string(o)
+}
+
+func (o allFlagsDetailFilterOption) apply(options *allFlagsDetailOptions) {
+	options.predicates = append(options.predicates, o)
+}
+
+func (o allFlagsDetailWithEventsOption) apply(options *allFlagsDetailOptions) {
+	options.withEvents = true
+}
+
+func (o allFlagsDetailWithHooksOption) apply(options *allFlagsDetailOptions) {
+	options.withHooks = true
+}
+
+func (o allFlagsDetailOptions) includes(key string, flag *ldmodel.FeatureFlag) bool {
+	if !strings.HasPrefix(key, o.keyPrefix) {
+		return false
+	}
+	for _, predicate := range o.predicates {
+		if flag != nil && !predicate(flag) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllFlagsDetail evaluates all feature flags for the given evaluation context, and returns the result of
+// each evaluation, including the reason, keyed by flag key.
+//
+// Unlike [LDClient.AllFlagsState], which is intended for passing flag state to a client-side SDK, this
+// method is intended for applications that need to use many flag values on the server side at once, such
+// as when rendering a page. The results are the same as if each flag had been evaluated with
+// [LDClient.JSONVariationDetail] and a null default value: if a flag is off and has no off variation, or
+// an error occurs, the value in the result is null.
+//
+// The flags to be evaluated can be limited by passing [AllFlagsDetailKeys], [AllFlagsDetailKeyPrefix], or
+// [AllFlagsDetailFilter]. By default no analytics events are generated and hooks are not run, but either
+// can be enabled by passing [AllFlagsDetailWithEvents] or [AllFlagsDetailWithHooks].
+//
+// If the client is offline, or has not been initialized and has no stored data, the result contains only
+// the keys passed with AllFlagsDetailKeys, each with the error kind [ldreason.EvalErrorClientNotReady].
+func (client *LDClient) AllFlagsDetail(
+	context ldcontext.Context,
+	options ...AllFlagsDetailOption,
+) map[string]ldreason.EvaluationDetail {
+	return client.allFlagsDetail(gocontext.TODO(), context, client.eventsWithReasons, allFlagsDetailFuncName, options)
+}
+
+// AllFlagsDetailCtx is the same as [LDClient.AllFlagsDetail], but accepts a context.Context.
+//
+// Cancelling the context.Context will not cause the evaluation to be cancelled. The context.Context is used
+// by hook implementations refer to [ldhooks.Hook].
+func (client *LDClient) AllFlagsDetailCtx(
+	ctx gocontext.Context,
+	context ldcontext.Context,
+	options ...AllFlagsDetailOption,
+) map[string]ldreason.EvaluationDetail {
+	return client.allFlagsDetail(ctx, context, client.eventsWithReasons, allFlagsDetailExFuncName, options)
+}
+
+func (client *LDClient) allFlagsDetail(
+	ctx gocontext.Context,
+	evalContext ldcontext.Context,
+	eventsScope eventsScope,
+	method string,
+	options []AllFlagsDetailOption,
+) map[string]ldreason.EvaluationDetail {
+	var opts allFlagsDetailOptions
+	for _, o := range options {
+		o.apply(&opts)
+	}
+	if !opts.withEvents {
+		eventsScope = newDisabledEventsScope()
+	}
+
+	if err := evalContext.Err(); err != nil {
+		client.loggers.Warnf("Tried to evaluate flags with an invalid context: %s", err)
+		return allFlagsDetailErrors(opts.keys, ldreason.EvalErrorUserNotSpecified)
+	}
+	if !client.canEvaluateAllFlags("AllFlagsDetail") {
+		return allFlagsDetailErrors(opts.keys, ldreason.EvalErrorClientNotReady)
+	}
+
+	flags, err := client.flagsForAllFlagsDetail(opts)
+	if err != nil {
+		client.loggers.Warn("Unable to fetch flags from data store. Returning empty state. Error: " + err.Error())
+		return allFlagsDetailErrors(opts.keys, ldreason.EvalErrorException)
+	}
+
+	results := make(map[string]ldreason.EvaluationDetail, len(flags))
+	for key, flag := range flags {
+		if !opts.includes(key, flag) {
+			continue
+		}
+		key, flag := key, flag
+		evaluate := func() (ldreason.EvaluationDetail, *ldmodel.FeatureFlag, error) {
+			return client.evaluateForAllFlagsDetail(key, flag, evalContext, eventsScope), flag, nil
+		}
+		var detail ldreason.EvaluationDetail
+		if opts.withHooks {
+			detail, _, _ = client.hookRunner.RunEvaluation(ctx, key, evalContext, ldvalue.Null(), method, evaluate)
+		} else {
+			detail, _, _ = evaluate()
+		}
+		results[key] = detail
+	}
+	return results
+}
+
+// flagsForAllFlagsDetail returns the flags that AllFlagsDetail should consider, keyed by flag key. If specific
+// keys were requested, any that do not exist map to nil.
+func (client *LDClient) flagsForAllFlagsDetail(opts allFlagsDetailOptions) (map[string]*ldmodel.FeatureFlag, error) {
+	store := client.dataSystem.Store()
+	if opts.keys != nil {
+		flags := make(map[string]*ldmodel.FeatureFlag, len(opts.keys))
+		for _, key := range opts.keys {
+			item, err := store.Get(datakinds.Features, key)
+			if err != nil {
+				return nil, err
+			}
+			flag, _ := item.Item.(*ldmodel.FeatureFlag)
+			flags[key] = flag
+		}
+		return flags, nil
+	}
+	items, err := store.GetAll(datakinds.Features)
+	if err != nil {
+		return nil, err
+	}
+	flags := make(map[string]*ldmodel.FeatureFlag, len(items))
+	for _, item := range items {
+		if flag, ok := item.Item.Item.(*ldmodel.FeatureFlag); ok {
+			flags[item.Key] = flag
+		}
+	}
+	return flags, nil
+}
+
+func (client *LDClient) evaluateForAllFlagsDetail(
+	key string,
+	flag *ldmodel.FeatureFlag,
+	evalContext ldcontext.Context,
+	eventsScope eventsScope,
+) ldreason.EvaluationDetail {
+	var result ldeval.Result
+	if flag == nil {
+		result.Detail = newEvaluationError(ldvalue.Null(), ldreason.EvalErrorFlagNotFound)
+	} else {
+		result = client.evaluator.Evaluate(flag, evalContext, eventsScope.prerequisiteEventRecorder)
+	}
+	if !eventsScope.disabled {
+		client.recordEvaluation(key, evalContext, ldvalue.Null(), result, flag, eventsScope)
+	}
+	return result.Detail
+}
+
+func allFlagsDetailErrors(keys []string, errorKind ldreason.EvalErrorKind) map[string]ldreason.EvaluationDetail {
+	results := make(map[string]ldreason.EvaluationDetail, len(keys))
+	for _, key := range keys {
+		results[key] = newEvaluationError(ldvalue.Null(), errorKind)
+	}
+	return results
+}
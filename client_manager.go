@@ -0,0 +1,281 @@
+package ldclient
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// Client manager errors
+var (
+	// ClientManager.AddEnvironment returns this error if there is already a client for the same SDK key,
+	// or one is being created.
+	ErrEnvironmentAlreadyAdded = errors.New("a LaunchDarkly client already exists for this SDK key")
+
+	// ClientManager.AddEnvironment returns this error if the ClientManager has been closed.
+	ErrClientManagerClosed = errors.New("LaunchDarkly client manager has been closed")
+)
+
+// ClientManager creates and owns LDClient instances for several LaunchDarkly environments in the same
+// process, identified by their SDK keys.
+//
+// All of the clients use the same HTTP transport, so they share a pool of connections to LaunchDarkly
+// rather than each having its own. Their analytics events are also flushed on a shared schedule, rather
+// than each client running a flush timer of its own: clients whose event configuration has the same
+// flush interval are flushed together by one goroutine. This applies to event processors built by
+// [ldcomponents.SendEvents], including when a custom Config.Events component delegates to it. An event
+// processor that is built in any other way is not flushed by the ClientManager, so it must schedule its
+// own flushes, as it would in a single LDClient.
+//
+// Each client still has its own data source, data store, and event processor, since these deliver and
+// receive data for one SDK key. Each event processor also still sends its periodic diagnostic event on
+// its own schedule, since that event describes one client and is scheduled by the event processor
+// itself. [ClientManager.Flush] flushes the events for all of the clients at once.
+//
+// Environments can be added and removed at any time. A ClientManager is safe for concurrent use.
+//
+//	manager := ld.NewClientManager(ld.Config{})
+//	defer manager.Close()
+//	for _, sdkKey := range sdkKeysForRegions {
+//	    if _, err := manager.AddEnvironment(sdkKey, 5*time.Second); err != nil {
+//	        log.Printf("LaunchDarkly environment failed to initialize: %s", err)
+//	    }
+//	}
+//	if client, ok := manager.Client(sdkKeyForRequest); ok {
+//	    enabled, _ := client.BoolVariation("new-checkout", context, false)
+//	}
+type ClientManager struct {
+	config         Config
+	http           *sharedHTTPConfigurer
+	flushScheduler *internal.EventFlushScheduler
+	clients        map[string]*LDClient
+	pending        map[string]struct{}
+	closed         bool
+	lock           sync.Mutex
+}
+
+// ClientManagerStatus describes the state of all of the environments in a [ClientManager].
+type ClientManagerStatus struct {
+	// Environments contains the status of each environment, keyed by SDK key.
+	Environments map[string]EnvironmentStatus
+	// AllInitialized is true if every environment's client has been initialized, as reported by
+	// [LDClient.Initialized]. It is also true if there are no environments.
+	AllInitialized bool
+}
+
+// EnvironmentStatus describes the state of one environment in a [ClientManager].
+type EnvironmentStatus struct {
+	// Initialized is the value of [LDClient.Initialized].
+	Initialized bool
+	// DataSource is the status reported by [LDClient.GetDataSourceStatusProvider].
+	DataSource interfaces.DataSourceStatus
+	// DataStore is the status reported by [LDClient.GetDataStoreStatusProvider].
+	DataStore interfaces.DataStoreStatus
+}
+
+// NewClientManager creates a [ClientManager]. The configuration is used for every environment that is
+// added with [ClientManager.AddEnvironment]; its HTTP property also determines the HTTP transport that
+// every environment shares.
+//
+// Be careful with components in the configuration that represent a single resource, rather than
+// building a new one for each client. For instance, if the environments use a persistent data store,
+// each one needs its own key prefix, so they should be added with [ClientManager.AddEnvironmentWithConfig].
+func NewClientManager(config Config) *ClientManager {
+	httpConfigurer := config.HTTP
+	if httpConfigurer == nil {
+		httpConfigurer = ldcomponents.HTTPConfiguration()
+	}
+	return &ClientManager{
+		config:         config,
+		http:           &sharedHTTPConfigurer{base: httpConfigurer},
+		flushScheduler: internal.NewEventFlushScheduler(),
+		clients:        make(map[string]*LDClient),
+		pending:        make(map[string]struct{}),
+	}
+}
+
+// AddEnvironment creates a client for an SDK key, with the ClientManager's configuration.
+//
+// This is the same as [MakeCustomClient]: it waits up to waitFor for the client to initialize, and if it
+// returns [ErrInitializationTimeout] or [ErrInitializationFailed], it also returns the client, which is
+// kept by the ClientManager. Any other error means that the client could not be created at all.
+func (m *ClientManager) AddEnvironment(sdkKey string, waitFor time.Duration) (*LDClient, error) {
+	return m.AddEnvironmentWithConfig(sdkKey, m.config, waitFor)
+}
+
+// AddEnvironmentWithConfig is the same as [ClientManager.AddEnvironment], but uses the specified
+// configuration instead of the ClientManager's. The HTTP property of the configuration is ignored: every
+// environment uses the HTTP configuration that was passed to [NewClientManager].
+func (m *ClientManager) AddEnvironmentWithConfig(
+	sdkKey string,
+	config Config,
+	waitFor time.Duration,
+) (*LDClient, error) {
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return nil, ErrClientManagerClosed
+	}
+	_, exists := m.clients[sdkKey]
+	_, isPending := m.pending[sdkKey]
+	if exists || isPending {
+		m.lock.Unlock()
+		return nil, ErrEnvironmentAlreadyAdded
+	}
+	m.pending[sdkKey] = struct{}{}
+	m.lock.Unlock()
+
+	// The lock is not held while the client starts, since that can take up to waitFor.
+	config.HTTP = m.http
+	client, err := makeCustomClient(sdkKey, config, waitFor, m.flushScheduler)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.pending, sdkKey)
+	if client == nil {
+		return nil, err
+	}
+	if m.closed {
+		_ = client.Close()
+		return nil, ErrClientManagerClosed
+	}
+	m.clients[sdkKey] = client
+	return client, err
+}
+
+// Client returns the client for an SDK key, or false if there is none.
+func (m *ClientManager) Client(sdkKey string) (*LDClient, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	client, ok := m.clients[sdkKey]
+	return client, ok
+}
+
+// SDKKeys returns the SDK keys of all of the environments, in sorted order.
+func (m *ClientManager) SDKKeys() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	keys := make([]string, 0, len(m.clients))
+	for key := range m.clients {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RemoveEnvironment closes the client for an SDK key and removes it from the ClientManager. It does
+// nothing if there is no such client.
+func (m *ClientManager) RemoveEnvironment(sdkKey string) error {
+	m.lock.Lock()
+	client, ok := m.clients[sdkKey]
+	delete(m.clients, sdkKey)
+	m.lock.Unlock()
+	if !ok {
+		return nil
+	}
+	return client.Close()
+}
+
+// Status returns the current status of every environment.
+func (m *ClientManager) Status() ClientManagerStatus {
+	m.lock.Lock()
+	clients := make(map[string]*LDClient, len(m.clients))
+	for key, client := range m.clients {
+		clients[key] = client
+	}
+	m.lock.Unlock()
+
+	status := ClientManagerStatus{
+		Environments:   make(map[string]EnvironmentStatus, len(clients)),
+		AllInitialized: true,
+	}
+	for key, client := range clients {
+		envStatus := EnvironmentStatus{
+			Initialized: client.Initialized(),
+			DataSource:  client.GetDataSourceStatusProvider().GetStatus(),
+			DataStore:   client.GetDataStoreStatusProvider().GetStatus(),
+		}
+		status.Environments[key] = envStatus
+		status.AllInitialized = status.AllInitialized && envStatus.Initialized
+	}
+	return status
+}
+
+// Flush tells the client for every environment that all pending analytics events should be delivered
+// as soon as possible. See [LDClient.Flush].
+func (m *ClientManager) Flush() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, client := range m.clients {
+		client.Flush()
+	}
+}
+
+// Close closes the clients for all of the environments. After calling this, the ClientManager and its
+// clients should no longer be used. If any of the clients returns an error, Close returns the first one.
+func (m *ClientManager) Close() error {
+	m.lock.Lock()
+	m.closed = true
+	clients := m.clients
+	m.clients = make(map[string]*LDClient)
+	m.lock.Unlock()
+
+	var firstErr error
+	for _, client := range clients {
+		if err := client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	m.flushScheduler.Close()
+	return firstErr
+}
+
+// sharedHTTPConfigurer builds the HTTP configuration for each client in a ClientManager. The default
+// headers are built separately for each client, since they include the SDK key, but every client uses the
+// HTTP client factory from the first configuration that was built, and therefore the same transport.
+//
+// If the base configuration is an ldcomponents.HTTPConfigurationBuilder, it is only built in full once;
+// after that, each client's configuration is built from a copy of it that uses the shared HTTP client
+// factory, so that no other transport is created. A custom configurer is built for every client, since
+// there is no other way to get its headers, but only the first transport is used.
+type sharedHTTPConfigurer struct {
+	base             subsystems.ComponentConfigurer[subsystems.HTTPConfiguration]
+	createHTTPClient func() *http.Client
+	lock             sync.Mutex
+}
+
+func (s *sharedHTTPConfigurer) Build( //nolint:revive // no doc comment for standard method
+	clientContext subsystems.ClientContext,
+) (subsystems.HTTPConfiguration, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if builder, ok := s.base.(*ldcomponents.HTTPConfigurationBuilder); ok && builder != nil && s.createHTTPClient != nil {
+		headersOnly := *builder
+		return headersOnly.HTTPClientFactory(s.createHTTPClient).Build(clientContext)
+	}
+	config, err := s.base.Build(clientContext)
+	if err != nil {
+		return config, err
+	}
+	if s.createHTTPClient == nil {
+		s.createHTTPClient = config.CreateHTTPClient
+	}
+	config.CreateHTTPClient = s.createHTTPClient
+	return config, nil
+}
+
+// DescribeConfiguration is used internally by the SDK to inspect the configuration.
+func (s *sharedHTTPConfigurer) DescribeConfiguration(clientContext subsystems.ClientContext) ldvalue.Value {
+	if dd, ok := s.base.(subsystems.DiagnosticDescription); ok {
+		return dd.DescribeConfiguration(clientContext)
+	}
+	return ldvalue.Null()
+}
@@ -0,0 +1,242 @@
+package ldclient
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+
+	th "github.com/launchdarkly/go-test-helpers/v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestClientManager() *ClientManager {
+	return NewClientManager(Config{
+		DataSource: ldcomponents.ExternalUpdatesOnly(),
+		Events:     ldcomponents.NoEvents(),
+		Logging:    ldcomponents.Logging().Loggers(sharedtest.NewTestLoggers()),
+	})
+}
+
+func TestClientManager(t *testing.T) {
+	t.Run("adds and returns clients", func(t *testing.T) {
+		manager := makeTestClientManager()
+		defer manager.Close()
+
+		client1, err := manager.AddEnvironment("sdk-key-1", time.Second)
+		require.NoError(t, err)
+		client2, err := manager.AddEnvironment("sdk-key-2", time.Second)
+		require.NoError(t, err)
+		assert.NotSame(t, client1, client2)
+
+		client, ok := manager.Client("sdk-key-1")
+		assert.True(t, ok)
+		assert.Same(t, client1, client)
+		_, ok = manager.Client("sdk-key-3")
+		assert.False(t, ok)
+		assert.Equal(t, []string{"sdk-key-1", "sdk-key-2"}, manager.SDKKeys())
+	})
+
+	t.Run("cannot add the same SDK key twice", func(t *testing.T) {
+		manager := makeTestClientManager()
+		defer manager.Close()
+
+		client1, err := manager.AddEnvironment("sdk-key", time.Second)
+		require.NoError(t, err)
+
+		_, err = manager.AddEnvironment("sdk-key", time.Second)
+		assert.Equal(t, ErrEnvironmentAlreadyAdded, err)
+		client, _ := manager.Client("sdk-key")
+		assert.Same(t, client1, client)
+	})
+
+	t.Run("client that cannot be created is not added", func(t *testing.T) {
+		manager := makeTestClientManager()
+		defer manager.Close()
+
+		client, err := manager.AddEnvironment("sdk-key\n", time.Second)
+		assert.Error(t, err)
+		assert.Nil(t, client)
+		assert.Len(t, manager.SDKKeys(), 0)
+	})
+
+	t.Run("adds client with its own configuration", func(t *testing.T) {
+		manager := makeTestClientManager()
+		defer manager.Close()
+
+		client, err := manager.AddEnvironmentWithConfig("sdk-key", Config{Offline: true}, time.Second)
+		require.NoError(t, err)
+		assert.True(t, client.IsOffline())
+	})
+
+	t.Run("removes client", func(t *testing.T) {
+		manager := makeTestClientManager()
+		defer manager.Close()
+
+		_, err := manager.AddEnvironment("sdk-key-1", time.Second)
+		require.NoError(t, err)
+		_, err = manager.AddEnvironment("sdk-key-2", time.Second)
+		require.NoError(t, err)
+
+		assert.NoError(t, manager.RemoveEnvironment("sdk-key-1"))
+		assert.NoError(t, manager.RemoveEnvironment("sdk-key-1"))
+		assert.Equal(t, []string{"sdk-key-2"}, manager.SDKKeys())
+
+		// the SDK key can be added again
+		_, err = manager.AddEnvironment("sdk-key-1", time.Second)
+		assert.NoError(t, err)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		manager := makeTestClientManager()
+		defer manager.Close()
+
+		assert.Equal(t, ClientManagerStatus{Environments: map[string]EnvironmentStatus{}, AllInitialized: true},
+			manager.Status())
+
+		_, err := manager.AddEnvironment("sdk-key", time.Second)
+		require.NoError(t, err)
+
+		status := manager.Status()
+		assert.True(t, status.AllInitialized)
+		require.Contains(t, status.Environments, "sdk-key")
+		envStatus := status.Environments["sdk-key"]
+		assert.True(t, envStatus.Initialized)
+		assert.Equal(t, interfaces.DataSourceStateValid, envStatus.DataSource.State)
+		assert.True(t, envStatus.DataStore.Available)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		manager := makeTestClientManager()
+
+		_, err := manager.AddEnvironment("sdk-key", time.Second)
+		require.NoError(t, err)
+
+		assert.NoError(t, manager.Close())
+		assert.Len(t, manager.SDKKeys(), 0)
+
+		_, err = manager.AddEnvironment("sdk-key", time.Second)
+		assert.Equal(t, ErrClientManagerClosed, err)
+	})
+}
+
+func TestClientManagerSharesHTTPTransport(t *testing.T) {
+	configurer := NewClientManager(Config{}).http
+
+	http1, err := configurer.Build(subsystems.BasicClientContext{SDKKey: "sdk-key-1"})
+	require.NoError(t, err)
+	http2, err := configurer.Build(subsystems.BasicClientContext{SDKKey: "sdk-key-2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "sdk-key-1", http1.DefaultHeaders.Get("Authorization"))
+	assert.Equal(t, "sdk-key-2", http2.DefaultHeaders.Get("Authorization"))
+	assert.Same(t, http1.CreateHTTPClient().Transport, http2.CreateHTTPClient().Transport)
+}
+
+func TestClientManagerBuildsHeadersForEachEnvironment(t *testing.T) {
+	configurer := NewClientManager(Config{HTTP: ldcomponents.HTTPConfiguration().Header("X-Custom", "value")}).http
+
+	http1, err := configurer.Build(subsystems.BasicClientContext{SDKKey: "sdk-key-1"})
+	require.NoError(t, err)
+	http2, err := configurer.Build(subsystems.BasicClientContext{
+		SDKKey:          "sdk-key-2",
+		ApplicationInfo: interfaces.ApplicationInfo{ApplicationID: "app2"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "value", http1.DefaultHeaders.Get("X-Custom"))
+	assert.Equal(t, "value", http2.DefaultHeaders.Get("X-Custom"))
+	assert.Equal(t, "", http1.DefaultHeaders.Get("X-LaunchDarkly-Tags"))
+	assert.Equal(t, "application-id/app2", http2.DefaultHeaders.Get("X-LaunchDarkly-Tags"))
+	assert.Same(t, http1.CreateHTTPClient().Transport, http2.CreateHTTPClient().Transport)
+}
+
+func TestClientManagerFlushesEventsOnSharedSchedule(t *testing.T) {
+	sender := newGatedEventSender()
+	for i := 0; i < 2; i++ {
+		sender.canSendCh <- struct{}{}
+	}
+	manager := NewClientManager(Config{
+		DataSource: ldcomponents.ExternalUpdatesOnly(),
+		Events: ldcomponents.SendEvents().
+			EventSender(mocks.SingleComponentConfigurer[ldevents.EventSender]{Instance: sender}).
+			FlushInterval(time.Millisecond * 10),
+		DiagnosticOptOut: true,
+		Logging:          ldcomponents.Logging().Loggers(sharedtest.NewTestLoggers()),
+	})
+	defer manager.Close()
+
+	for _, sdkKey := range []string{"sdk-key-1", "sdk-key-2"} {
+		client, err := manager.AddEnvironment(sdkKey, time.Second)
+		require.NoError(t, err)
+		require.NoError(t, client.Identify(evalTestUser))
+	}
+
+	th.RequireValue(t, sender.didSendCh, time.Second)
+	th.RequireValue(t, sender.didSendCh, time.Second)
+}
+
+// wrappingEventsConfigurer is a custom Config.Events component that builds its event processor with
+// SendEvents, as an application might do to decorate it.
+type wrappingEventsConfigurer struct {
+	base subsystems.ComponentConfigurer[ldevents.EventProcessor]
+}
+
+func (c wrappingEventsConfigurer) Build(context subsystems.ClientContext) (ldevents.EventProcessor, error) {
+	return c.base.Build(context)
+}
+
+// ownEventsConfigurer is a custom Config.Events component that does not use SendEvents at all.
+type ownEventsConfigurer struct {
+	sender ldevents.EventSender
+}
+
+func (c ownEventsConfigurer) Build(context subsystems.ClientContext) (ldevents.EventProcessor, error) {
+	return ldevents.NewDefaultEventProcessor(ldevents.EventsConfiguration{
+		Capacity:    1000,
+		EventSender: c.sender,
+		// This processor is not flushed by the client manager, so it needs a flush interval of its own.
+		FlushInterval:         time.Millisecond * 10,
+		Loggers:               context.GetLogging().Loggers,
+		UserKeysCapacity:      1000,
+		UserKeysFlushInterval: time.Hour,
+	}), nil
+}
+
+func TestClientManagerFlushesEventsFromCustomEventsConfigurers(t *testing.T) {
+	sender := newGatedEventSender()
+	for i := 0; i < 2; i++ {
+		sender.canSendCh <- struct{}{}
+	}
+	configurers := []subsystems.ComponentConfigurer[ldevents.EventProcessor]{
+		wrappingEventsConfigurer{base: ldcomponents.SendEvents().
+			EventSender(mocks.SingleComponentConfigurer[ldevents.EventSender]{Instance: sender}).
+			FlushInterval(time.Millisecond * 10)},
+		ownEventsConfigurer{sender: sender},
+	}
+	config := Config{
+		DataSource:       ldcomponents.ExternalUpdatesOnly(),
+		DiagnosticOptOut: true,
+		Logging:          ldcomponents.Logging().Loggers(sharedtest.NewTestLoggers()),
+	}
+	manager := NewClientManager(config)
+	defer manager.Close()
+
+	for i, configurer := range configurers {
+		config.Events = configurer
+		client, err := manager.AddEnvironmentWithConfig(fmt.Sprintf("sdk-key-%d", i), config, time.Second)
+		require.NoError(t, err)
+		require.NoError(t, client.Identify(evalTestUser))
+	}
+
+	th.RequireValue(t, sender.didSendCh, time.Second)
+	th.RequireValue(t, sender.didSendCh, time.Second)
+}
@@ -86,6 +86,21 @@ type Config struct {
 	//     config.DataStore = ldcomponents.PersistentDataStore(ldredis.DataStore())
 	DataStore subsystems.ComponentConfigurer[subsystems.DataStore]
 
+	// Enables the FDv2 data system, which replaces DataSource and DataStore.
+	//
+	// This field is not stable, and not subject to any backwards
+	// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+	//
+	// Do not use it.
+	// You have been warned.
+	//
+	// If set, DataSource and DataStore are ignored.
+	//
+	//     // example: use the FDv2 data system with a persistent store in read-write mode
+	//     config.DataSystem = ldcomponents.DataSystem().
+	//         DataStore(ldcomponents.PersistentDataStore(ldredis.DataStore()), subsystems.DataStoreModeReadWrite)
+	DataSystem subsystems.ComponentConfigurer[subsystems.DataSystemConfiguration]
+
 	// Set to true to opt out of sending diagnostic events.
 	//
 	// Unless DiagnosticOptOut is set to true, the client will send some diagnostics data to the LaunchDarkly
@@ -95,6 +110,16 @@ type Config struct {
 	// as dropped events.
 	DiagnosticOptOut bool
 
+	// Enables caching of flag evaluation results.
+	//
+	// If this field is unset/nil, there is no cache: the SDK evaluates a flag's rules every time the flag is
+	// evaluated. To enable it, set it to ldcomponents.InMemoryEvaluationCache(), or to a custom
+	// implementation of subsystems.EvaluationCache.
+	//
+	//     // example: cache up to 50000 results for up to 5 minutes each
+	//     config.EvaluationCache = ldcomponents.InMemoryEvaluationCache().Capacity(50000).TTL(5 * time.Minute)
+	EvaluationCache subsystems.ComponentConfigurer[subsystems.EvaluationCache]
+
 	// Sets the SDK's behavior regarding analytics events.
 	//
 	// The interface type for this field allows you to set it to either:
@@ -0,0 +1,334 @@
+package ldclient
+
+import (
+	"fmt"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldeval "github.com/launchdarkly/go-server-sdk-evaluation/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datasystem"
+)
+
+// EvaluationExplanation is the result of [LDClient.ExplainVariation]. Along with the result of the
+// evaluation, it describes every prerequisite, rule, clause, and segment that was involved in it.
+type EvaluationExplanation struct {
+	// FlagKey is the key of the flag that was evaluated.
+	FlagKey string
+
+	// FlagVersion is the version of the flag, or an empty value if the flag was not found.
+	FlagVersion ldvalue.OptionalInt
+
+	// FlagOn is true if the flag's targeting was turned on.
+	FlagOn bool
+
+	// Detail is the result of the evaluation, exactly as [LDClient.JSONVariationDetail] would return it.
+	Detail ldreason.EvaluationDetail
+
+	// Prerequisites describes each prerequisite flag that was evaluated, including prerequisites of
+	// prerequisites, in the order that they were evaluated. Prerequisites are checked one at a time and
+	// evaluation stops at the first one that fails, so later prerequisites do not appear here; nor does a
+	// prerequisite flag that does not exist, although the reason in Detail will identify it.
+	Prerequisites []PrerequisiteExplanation
+
+	// Rules describes each of the flag's rules, in order.
+	Rules []RuleExplanation
+}
+
+// PrerequisiteExplanation describes the evaluation of a prerequisite flag, as part of an
+// [EvaluationExplanation].
+type PrerequisiteExplanation struct {
+	// FlagKey is the key of the prerequisite flag.
+	FlagKey string
+
+	// PrerequisiteOf is the key of the flag that has this prerequisite. This is the key of the flag
+	// being explained, unless the prerequisite is a prerequisite of another prerequisite.
+	PrerequisiteOf string
+
+	// FlagVersion is the version of the prerequisite flag.
+	FlagVersion int
+
+	// FlagOn is true if the prerequisite flag's targeting was turned on. A prerequisite that is off is
+	// never met, regardless of its off variation.
+	FlagOn bool
+
+	// RequiredVariation is the variation index that the prerequisite flag must return.
+	RequiredVariation int
+
+	// Detail is the result of evaluating the prerequisite flag.
+	Detail ldreason.EvaluationDetail
+
+	// Met is true if the prerequisite was satisfied.
+	Met bool
+}
+
+// RuleExplanation describes one of a flag's rules, as part of an [EvaluationExplanation].
+type RuleExplanation struct {
+	// Index is the position of the rule in the flag's list of rules, starting at 0.
+	Index int
+
+	// ID is the rule's unique identifier.
+	ID string
+
+	// Reached is true if the evaluation got as far as this rule: that is, the flag was on, its
+	// prerequisites were met, the context did not match any individual targets, and no earlier rule
+	// matched. If it is false, the rule had no effect on the result, but its clauses are still tested so
+	// that it is possible to see whether it would have matched.
+	Reached bool
+
+	// Matched is true if all of the rule's clauses matched the context.
+	Matched bool
+
+	// Clauses describes each of the rule's clauses, in order.
+	Clauses []ClauseExplanation
+}
+
+// ClauseExplanation describes one clause of a flag rule, as part of an [EvaluationExplanation].
+//
+// Every clause is tested on its own, so this shows all of the clauses in a rule that failed to match, even
+// though the evaluator stops at the first one.
+type ClauseExplanation struct {
+	// ContextKind is the context kind that the clause applies to. This is [ldcontext.DefaultKind] if the
+	// clause does not specify a kind.
+	ContextKind ldcontext.Kind
+
+	// Attribute is the attribute reference that the clause tests.
+	Attribute string
+
+	// Op is the clause operator.
+	Op ldmodel.Operator
+
+	// Values are the values that the clause tests against.
+	Values []ldvalue.Value
+
+	// Negate is true if the result of the test is inverted.
+	Negate bool
+
+	// Matched is true if the clause matched the context, taking Negate into account.
+	Matched bool
+
+	// ErrorKind is set if the clause could not be tested, for instance because it has an invalid
+	// attribute reference; in that case the evaluation of the flag fails if it reaches this clause.
+	ErrorKind ldreason.EvalErrorKind
+
+	// Segments describes each of the segments referenced by a clause with the operator
+	// [ldmodel.OperatorSegmentMatch]. It is nil for all other operators.
+	Segments []SegmentExplanation
+}
+
+// SegmentExplanation describes a segment referenced by a rule clause, as part of an
+// [EvaluationExplanation].
+type SegmentExplanation struct {
+	// Key is the segment key.
+	Key string
+
+	// Found is false if there is no segment with this key. The context never matches a segment that
+	// does not exist.
+	Found bool
+
+	// Version is the version of the segment, or zero if it was not found.
+	Version int
+
+	// Unbounded is true if this is a Big Segment.
+	Unbounded bool
+
+	// Matched is true if the context is a member of the segment. This is not affected by the Negate
+	// property of the clause.
+	Matched bool
+
+	// BigSegmentsStatus describes the state of the Big Segment store when membership was checked. It is
+	// empty if no Big Segment was involved, either in this segment or in any segment that it references.
+	BigSegmentsStatus ldreason.BigSegmentsStatus
+
+	// ErrorKind is set if membership could not be determined, for instance because of a circular
+	// reference between segments.
+	ErrorKind ldreason.EvalErrorKind
+}
+
+// ExplainVariation evaluates a feature flag in the same way as [LDClient.JSONVariationDetail], and also
+// returns a description of how the result was reached: the outcome of each prerequisite, and of each rule,
+// clause, and segment in the flag.
+//
+// This is meant for diagnosing unexpected results, and is much slower than a normal evaluation, since each
+// clause and segment is evaluated separately. It does not generate analytics events, run hooks, or use the
+// evaluation cache.
+//
+// The returned error is the same as for [LDClient.JSONVariationDetail].
+func (client *LDClient) ExplainVariation(
+	key string,
+	context ldcontext.Context,
+	defaultVal ldvalue.Value,
+) (EvaluationExplanation, error) {
+	explanation := EvaluationExplanation{FlagKey: key}
+
+	if err := context.Err(); err != nil {
+		explanation.Detail = newEvaluationError(defaultVal, ldreason.EvalErrorUserNotSpecified)
+		return explanation, err
+	}
+	if client.IsOffline() {
+		explanation.Detail = newEvaluationError(defaultVal, ldreason.EvalErrorClientNotReady)
+		return explanation, nil
+	}
+	if client.dataSystem.DataAvailability() == datasystem.Defaults {
+		explanation.Detail = newEvaluationError(defaultVal, ldreason.EvalErrorClientNotReady)
+		return explanation, ErrClientNotInitialized
+	}
+
+	store := client.dataSystem.Store()
+	itemDesc, err := store.Get(datakinds.Features, key)
+	if err != nil {
+		explanation.Detail = newEvaluationError(defaultVal, ldreason.EvalErrorException)
+		return explanation, err
+	}
+	flag, ok := itemDesc.Item.(*ldmodel.FeatureFlag)
+	if !ok || flag == nil {
+		explanation.Detail = newEvaluationError(defaultVal, ldreason.EvalErrorFlagNotFound)
+		return explanation,
+			fmt.Errorf("unknown feature key: %s. Verify that this feature key exists. Returning default value", key)
+	}
+	explanation.FlagVersion = ldvalue.NewOptionalInt(flag.Version)
+	explanation.FlagOn = flag.On
+
+	result := client.evaluator.Evaluate(flag, context, func(event ldeval.PrerequisiteFlagEvent) {
+		explanation.Prerequisites = append(explanation.Prerequisites,
+			client.explainPrerequisite(flag, event))
+	})
+	explanation.Detail = result.Detail
+	if explanation.Detail.IsDefaultValue() {
+		explanation.Detail.Value = defaultVal
+	}
+
+	// Rules are only reached if the flag is on, its prerequisites are met, and no individual target matched,
+	// in which case the result is either a rule match, the fallthrough, or an error in a rule.
+	reasonKind := result.Detail.Reason.GetKind()
+	reachable := reasonKind == ldreason.EvalReasonRuleMatch || reasonKind == ldreason.EvalReasonFallthrough ||
+		(reasonKind == ldreason.EvalReasonError && flag.On &&
+			countMetPrerequisitesOf(flag.Key, explanation.Prerequisites) == len(flag.Prerequisites))
+	for i, rule := range flag.Rules {
+		ruleExplanation := RuleExplanation{Index: i, ID: rule.ID, Reached: reachable, Matched: true}
+		hasError := false
+		for _, clause := range rule.Clauses {
+			clauseExplanation := client.explainClause(flag.Key, clause, context)
+			ruleExplanation.Matched = ruleExplanation.Matched && clauseExplanation.Matched
+			hasError = hasError || clauseExplanation.ErrorKind != ""
+			ruleExplanation.Clauses = append(ruleExplanation.Clauses, clauseExplanation)
+		}
+		ruleExplanation.Matched = ruleExplanation.Matched && !hasError
+		if ruleExplanation.Matched || hasError {
+			reachable = false
+		}
+		explanation.Rules = append(explanation.Rules, ruleExplanation)
+	}
+
+	return explanation, nil
+}
+
+// countMetPrerequisitesOf counts the prerequisites that were met for one flag, ignoring any prerequisites of
+// those prerequisites.
+func countMetPrerequisitesOf(flagKey string, prerequisites []PrerequisiteExplanation) int {
+	count := 0
+	for _, p := range prerequisites {
+		if p.PrerequisiteOf == flagKey && p.Met {
+			count++
+		}
+	}
+	return count
+}
+
+func (client *LDClient) explainPrerequisite(
+	flag *ldmodel.FeatureFlag,
+	event ldeval.PrerequisiteFlagEvent,
+) PrerequisiteExplanation {
+	p := PrerequisiteExplanation{
+		FlagKey:           event.PrerequisiteFlag.Key,
+		PrerequisiteOf:    event.TargetFlagKey,
+		FlagVersion:       event.PrerequisiteFlag.Version,
+		FlagOn:            event.PrerequisiteFlag.On,
+		RequiredVariation: -1,
+		Detail:            event.PrerequisiteResult.Detail,
+	}
+	targetFlag := flag
+	if event.TargetFlagKey != flag.Key {
+		targetFlag = nil
+		if item, err := client.dataSystem.Store().Get(datakinds.Features, event.TargetFlagKey); err == nil {
+			targetFlag, _ = item.Item.(*ldmodel.FeatureFlag)
+		}
+	}
+	if targetFlag != nil {
+		for _, prereq := range targetFlag.Prerequisites {
+			if prereq.Key == p.FlagKey {
+				p.RequiredVariation = prereq.Variation
+				break
+			}
+		}
+	}
+	p.Met = p.FlagOn && p.Detail.VariationIndex.IsDefined() && p.Detail.VariationIndex.IntValue() == p.RequiredVariation
+	return p
+}
+
+func (client *LDClient) explainClause(
+	flagKey string,
+	clause ldmodel.Clause,
+	context ldcontext.Context,
+) ClauseExplanation {
+	c := ClauseExplanation{
+		ContextKind: clause.ContextKind,
+		Attribute:   clause.Attribute.String(),
+		Op:          clause.Op,
+		Values:      clause.Values,
+		Negate:      clause.Negate,
+	}
+	if c.ContextKind == "" {
+		c.ContextKind = ldcontext.DefaultKind
+	}
+	result := client.evaluateClause(flagKey, clause, context)
+	c.Matched = result.Detail.Value.BoolValue()
+	c.ErrorKind = result.Detail.Reason.GetErrorKind()
+
+	if clause.Op == ldmodel.OperatorSegmentMatch {
+		c.Segments = make([]SegmentExplanation, 0, len(clause.Values))
+		for _, value := range clause.Values {
+			c.Segments = append(c.Segments, client.explainSegment(flagKey, value.StringValue(), context))
+		}
+	}
+	return c
+}
+
+func (client *LDClient) explainSegment(flagKey, segmentKey string, context ldcontext.Context) SegmentExplanation {
+	s := SegmentExplanation{Key: segmentKey}
+	if item, err := client.dataSystem.Store().Get(datakinds.Segments, segmentKey); err == nil {
+		if segment, ok := item.Item.(*ldmodel.Segment); ok && segment != nil {
+			s.Found = true
+			s.Version = segment.Version
+			s.Unbounded = segment.Unbounded
+		}
+	}
+	result := client.evaluateClause(flagKey, ldmodel.Clause{
+		Op:     ldmodel.OperatorSegmentMatch,
+		Values: []ldvalue.Value{ldvalue.String(segmentKey)},
+	}, context)
+	s.Matched = result.Detail.Value.BoolValue()
+	s.BigSegmentsStatus = result.Detail.Reason.GetBigSegmentsStatus()
+	s.ErrorKind = result.Detail.Reason.GetErrorKind()
+	return s
+}
+
+// evaluateClause tests a single clause by evaluating a flag that has only that clause, so that it gets
+// exactly the same logic as a real evaluation, including segment and Big Segment lookups. The result
+// value is true if the clause matched.
+func (client *LDClient) evaluateClause(flagKey string, clause ldmodel.Clause, context ldcontext.Context) ldeval.Result {
+	// The clause is copied along with its preprocessed data, so this flag does not need to be preprocessed.
+	probe := ldmodel.FeatureFlag{
+		Key:         flagKey,
+		On:          true,
+		Variations:  []ldvalue.Value{ldvalue.Bool(false), ldvalue.Bool(true)},
+		Fallthrough: ldmodel.VariationOrRollout{Variation: ldvalue.NewOptionalInt(0)},
+		Rules: []ldmodel.FlagRule{{
+			VariationOrRollout: ldmodel.VariationOrRollout{Variation: ldvalue.NewOptionalInt(1)},
+			Clauses:            []ldmodel.Clause{clause},
+		}},
+	}
+	return client.evaluator.Evaluate(&probe, context, nil)
+}
@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -63,6 +64,20 @@ type DataSourceStatusProvider interface {
 	// one that was previously returned by AddStatusListener(); otherwise, the method has no effect.
 	RemoveStatusListener(listener <-chan DataSourceStatus)
 
+	// AddStatusListenerFunc subscribes for notifications of status changes by calling a function, and
+	// returns a function that unsubscribes. If ctx is not nil, the subscription also ends when ctx is done.
+	//
+	// The listener is called on its own goroutine, one value at a time in the order they were sent. It does
+	// not block the SDK even if the listener is slow: undelivered values are kept in a buffer, and discarded
+	// according to the options if the buffer is full. See ListenerOptions.
+	AddStatusListenerFunc(ctx context.Context, listener func(DataSourceStatus), options ListenerOptions) func()
+
+	// AddStatusListenerCtx is the same as AddStatusListener, except that the subscription ends (and the
+	// channel is closed) when ctx is done, and it does not block the SDK if the caller does not consume
+	// values from the channel: undelivered values are kept in a buffer, and discarded according to the
+	// options if the buffer is full. See ListenerOptions.
+	AddStatusListenerCtx(ctx context.Context, options ListenerOptions) <-chan DataSourceStatus
+
 	// WaitFor is a synchronous method for waiting for a desired connection state.
 	//
 	// If the current state is already desiredState when this method is called, it immediately returns.
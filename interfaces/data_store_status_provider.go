@@ -1,5 +1,7 @@
 package interfaces
 
+import "context"
+
 // DataStoreStatusProvider is an interface for querying the status of a persistent data store.
 //
 // An implementation of this interface is returned by
@@ -60,6 +62,20 @@ type DataStoreStatusProvider interface {
 	// RemoveStatusListener unsubscribes from notifications of status changes. The specified channel must be
 	// one that was previously returned by AddStatusListener(); otherwise, the method has no effect.
 	RemoveStatusListener(<-chan DataStoreStatus)
+
+	// AddStatusListenerFunc subscribes for notifications of status changes by calling a function, and
+	// returns a function that unsubscribes. If ctx is not nil, the subscription also ends when ctx is done.
+	//
+	// The listener is called on its own goroutine, one value at a time in the order they were sent. It does
+	// not block the SDK even if the listener is slow: undelivered values are kept in a buffer, and discarded
+	// according to the options if the buffer is full. See ListenerOptions.
+	AddStatusListenerFunc(ctx context.Context, listener func(DataStoreStatus), options ListenerOptions) func()
+
+	// AddStatusListenerCtx is the same as AddStatusListener, except that the subscription ends (and the
+	// channel is closed) when ctx is done, and it does not block the SDK if the caller does not consume
+	// values from the channel: undelivered values are kept in a buffer, and discarded according to the
+	// options if the buffer is full. See ListenerOptions.
+	AddStatusListenerCtx(ctx context.Context, options ListenerOptions) <-chan DataStoreStatus
 }
 
 // DataStoreStatus contains information about the status of a data store, provided by [DataStoreStatusProvider].
@@ -1,6 +1,9 @@
 package interfaces
 
 import (
+	"context"
+	"time"
+
 	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
 )
@@ -35,6 +38,21 @@ type FlagTracker interface {
 	// must be one that was previously returned by AddFlagChangeListener(); otherwise, the method has no effect.
 	RemoveFlagChangeListener(listener <-chan FlagChangeEvent)
 
+	// AddFlagChangeListenerFunc subscribes for notifications of feature flag changes in general, like
+	// AddFlagChangeListener, by calling a function. It returns a function that unsubscribes. If ctx is not
+	// nil, the subscription also ends when ctx is done.
+	//
+	// The listener is called on its own goroutine, one value at a time in the order they were sent. It does
+	// not block the SDK even if the listener is slow: undelivered values are kept in a buffer, and discarded
+	// according to the options if the buffer is full. See ListenerOptions.
+	AddFlagChangeListenerFunc(ctx context.Context, listener func(FlagChangeEvent), options ListenerOptions) func()
+
+	// AddFlagChangeListenerCtx is the same as AddFlagChangeListener, except that the subscription ends (and
+	// the channel is closed) when ctx is done, and it does not block the SDK if the caller does not consume
+	// values from the channel: undelivered values are kept in a buffer, and discarded according to the
+	// options if the buffer is full. See ListenerOptions.
+	AddFlagChangeListenerCtx(ctx context.Context, options ListenerOptions) <-chan FlagChangeEvent
+
 	// AddFlagValueChangeListener subscribes for notifications of changes in a specific feature flag's value
 	// for a specific set of context properties.
 	//
@@ -60,6 +78,30 @@ type FlagTracker interface {
 	// specified channel must be one that was previously returned by AddFlagValueChangeListener(); otherwise,
 	// the method has no effect.
 	RemoveFlagValueChangeListener(listener <-chan FlagValueChangeEvent)
+
+	// AddFlagValuesChangeListener subscribes for notifications of changes in the values of a set of feature
+	// flags for a set of evaluation contexts.
+	//
+	// This is similar to AddFlagValueChangeListener, but a single subscription can cover any number of flags
+	// and contexts. When you call this method, it first evaluates the flags for each context before
+	// returning. Then, whenever it receives a flag change notification (using the same mechanism as
+	// AddFlagChangeListener, which only reports flags that are affected by an update), it re-evaluates only
+	// the affected flags that are being watched, and pushes a FlagValuesChangeBatch to the channel listing
+	// every flag value that has changed for every context. See FlagValuesChangeListenerConfig for how
+	// notifications are coalesced into batches.
+	//
+	// Unlike AddFlagValueChangeListener, these evaluations do not generate analytics events or run hooks. If
+	// a flag does not exist or cannot be evaluated, its value is reported as ldvalue.Null().
+	//
+	// The listener does not block the SDK if the caller does not consume values from the channel: changes
+	// that are found before the caller receives the next batch are added to that batch, so that each flag and
+	// context appears in it at most once, with the value that the caller last received as OldValue.
+	AddFlagValuesChangeListener(config FlagValuesChangeListenerConfig) <-chan FlagValuesChangeBatch
+
+	// RemoveFlagValuesChangeListener unsubscribes from notifications of feature flag value changes. The
+	// specified channel must be one that was previously returned by AddFlagValuesChangeListener(); otherwise,
+	// the method has no effect.
+	RemoveFlagValuesChangeListener(listener <-chan FlagValuesChangeBatch)
 }
 
 // FlagChangeEvent is a parameter type used with FlagTracker.AddFlagChangeListener().
@@ -70,10 +112,72 @@ type FlagChangeEvent struct {
 	//
 	// The specified flag may have been modified directly, or this may be an indirect change due to a change
 	// in some other flag that is a prerequisite for this flag, or a user segment that is referenced in the
-	// flag's rules.
+	// flag's rules. Cause indicates which it is.
 	Key string
+
+	// Kind indicates whether the flag was added, updated, or deleted. If the change is indirect (Cause is
+	// not FlagChangeCauseDirect), Kind is always FlagChangeKindUpdated, since the flag itself still exists
+	// but may now behave differently.
+	Kind FlagChangeKind
+
+	// Cause indicates whether the flag itself was changed, or whether the event is due to a change in a
+	// prerequisite flag or a segment that the flag depends on.
+	//
+	// Events sent by older SDK code paths, or constructed by application code, may have an empty Cause;
+	// this should be treated as FlagChangeCauseDirect.
+	Cause FlagChangeCause
+
+	// CauseKey is the key of the prerequisite flag or segment that was changed, if Cause is
+	// FlagChangeCausePrerequisite or FlagChangeCauseSegment. If the flag depends on that item indirectly
+	// (for instance, through a prerequisite flag that references the segment), this is the key of the item
+	// that was actually changed, not of the intermediate flag. It is empty for a direct change.
+	CauseKey string
+
+	// OldVersion is the version of the flag before the change, or an undefined value if the flag did not
+	// previously exist or its previous version is not known.
+	OldVersion ldvalue.OptionalInt
+
+	// NewVersion is the version of the flag after the change, or an undefined value if the flag was deleted.
+	// For an indirect change, this is the same as OldVersion.
+	NewVersion ldvalue.OptionalInt
+
+	// Source is the name of the data source that provided the change, such as "StreamingDataSource", or the
+	// name of an initializer in the FDv2 data system. It is empty if the data source does not have a name.
+	// A custom data source can provide one by implementing a method with the signature Name() string.
+	Source string
 }
 
+// FlagChangeKind describes the type of change in a FlagChangeEvent.
+type FlagChangeKind string
+
+const (
+	// FlagChangeKindAdded means that the flag did not previously exist.
+	FlagChangeKindAdded FlagChangeKind = "ADDED"
+
+	// FlagChangeKindUpdated means that the flag's configuration changed, or that it may behave differently
+	// because of a change to something it depends on.
+	FlagChangeKindUpdated FlagChangeKind = "UPDATED"
+
+	// FlagChangeKindDeleted means that the flag was deleted.
+	FlagChangeKindDeleted FlagChangeKind = "DELETED"
+)
+
+// FlagChangeCause describes why a FlagChangeEvent was sent.
+type FlagChangeCause string
+
+const (
+	// FlagChangeCauseDirect means that the flag itself was changed.
+	FlagChangeCauseDirect FlagChangeCause = "DIRECT"
+
+	// FlagChangeCausePrerequisite means that a flag which this flag depends on as a prerequisite (directly
+	// or indirectly) was changed.
+	FlagChangeCausePrerequisite FlagChangeCause = "PREREQUISITE"
+
+	// FlagChangeCauseSegment means that a segment which this flag references (directly or through a
+	// prerequisite flag or another segment) was changed.
+	FlagChangeCauseSegment FlagChangeCause = "SEGMENT"
+)
+
 // FlagValueChangeEvent is a parameter type used with FlagTracker.AddFlagValueChangeListener().
 //
 // This is not an analytics event to be sent to LaunchDarkly; it is a notification to the application.
@@ -103,3 +207,47 @@ type FlagValueChangeEvent struct {
 	// the default with AddFlagValueChangeListener().
 	NewValue ldvalue.Value
 }
+
+// FlagValuesChangeListenerConfig is a parameter type used with FlagTracker.AddFlagValuesChangeListener().
+type FlagValuesChangeListenerConfig struct {
+	// FlagKeys is the set of feature flags to watch. If it is empty, all flags are watched, including any
+	// that are added later.
+	FlagKeys []string
+
+	// Contexts is the set of evaluation contexts to evaluate the flags for. To watch a different set of
+	// contexts, remove the listener and add a new one.
+	Contexts []ldcontext.Context
+
+	// BatchInterval is how long to wait after a flag change notification, to collect any further
+	// notifications, before re-evaluating flags and delivering a batch. This is useful if many flags are
+	// updated in quick succession. If it is zero, the listener only combines notifications that have already
+	// been received.
+	BatchInterval time.Duration
+}
+
+// FlagValuesChangeBatch is a parameter type used with FlagTracker.AddFlagValuesChangeListener().
+//
+// This is not an analytics event to be sent to LaunchDarkly; it is a notification to the application.
+type FlagValuesChangeBatch struct {
+	// Changes contains one item for each combination of flag and context whose value has changed. It is
+	// never empty.
+	Changes []ContextFlagValueChangeEvent
+}
+
+// ContextFlagValueChangeEvent describes a change in a feature flag's value for one of the evaluation
+// contexts in a FlagValuesChangeBatch.
+type ContextFlagValueChangeEvent struct {
+	// Context is the evaluation context whose value has changed.
+	Context ldcontext.Context
+
+	// Key is the key of the feature flag whose value has changed.
+	Key string
+
+	// OldValue is the last known value of the flag for this context prior to the update, or ldvalue.Null()
+	// if the flag did not exist or could not be evaluated.
+	OldValue ldvalue.Value
+
+	// NewValue is the new value of the flag for this context, or ldvalue.Null() if the flag was deleted or
+	// could not be evaluated.
+	NewValue ldvalue.Value
+}
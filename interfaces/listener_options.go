@@ -0,0 +1,37 @@
+package interfaces
+
+// DefaultListenerBufferSize is the number of undelivered values that a listener created with one of the
+// ListenerFunc or ListenerCtx methods can hold, if ListenerOptions.BufferSize is not set.
+const DefaultListenerBufferSize = 10
+
+// ListenerOverflowPolicy determines what happens to a listener's undelivered values when it is not keeping
+// up with the values being sent to it. It is used in ListenerOptions.
+type ListenerOverflowPolicy int
+
+const (
+	// ListenerOverflowDropOldest means that if the listener's buffer is full, the oldest undelivered value is
+	// discarded to make room for the new one. This is the default.
+	ListenerOverflowDropOldest ListenerOverflowPolicy = iota
+
+	// ListenerOverflowKeepLatest means that the listener only ever has one undelivered value: a new value
+	// replaces any value that has not yet been delivered. This is appropriate for status listeners that only
+	// care about the current state. ListenerOptions.BufferSize is ignored.
+	ListenerOverflowKeepLatest
+)
+
+// ListenerOptions is a parameter type for the methods that add listeners with a callback function
+// (such as DataSourceStatusProvider.AddStatusListenerFunc) or a context.Context (such as
+// DataSourceStatusProvider.AddStatusListenerCtx). The zero value uses the default settings.
+//
+// Unlike listeners that are added with the original channel-based methods (such as
+// DataSourceStatusProvider.AddStatusListener), these listeners never block the SDK. Each one has its own
+// buffer and its own goroutine for delivering values in order; if the listener does not keep up, values are
+// discarded according to OverflowPolicy.
+type ListenerOptions struct {
+	// BufferSize is the maximum number of undelivered values. If it is zero or negative,
+	// DefaultListenerBufferSize is used.
+	BufferSize int
+
+	// OverflowPolicy determines which values are discarded when the buffer is full.
+	OverflowPolicy ListenerOverflowPolicy
+}
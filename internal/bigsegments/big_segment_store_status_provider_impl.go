@@ -1,6 +1,8 @@
 package bigsegments
 
 import (
+	"context"
+
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
 	"github.com/launchdarkly/go-server-sdk/v7/internal"
 )
@@ -45,3 +47,18 @@ func (b *bigSegmentStoreStatusProviderImpl) RemoveStatusListener(
 ) {
 	b.broadcaster.RemoveListener(ch)
 }
+
+func (b *bigSegmentStoreStatusProviderImpl) AddStatusListenerFunc(
+	ctx context.Context,
+	listener func(interfaces.BigSegmentStoreStatus),
+	options interfaces.ListenerOptions,
+) func() {
+	return b.broadcaster.AddCallback(ctx, listener, options)
+}
+
+func (b *bigSegmentStoreStatusProviderImpl) AddStatusListenerCtx(
+	ctx context.Context,
+	options interfaces.ListenerOptions,
+) <-chan interfaces.BigSegmentStoreStatus {
+	return b.broadcaster.AddListenerCtx(ctx, options)
+}
@@ -1,8 +1,11 @@
 package internal
 
 import (
+	"context"
 	"sync"
 
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+
 	"golang.org/x/exp/slices"
 )
 
@@ -12,14 +15,19 @@ import (
 // that channel, and closes the sending end of it; Broadcast sends a value to all of the subscribed channels
 // (if any); and Close unsubscribes and closes all existing channels.
 
+// AddCallback and AddListenerCtx provide an alternative model in which Broadcast never blocks: each such
+// subscriber has its own bounded queue and its own goroutine for delivering values from it, and values are
+// discarded according to the subscriber's ListenerOverflowPolicy if the queue is full.
+
 // Arbitrary buffer size to make it less likely that we'll block when broadcasting to channels. It is still
 // the consumer's responsibility to make sure they're reading the channel.
 const subscriberChannelBufferLength = 10
 
 // Broadcaster is our generalized implementation of broadcasters.
 type Broadcaster[V any] struct {
-	subscribers []channelPair[V]
-	lock        sync.RWMutex
+	subscribers      []channelPair[V]
+	asyncSubscribers []*asyncSubscriber[V]
+	lock             sync.RWMutex
 }
 
 // We need to keep track of both the channel we use for sending (stored as a reflect.Value, because Value
@@ -62,17 +70,85 @@ func (b *Broadcaster[V]) RemoveListener(ch <-chan V) {
 	})
 }
 
+// AddCallback adds a subscriber that receives values by calling a function on its own goroutine, and
+// returns a function for removing it. If ctx is non-nil, the subscriber is also removed when ctx is done.
+func (b *Broadcaster[V]) AddCallback(
+	ctx context.Context,
+	callback func(V),
+	options interfaces.ListenerOptions,
+) func() {
+	return b.addCallback(ctx, callback, options, nil)
+}
+
+// addCallback is the same as AddCallback, but also calls onClose, if it is non-nil, on the subscriber's
+// goroutine once the subscriber has been removed or the Broadcaster has been closed.
+func (b *Broadcaster[V]) addCallback(
+	ctx context.Context,
+	callback func(V),
+	options interfaces.ListenerOptions,
+	onClose func(),
+) func() {
+	sub := newAsyncSubscriber[V](options, onClose)
+	sub.deliver = callback
+	b.addAsyncSubscriber(ctx, sub)
+	return func() { b.removeAsyncSubscriber(sub) }
+}
+
+// AddListenerCtx adds a subscriber that receives values on a channel, like AddListener, except that it is
+// removed (and the channel is closed) when ctx is done, and it never blocks Broadcast.
+func (b *Broadcaster[V]) AddListenerCtx(ctx context.Context, options interfaces.ListenerOptions) <-chan V {
+	ch := make(chan V)
+	sub := newAsyncSubscriber[V](options, func() { close(ch) })
+	sub.deliver = func(value V) {
+		select {
+		case ch <- value:
+		case <-sub.closeCh:
+		}
+	}
+	b.addAsyncSubscriber(ctx, sub)
+	return ch
+}
+
+func (b *Broadcaster[V]) addAsyncSubscriber(ctx context.Context, sub *asyncSubscriber[V]) {
+	b.lock.Lock()
+	b.asyncSubscribers = append(b.asyncSubscribers, sub)
+	b.lock.Unlock()
+	go sub.run()
+	if ctx != nil && ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				b.removeAsyncSubscriber(sub)
+			case <-sub.closeCh:
+			}
+		}()
+	}
+}
+
+func (b *Broadcaster[V]) removeAsyncSubscriber(sub *asyncSubscriber[V]) {
+	b.lock.Lock()
+	b.asyncSubscribers = slices.DeleteFunc(b.asyncSubscribers, func(s *asyncSubscriber[V]) bool {
+		return s == sub
+	})
+	b.lock.Unlock()
+	sub.close()
+}
+
 // HasListeners returns true if there are any current subscribers.
 func (b *Broadcaster[V]) HasListeners() bool {
 	b.lock.RLock()
 	defer b.lock.RUnlock()
-	return len(b.subscribers) > 0
+	return len(b.subscribers) > 0 || len(b.asyncSubscribers) > 0
 }
 
 // Broadcast broadcasts a value to all current subscribers.
 func (b *Broadcaster[V]) Broadcast(value V) {
 	b.lock.RLock()
 	defer b.lock.RUnlock()
+	// The async subscribers go first, since sending to the channel subscribers may block.
+	for _, sub := range b.asyncSubscribers {
+		sub.offer(value)
+	}
 	for _, ch := range b.subscribers {
 		ch.sendCh <- value
 	}
@@ -86,4 +162,94 @@ func (b *Broadcaster[V]) Close() {
 		close(s.sendCh)
 	}
 	b.subscribers = nil
+	for _, sub := range b.asyncSubscribers {
+		sub.close()
+	}
+	b.asyncSubscribers = nil
+}
+
+type asyncSubscriber[V any] struct {
+	deliver    func(V)
+	onClose    func()
+	bufferSize int
+	keepLatest bool
+	queue      []V
+	lock       sync.Mutex
+	wakeCh     chan struct{}
+	closeCh    chan struct{}
+	closeOnce  sync.Once
+}
+
+func newAsyncSubscriber[V any](options interfaces.ListenerOptions, onClose func()) *asyncSubscriber[V] {
+	bufferSize := options.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = interfaces.DefaultListenerBufferSize
+	}
+	return &asyncSubscriber[V]{
+		onClose:    onClose,
+		bufferSize: bufferSize,
+		keepLatest: options.OverflowPolicy == interfaces.ListenerOverflowKeepLatest,
+		wakeCh:     make(chan struct{}, 1),
+		closeCh:    make(chan struct{}),
+	}
+}
+
+func (s *asyncSubscriber[V]) offer(value V) {
+	s.lock.Lock()
+	switch {
+	case s.keepLatest:
+		s.queue = append(s.queue[:0], value)
+	case len(s.queue) >= s.bufferSize:
+		copy(s.queue, s.queue[1:])
+		s.queue[len(s.queue)-1] = value
+	default:
+		s.queue = append(s.queue, value)
+	}
+	s.lock.Unlock()
+	select {
+	case s.wakeCh <- struct{}{}:
+	default: // already signaled
+	}
+}
+
+func (s *asyncSubscriber[V]) next() (V, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var value V
+	if len(s.queue) == 0 {
+		return value, false
+	}
+	value = s.queue[0]
+	copy(s.queue, s.queue[1:])
+	s.queue = s.queue[:len(s.queue)-1]
+	return value, true
+}
+
+func (s *asyncSubscriber[V]) run() {
+	if s.onClose != nil {
+		defer s.onClose()
+	}
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-s.wakeCh:
+		}
+		for {
+			value, ok := s.next()
+			if !ok {
+				break
+			}
+			select {
+			case <-s.closeCh:
+				return
+			default:
+			}
+			s.deliver(value)
+		}
+	}
+}
+
+func (s *asyncSubscriber[V]) close() {
+	s.closeOnce.Do(func() { close(s.closeCh) })
 }
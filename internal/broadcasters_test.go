@@ -1,11 +1,14 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+
 	th "github.com/launchdarkly/go-test-helpers/v3"
 
 	"github.com/stretchr/testify/assert"
@@ -96,6 +99,8 @@ func TestBroadcasterDataRace(t *testing.T) {
 		func() { b.Close() },
 		func() { b.HasListeners() },
 		func() { b.RemoveListener(nil) },
+		func() { b.AddCallback(nil, func(string) {}, interfaces.ListenerOptions{})() },
+		func() { b.AddListenerCtx(context.Background(), interfaces.ListenerOptions{}) },
 	} {
 		const concurrentRoutinesWithSelf = 2
 		// Run a method concurrently with itself to detect data races. These methods will also be
@@ -111,3 +116,141 @@ func TestBroadcasterDataRace(t *testing.T) {
 	}
 	waitGroup.Wait()
 }
+
+func TestBroadcasterCallback(t *testing.T) {
+	timeout := time.Second
+
+	t.Run("delivers values in order", func(t *testing.T) {
+		b := NewBroadcaster[string]()
+		defer b.Close()
+		received := make(chan string, 10)
+		unsubscribe := b.AddCallback(nil, func(value string) { received <- value }, interfaces.ListenerOptions{})
+		defer unsubscribe()
+
+		assert.True(t, b.HasListeners())
+		b.Broadcast("a")
+		b.Broadcast("b")
+		assert.Equal(t, "a", th.RequireValue(t, received, timeout))
+		assert.Equal(t, "b", th.RequireValue(t, received, timeout))
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		b := NewBroadcaster[string]()
+		defer b.Close()
+		received := make(chan string, 10)
+		unsubscribe := b.AddCallback(nil, func(value string) { received <- value }, interfaces.ListenerOptions{})
+
+		unsubscribe()
+		assert.False(t, b.HasListeners())
+		b.Broadcast("a")
+		th.AssertNoMoreValues(t, received, time.Millisecond*50)
+		unsubscribe() // no effect if called again
+	})
+
+	t.Run("unsubscribes when context is done", func(t *testing.T) {
+		b := NewBroadcaster[string]()
+		defer b.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		b.AddCallback(ctx, func(string) {}, interfaces.ListenerOptions{})
+
+		cancel()
+		assert.Eventually(t, func() bool { return !b.HasListeners() }, timeout, time.Millisecond)
+	})
+
+	t.Run("slow callback does not block broadcast", func(t *testing.T) {
+		b := NewBroadcaster[string]()
+		defer b.Close()
+		started, release := make(chan struct{}, 10), make(chan struct{})
+		var received []string
+		var lock sync.Mutex
+		unsubscribe := b.AddCallback(nil, func(value string) {
+			started <- struct{}{}
+			<-release
+			lock.Lock()
+			received = append(received, value)
+			lock.Unlock()
+		}, interfaces.ListenerOptions{BufferSize: 2})
+		defer unsubscribe()
+
+		b.Broadcast("a")
+		th.RequireValue(t, started, timeout) // the callback is now blocked, and "a" is no longer in the queue
+		for _, value := range []string{"b", "c", "d"} {
+			b.Broadcast(value)
+		}
+		close(release)
+
+		assert.Eventually(t, func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(received) == 3
+		}, timeout, time.Millisecond)
+		assert.Equal(t, []string{"a", "c", "d"}, received) // "b" was dropped as the oldest
+	})
+}
+
+func TestBroadcasterOverflowPolicy(t *testing.T) {
+	for _, p := range []struct {
+		name     string
+		options  interfaces.ListenerOptions
+		expected []string
+	}{
+		{"drop oldest", interfaces.ListenerOptions{BufferSize: 2}, []string{"c", "d"}},
+		{"keep latest", interfaces.ListenerOptions{OverflowPolicy: interfaces.ListenerOverflowKeepLatest}, []string{"d"}},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			sub := newAsyncSubscriber[string](p.options, nil)
+			for _, value := range []string{"a", "b", "c", "d"} {
+				sub.offer(value)
+			}
+			var values []string
+			for value, ok := sub.next(); ok; value, ok = sub.next() {
+				values = append(values, value)
+			}
+			assert.Equal(t, p.expected, values)
+		})
+	}
+}
+
+func TestBroadcasterListenerCtx(t *testing.T) {
+	timeout := time.Second
+
+	t.Run("delivers values without blocking broadcast", func(t *testing.T) {
+		b := NewBroadcaster[string]()
+		defer b.Close()
+		ch := b.AddListenerCtx(context.Background(), interfaces.ListenerOptions{BufferSize: 1})
+
+		b.Broadcast("a")
+		assert.Equal(t, "a", th.RequireValue(t, ch, timeout))
+		for i := 0; i < 100; i++ {
+			b.Broadcast(fmt.Sprintf("value%d", i)) // we're not reading these, but it should not block
+		}
+		value := th.RequireValue(t, ch, timeout)
+		assert.Contains(t, []string{"value0", "value99"}, value)
+	})
+
+	t.Run("closes channel when context is done", func(t *testing.T) {
+		b := NewBroadcaster[string]()
+		defer b.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := b.AddListenerCtx(ctx, interfaces.ListenerOptions{})
+
+		b.Broadcast("a") // not consumed before cancellation
+		cancel()
+		assert.Eventually(t, func() bool {
+			select {
+			case _, ok := <-ch:
+				return !ok
+			default:
+				return false
+			}
+		}, timeout, time.Millisecond)
+		assert.False(t, b.HasListeners())
+	})
+
+	t.Run("closes channel when broadcaster is closed", func(t *testing.T) {
+		b := NewBroadcaster[string]()
+		ch := b.AddListenerCtx(context.Background(), interfaces.ListenerOptions{})
+		b.Close()
+		th.AssertChannelClosed(t, ch, timeout)
+	})
+}
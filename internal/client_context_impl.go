@@ -10,4 +10,6 @@ type ClientContextImpl struct {
 	subsystems.BasicClientContext
 	// Used internally to share a diagnosticsManager instance between components.
 	DiagnosticsManager *ldevents.DiagnosticsManager
+	// Used internally by ClientManager to flush the events of all of its clients on a shared schedule.
+	EventFlushScheduler *EventFlushScheduler
 }
@@ -51,14 +51,28 @@ func (d *dependencyTracker) reset() {
 	d.dependenciesTo = make(toposort.AdjacencyList)
 }
 
-// Populates the given set with the union of the initial item and all items that directly or indirectly
-// depend on it (based on the current state of the dependency graph).
-func (d *dependencyTracker) addAffectedItems(itemsOut toposort.Neighbors, initialModifiedItem toposort.Vertex) {
-	if !itemsOut.Contains(initialModifiedItem) {
-		itemsOut.Add(initialModifiedItem)
-		affectedItems := d.dependenciesTo[initialModifiedItem]
-		for affectedItem := range affectedItems {
-			d.addAffectedItems(itemsOut, affectedItem)
+// Populates the given map with the initial item and all items that directly or indirectly depend on it
+// (based on the current state of the dependency graph), mapping each of them to the initial item as the
+// cause of the change. Items that are already in the map keep their existing cause; so, if several items
+// have changed, the caller should add each of them to the map as its own cause before calling this, in
+// order for a direct change to take precedence over an indirect one.
+func (d *dependencyTracker) addAffectedItems(
+	itemsOut map[toposort.Vertex]toposort.Vertex,
+	initialModifiedItem toposort.Vertex,
+) {
+	itemsOut[initialModifiedItem] = initialModifiedItem
+	d.addDependentItems(itemsOut, initialModifiedItem, initialModifiedItem)
+}
+
+func (d *dependencyTracker) addDependentItems(
+	itemsOut map[toposort.Vertex]toposort.Vertex,
+	item toposort.Vertex,
+	cause toposort.Vertex,
+) {
+	for dependentItem := range d.dependenciesTo[item] {
+		if _, found := itemsOut[dependentItem]; !found {
+			itemsOut[dependentItem] = cause
+			d.addDependentItems(itemsOut, dependentItem, cause)
 		}
 	}
 }
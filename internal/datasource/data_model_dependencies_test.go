@@ -297,8 +297,14 @@ func verifyDependencyAffectedItems(
 	for _, value := range expected {
 		expectedSet.Add(value)
 	}
+	changedItem := toposort.NewVertex(kind, key)
+	causes := make(map[toposort.Vertex]toposort.Vertex)
+	dt.addAffectedItems(causes, changedItem)
 	result := make(toposort.Neighbors)
-	dt.addAffectedItems(result, toposort.NewVertex(kind, key))
+	for item, cause := range causes {
+		result.Add(item)
+		assert.Equal(t, changedItem, cause)
+	}
 	assert.Equal(t, expectedSet, result)
 }
 
@@ -1,6 +1,7 @@
 package datasource
 
 import (
+	"context"
 	"time"
 
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
@@ -11,13 +12,22 @@ import (
 // exported because the rest of the SDK code only interacts with the public interface.
 type dataSourceStatusProviderImpl struct {
 	broadcaster       *internal.Broadcaster[interfaces.DataSourceStatus]
-	dataSourceUpdates *DataSourceUpdateSinkImpl
+	dataSourceUpdates statusTracker
+}
+
+// statusTracker is implemented by DataSourceStatusReporterImpl, and by DataSourceUpdateSinkImpl which embeds it.
+type statusTracker interface {
+	GetLastStatus() interfaces.DataSourceStatus
+	waitFor(desiredState interfaces.DataSourceState, timeout time.Duration) bool
 }
 
 // NewDataSourceStatusProviderImpl creates the internal implementation of DataSourceStatusProvider.
+//
+// The dataSourceUpdates parameter is either a *DataSourceUpdateSinkImpl (FDv1) or a *DataSourceStatusReporterImpl
+// (FDv2).
 func NewDataSourceStatusProviderImpl(
 	broadcaster *internal.Broadcaster[interfaces.DataSourceStatus],
-	dataSourceUpdates *DataSourceUpdateSinkImpl,
+	dataSourceUpdates statusTracker,
 ) interfaces.DataSourceStatusProvider {
 	return &dataSourceStatusProviderImpl{broadcaster, dataSourceUpdates}
 }
@@ -34,6 +44,21 @@ func (d *dataSourceStatusProviderImpl) RemoveStatusListener(listener <-chan inte
 	d.broadcaster.RemoveListener(listener)
 }
 
+func (d *dataSourceStatusProviderImpl) AddStatusListenerFunc(
+	ctx context.Context,
+	listener func(interfaces.DataSourceStatus),
+	options interfaces.ListenerOptions,
+) func() {
+	return d.broadcaster.AddCallback(ctx, listener, options)
+}
+
+func (d *dataSourceStatusProviderImpl) AddStatusListenerCtx(
+	ctx context.Context,
+	options interfaces.ListenerOptions,
+) <-chan interfaces.DataSourceStatus {
+	return d.broadcaster.AddListenerCtx(ctx, options)
+}
+
 func (d *dataSourceStatusProviderImpl) WaitFor(desiredState interfaces.DataSourceState, timeout time.Duration) bool {
 	return d.dataSourceUpdates.waitFor(desiredState, timeout)
 }
@@ -0,0 +1,129 @@
+package datasource
+
+import (
+	"sync"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	intf "github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+)
+
+// DataSourceStatusReporterImpl is the internal implementation of DataSourceStatusReporter. It keeps track of
+// the current data source status, broadcasts changes to it, and logs extended outages.
+//
+// It is embedded in DataSourceUpdateSinkImpl for FDv1 data sources, and used directly by the FDv2 data system,
+// whose data sources report status separately from delivering data.
+type DataSourceStatusReporterImpl struct {
+	dataSourceStatusBroadcaster *internal.Broadcaster[intf.DataSourceStatus]
+	outageTracker               *outageTracker
+	currentStatus               intf.DataSourceStatus
+	lock                        sync.Mutex
+}
+
+// NewDataSourceStatusReporterImpl creates the internal implementation of DataSourceStatusReporter.
+func NewDataSourceStatusReporterImpl(
+	dataSourceStatusBroadcaster *internal.Broadcaster[intf.DataSourceStatus],
+	logDataSourceOutageAsErrorAfter time.Duration,
+	loggers ldlog.Loggers,
+) *DataSourceStatusReporterImpl {
+	return &DataSourceStatusReporterImpl{
+		dataSourceStatusBroadcaster: dataSourceStatusBroadcaster,
+		outageTracker:               newOutageTracker(logDataSourceOutageAsErrorAfter, loggers),
+		currentStatus: intf.DataSourceStatus{
+			State:      intf.DataSourceStateInitializing,
+			StateSince: time.Now(),
+		},
+	}
+}
+
+//nolint:revive // no doc comment for standard method
+func (d *DataSourceStatusReporterImpl) UpdateStatus(
+	newState intf.DataSourceState,
+	newError intf.DataSourceErrorInfo,
+) {
+	if newState == "" {
+		return
+	}
+	if statusToBroadcast, changed := d.maybeUpdateStatus(newState, newError); changed {
+		d.dataSourceStatusBroadcaster.Broadcast(statusToBroadcast)
+	}
+}
+
+func (d *DataSourceStatusReporterImpl) maybeUpdateStatus(
+	newState intf.DataSourceState,
+	newError intf.DataSourceErrorInfo,
+) (intf.DataSourceStatus, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	oldStatus := d.currentStatus
+
+	if newState == intf.DataSourceStateInterrupted && oldStatus.State == intf.DataSourceStateInitializing {
+		newState = intf.DataSourceStateInitializing // see comment on DataSourceUpdateSink.UpdateStatus
+	}
+
+	if newState == oldStatus.State && newError.Kind == "" {
+		return intf.DataSourceStatus{}, false
+	}
+
+	stateSince := oldStatus.StateSince
+	if newState != oldStatus.State {
+		stateSince = time.Now()
+	}
+	lastError := oldStatus.LastError
+	if newError.Kind != "" {
+		lastError = newError
+	}
+	d.currentStatus = intf.DataSourceStatus{
+		State:      newState,
+		StateSince: stateSince,
+		LastError:  lastError,
+	}
+
+	d.outageTracker.trackDataSourceState(newState, newError)
+
+	return d.currentStatus, true
+}
+
+// GetLastStatus is used internally by SDK components.
+func (d *DataSourceStatusReporterImpl) GetLastStatus() intf.DataSourceStatus {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.currentStatus
+}
+
+func (d *DataSourceStatusReporterImpl) waitFor(desiredState intf.DataSourceState, timeout time.Duration) bool {
+	d.lock.Lock()
+	if d.currentStatus.State == desiredState {
+		d.lock.Unlock()
+		return true
+	}
+	if d.currentStatus.State == intf.DataSourceStateOff {
+		d.lock.Unlock()
+		return false
+	}
+
+	statusCh := d.dataSourceStatusBroadcaster.AddListener()
+	defer d.dataSourceStatusBroadcaster.RemoveListener(statusCh)
+	d.lock.Unlock()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		deadline = time.After(timeout)
+	}
+
+	for {
+		select {
+		case newStatus := <-statusCh:
+			if newStatus.State == desiredState {
+				return true
+			}
+			if newStatus.State == intf.DataSourceStateOff {
+				return false
+			}
+		case <-deadline:
+			return false
+		}
+	}
+}
@@ -19,16 +19,14 @@ import (
 // because the actual implementation type, rather than the interface, is required as a dependency
 // of other SDK components.
 type DataSourceUpdateSinkImpl struct {
-	store                       subsystems.DataStore
-	dataStoreStatusProvider     intf.DataStoreStatusProvider
-	dataSourceStatusBroadcaster *internal.Broadcaster[intf.DataSourceStatus]
-	flagChangeEventBroadcaster  *internal.Broadcaster[intf.FlagChangeEvent]
-	dependencyTracker           *dependencyTracker
-	outageTracker               *outageTracker
-	loggers                     ldlog.Loggers
-	currentStatus               intf.DataSourceStatus
-	lastStoreUpdateFailed       bool
-	lock                        sync.Mutex
+	*DataSourceStatusReporterImpl
+	store                   subsystems.DataStore
+	dataStoreStatusProvider intf.DataStoreStatusProvider
+	flagChangeTracker       *FlagChangeTracker
+	sourceName              string
+	loggers                 ldlog.Loggers
+	lastStoreUpdateFailed   bool
+	lock                    sync.Mutex
 }
 
 // NewDataSourceUpdateSinkImpl creates the internal implementation of DataSourceUpdateSink.
@@ -41,25 +39,29 @@ func NewDataSourceUpdateSinkImpl(
 	loggers ldlog.Loggers,
 ) *DataSourceUpdateSinkImpl {
 	return &DataSourceUpdateSinkImpl{
-		store:                       store,
-		dataStoreStatusProvider:     dataStoreStatusProvider,
-		dataSourceStatusBroadcaster: dataSourceStatusBroadcaster,
-		flagChangeEventBroadcaster:  flagChangeEventBroadcaster,
-		dependencyTracker:           newDependencyTracker(),
-		outageTracker:               newOutageTracker(logDataSourceOutageAsErrorAfter, loggers),
-		loggers:                     loggers,
-		currentStatus: intf.DataSourceStatus{
-			State:      intf.DataSourceStateInitializing,
-			StateSince: time.Now(),
-		},
+		DataSourceStatusReporterImpl: NewDataSourceStatusReporterImpl(
+			dataSourceStatusBroadcaster,
+			logDataSourceOutageAsErrorAfter,
+			loggers,
+		),
+		store:                   store,
+		dataStoreStatusProvider: dataStoreStatusProvider,
+		flagChangeTracker:       NewFlagChangeTracker(flagChangeEventBroadcaster),
+		loggers:                 loggers,
 	}
 }
 
+// SetSourceName sets the name of the data source that is reported in flag change events. It must be called
+// before the data source is started.
+func (d *DataSourceUpdateSinkImpl) SetSourceName(name string) {
+	d.sourceName = name
+}
+
 //nolint:revive // no doc comment for standard method
 func (d *DataSourceUpdateSinkImpl) Init(allData []st.Collection) bool {
 	var oldData map[st.DataKind]map[string]st.ItemDescriptor
 
-	if d.flagChangeEventBroadcaster.HasListeners() {
+	if d.flagChangeTracker.HasListeners() {
 		// Query the existing data if any, so that after the update we can send events for whatever was changed
 		oldData = make(map[st.DataKind]map[string]st.ItemDescriptor)
 		for _, kind := range datakinds.AllDataKinds() {
@@ -77,15 +79,10 @@ func (d *DataSourceUpdateSinkImpl) Init(allData []st.Collection) bool {
 	updated := d.maybeUpdateError(err)
 
 	if updated {
-		// We must always update the dependency graph even if we don't currently have any event listeners, because if
-		// listeners are added later, we don't want to have to reread the whole data store to compute the graph
-		d.updateDependencyTrackerFromFullDataSet(allData)
-
-		// Now, if we previously queried the old data because someone is listening for flag change events, compare
-		// the versions of all items and generate events for those (and any other items that depend on them)
-		if oldData != nil {
-			d.sendChangeEvents(d.computeChangedItemsForFullDataSet(oldData, fullDataSetToMap(allData)))
-		}
+		// If we previously queried the old data because someone is listening for flag change events, the
+		// tracker compares the versions of all items and generates events for those (and any other items
+		// that depend on them)
+		d.flagChangeTracker.SetBasis(d.sourceName, oldData, allData)
 	}
 
 	return updated
@@ -101,12 +98,7 @@ func (d *DataSourceUpdateSinkImpl) Upsert(
 	didNotGetError := d.maybeUpdateError(err)
 
 	if updated {
-		d.dependencyTracker.updateDependenciesFrom(kind, key, item)
-		if d.flagChangeEventBroadcaster.HasListeners() {
-			affectedItems := make(toposort.Neighbors)
-			d.dependencyTracker.addAffectedItems(affectedItems, toposort.NewVertex(kind, key))
-			d.sendChangeEvents(affectedItems)
-		}
+		d.flagChangeTracker.Upsert(d.sourceName, kind, key, item)
 	}
 
 	return didNotGetError
@@ -141,161 +133,11 @@ func (d *DataSourceUpdateSinkImpl) maybeUpdateError(err error) bool {
 	return false
 }
 
-//nolint:revive // no doc comment for standard method
-func (d *DataSourceUpdateSinkImpl) UpdateStatus(
-	newState intf.DataSourceState,
-	newError intf.DataSourceErrorInfo,
-) {
-	if newState == "" {
-		return
-	}
-	if statusToBroadcast, changed := d.maybeUpdateStatus(newState, newError); changed {
-		d.dataSourceStatusBroadcaster.Broadcast(statusToBroadcast)
-	}
-}
-
-func (d *DataSourceUpdateSinkImpl) maybeUpdateStatus(
-	newState intf.DataSourceState,
-	newError intf.DataSourceErrorInfo,
-) (intf.DataSourceStatus, bool) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
-
-	oldStatus := d.currentStatus
-
-	if newState == intf.DataSourceStateInterrupted && oldStatus.State == intf.DataSourceStateInitializing {
-		newState = intf.DataSourceStateInitializing // see comment on DataSourceUpdateSink.UpdateStatus
-	}
-
-	if newState == oldStatus.State && newError.Kind == "" {
-		return intf.DataSourceStatus{}, false
-	}
-
-	stateSince := oldStatus.StateSince
-	if newState != oldStatus.State {
-		stateSince = time.Now()
-	}
-	lastError := oldStatus.LastError
-	if newError.Kind != "" {
-		lastError = newError
-	}
-	d.currentStatus = intf.DataSourceStatus{
-		State:      newState,
-		StateSince: stateSince,
-		LastError:  lastError,
-	}
-
-	d.outageTracker.trackDataSourceState(newState, newError)
-
-	return d.currentStatus, true
-}
-
 //nolint:revive // no doc comment for standard method
 func (d *DataSourceUpdateSinkImpl) GetDataStoreStatusProvider() intf.DataStoreStatusProvider {
 	return d.dataStoreStatusProvider
 }
 
-// GetLastStatus is used internally by SDK components.
-func (d *DataSourceUpdateSinkImpl) GetLastStatus() intf.DataSourceStatus {
-	d.lock.Lock()
-	defer d.lock.Unlock()
-	return d.currentStatus
-}
-
-func (d *DataSourceUpdateSinkImpl) waitFor(desiredState intf.DataSourceState, timeout time.Duration) bool {
-	d.lock.Lock()
-	if d.currentStatus.State == desiredState {
-		d.lock.Unlock()
-		return true
-	}
-	if d.currentStatus.State == intf.DataSourceStateOff {
-		d.lock.Unlock()
-		return false
-	}
-
-	statusCh := d.dataSourceStatusBroadcaster.AddListener()
-	defer d.dataSourceStatusBroadcaster.RemoveListener(statusCh)
-	d.lock.Unlock()
-
-	var deadline <-chan time.Time
-	if timeout > 0 {
-		deadline = time.After(timeout)
-	}
-
-	for {
-		select {
-		case newStatus := <-statusCh:
-			if newStatus.State == desiredState {
-				return true
-			}
-			if newStatus.State == intf.DataSourceStateOff {
-				return false
-			}
-		case <-deadline:
-			return false
-		}
-	}
-}
-
-func (d *DataSourceUpdateSinkImpl) sendChangeEvents(affectedItems toposort.Neighbors) {
-	for item := range affectedItems {
-		if item.Kind() == datakinds.Features {
-			d.flagChangeEventBroadcaster.Broadcast(intf.FlagChangeEvent{Key: item.Key()})
-		}
-	}
-}
-
-func (d *DataSourceUpdateSinkImpl) updateDependencyTrackerFromFullDataSet(allData []st.Collection) {
-	d.dependencyTracker.reset()
-	for _, coll := range allData {
-		for _, item := range coll.Items {
-			d.dependencyTracker.updateDependenciesFrom(coll.Kind, item.Key, item.Item)
-		}
-	}
-}
-
-func fullDataSetToMap(allData []st.Collection) map[st.DataKind]map[string]st.ItemDescriptor {
-	ret := make(map[st.DataKind]map[string]st.ItemDescriptor, len(allData))
-	for _, coll := range allData {
-		m := make(map[string]st.ItemDescriptor, len(coll.Items))
-		for _, item := range coll.Items {
-			m[item.Key] = item.Item
-		}
-		ret[coll.Kind] = m
-	}
-	return ret
-}
-
-func (d *DataSourceUpdateSinkImpl) computeChangedItemsForFullDataSet(
-	oldDataMap map[st.DataKind]map[string]st.ItemDescriptor,
-	newDataMap map[st.DataKind]map[string]st.ItemDescriptor,
-) toposort.Neighbors {
-	affectedItems := make(toposort.Neighbors)
-	for _, kind := range datakinds.AllDataKinds() {
-		oldItems := oldDataMap[kind]
-		newItems := newDataMap[kind]
-		allKeys := make([]string, 0, len(oldItems)+len(newItems))
-		for key := range oldItems {
-			allKeys = append(allKeys, key)
-		}
-		for key := range newItems {
-			if _, found := oldItems[key]; !found {
-				allKeys = append(allKeys, key)
-			}
-		}
-		for _, key := range allKeys {
-			oldItem, haveOld := oldItems[key]
-			newItem, haveNew := newItems[key]
-			if haveOld || haveNew {
-				if !haveOld || !haveNew || oldItem.Version < newItem.Version {
-					d.dependencyTracker.addAffectedItems(affectedItems, toposort.NewVertex(kind, key))
-				}
-			}
-		}
-	}
-	return affectedItems
-}
-
 type outageTracker struct {
 	outageLoggingTimeout time.Duration
 	loggers              ldlog.Loggers
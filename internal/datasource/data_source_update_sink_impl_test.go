@@ -328,6 +328,21 @@ func TestDataSourceUpdatesImplFlagChangeEvents(t *testing.T) {
 		})
 	})
 
+	t.Run("reports the source name", func(t *testing.T) {
+		dataSourceUpdateSinkImplTest(func(p dataSourceUpdateSinkImplTestParams) {
+			p.dataSourceUpdates.SetSourceName("MySource")
+			p.dataSourceUpdates.Init(sharedtest.NewDataSetBuilder().Build())
+
+			ch := p.flagChangeBroadcaster.AddListener()
+
+			flag1 := ldbuilders.NewFlagBuilder("flag1").Version(1).Build()
+			p.dataSourceUpdates.Upsert(datakinds.Features, flag1.Key, st.ItemDescriptor{Version: flag1.Version, Item: &flag1})
+
+			event := th.RequireValue(t, ch, time.Second, "timed out waiting for flag change event")
+			assert.Equal(t, "MySource", event.Source)
+		})
+	})
+
 	t.Run("does not send event on update if item was not really updated", func(t *testing.T) {
 		dataSourceUpdateSinkImplTest(func(p dataSourceUpdateSinkImplTestParams) {
 			builder := sharedtest.NewDataSetBuilder().
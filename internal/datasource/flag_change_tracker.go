@@ -0,0 +1,226 @@
+package datasource
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	intf "github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/toposort"
+	st "github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+)
+
+// FlagChangeTracker maintains the dependency graph between flags and segments, and broadcasts a
+// FlagChangeEvent for every flag that is affected by an update, either directly or because it depends on
+// a changed item through a prerequisite or segment reference.
+//
+// It also keeps track of the current version of every item, so that events can report the version before
+// and after a change without the caller having to query the data store.
+//
+// DataSourceUpdateSinkImpl uses it for FDv1 data sources; the FDv2 data system uses it directly.
+type FlagChangeTracker struct {
+	dependencyTracker *dependencyTracker
+	versions          map[toposort.Vertex]int
+	broadcaster       *internal.Broadcaster[intf.FlagChangeEvent]
+	lock              sync.Mutex
+}
+
+// NewFlagChangeTracker creates a FlagChangeTracker that sends events to the given broadcaster.
+func NewFlagChangeTracker(broadcaster *internal.Broadcaster[intf.FlagChangeEvent]) *FlagChangeTracker {
+	return &FlagChangeTracker{
+		dependencyTracker: newDependencyTracker(),
+		versions:          make(map[toposort.Vertex]int),
+		broadcaster:       broadcaster,
+	}
+}
+
+// HasListeners returns true if anyone is listening for flag change events. Callers can use this to avoid
+// the cost of querying the old state of the data before a full update.
+func (f *FlagChangeTracker) HasListeners() bool {
+	return f.broadcaster.HasListeners()
+}
+
+// SetBasis rebuilds the dependency graph from a full data set. If oldData is non-nil, events are sent for
+// every item that was added, removed, or changed version relative to oldData, and for anything that
+// depends on those items. The source is the name of the data source that provided the data, if known.
+//
+// The dependency graph must always be updated even if there are no listeners, because if listeners are
+// added later, we don't want to have to reread the whole data store to compute the graph.
+func (f *FlagChangeTracker) SetBasis(
+	source string,
+	oldData map[st.DataKind]map[string]st.ItemDescriptor,
+	allData []st.Collection,
+) {
+	f.lock.Lock()
+	f.dependencyTracker.reset()
+	f.versions = make(map[toposort.Vertex]int)
+	for _, coll := range allData {
+		for _, item := range coll.Items {
+			f.dependencyTracker.updateDependenciesFrom(coll.Kind, item.Key, item.Item)
+			f.updateVersion(toposort.NewVertex(coll.Kind, item.Key), item.Item)
+		}
+	}
+	var events []intf.FlagChangeEvent
+	if oldData != nil {
+		events = f.makeChangeEvents(source, computeChangedItemsForFullDataSet(oldData, fullDataSetToMap(allData)))
+	}
+	f.lock.Unlock()
+
+	f.sendChangeEvents(events)
+}
+
+// Upsert updates the dependency graph for a single item that has changed, and sends events for it and
+// anything that depends on it.
+func (f *FlagChangeTracker) Upsert(source string, kind st.DataKind, key string, item st.ItemDescriptor) {
+	f.ApplyDelta(source, []st.Collection{{Kind: kind, Items: []st.KeyedItemDescriptor{{Key: key, Item: item}}}})
+}
+
+// ApplyDelta updates the dependency graph for a set of items that have changed, and sends events for them
+// and anything that depends on them. The caller is responsible for passing only items that were actually
+// updated in the store.
+func (f *FlagChangeTracker) ApplyDelta(source string, changed []st.Collection) {
+	f.lock.Lock()
+	listening := f.broadcaster.HasListeners()
+	var oldVersions map[toposort.Vertex]ldvalue.OptionalInt
+	if listening {
+		oldVersions = make(map[toposort.Vertex]ldvalue.OptionalInt)
+	}
+	for _, coll := range changed {
+		for _, item := range coll.Items {
+			vertex := toposort.NewVertex(coll.Kind, item.Key)
+			if listening {
+				oldVersions[vertex] = f.currentVersion(vertex)
+			}
+			f.dependencyTracker.updateDependenciesFrom(coll.Kind, item.Key, item.Item)
+			f.updateVersion(vertex, item.Item)
+		}
+	}
+	var events []intf.FlagChangeEvent
+	if listening {
+		events = f.makeChangeEvents(source, oldVersions)
+	}
+	f.lock.Unlock()
+
+	f.sendChangeEvents(events)
+}
+
+func (f *FlagChangeTracker) sendChangeEvents(events []intf.FlagChangeEvent) {
+	for _, event := range events {
+		f.broadcaster.Broadcast(event)
+	}
+}
+
+// makeChangeEvents computes the events for a set of directly changed items, given their versions before the
+// change, and everything that depends on them. The tracker's state must already reflect the change, and the
+// caller must hold the lock.
+func (f *FlagChangeTracker) makeChangeEvents(
+	source string,
+	oldVersions map[toposort.Vertex]ldvalue.OptionalInt,
+) []intf.FlagChangeEvent {
+	causes := make(map[toposort.Vertex]toposort.Vertex, len(oldVersions))
+	for item := range oldVersions {
+		causes[item] = item
+	}
+	for item := range oldVersions {
+		f.dependencyTracker.addAffectedItems(causes, item)
+	}
+	var events []intf.FlagChangeEvent
+	for item, cause := range causes {
+		if item.Kind() != datakinds.Features {
+			continue
+		}
+		event := intf.FlagChangeEvent{Key: item.Key(), NewVersion: f.currentVersion(item), Source: source}
+		if cause == item {
+			event.Cause = intf.FlagChangeCauseDirect
+			event.OldVersion = oldVersions[item]
+			switch {
+			case !event.NewVersion.IsDefined():
+				event.Kind = intf.FlagChangeKindDeleted
+			case !event.OldVersion.IsDefined():
+				event.Kind = intf.FlagChangeKindAdded
+			default:
+				event.Kind = intf.FlagChangeKindUpdated
+			}
+		} else {
+			event.Kind = intf.FlagChangeKindUpdated
+			event.Cause = intf.FlagChangeCauseSegment
+			if cause.Kind() == datakinds.Features {
+				event.Cause = intf.FlagChangeCausePrerequisite
+			}
+			event.CauseKey = cause.Key()
+			event.OldVersion = event.NewVersion
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Key < events[j].Key })
+	return events
+}
+
+// currentVersion returns the version of an item, or an undefined value if it does not exist or has been
+// deleted. The caller must hold the lock.
+func (f *FlagChangeTracker) currentVersion(item toposort.Vertex) ldvalue.OptionalInt {
+	if version, ok := f.versions[item]; ok {
+		return ldvalue.NewOptionalInt(version)
+	}
+	return ldvalue.OptionalInt{}
+}
+
+// updateVersion records the version of an item, or forgets it if the item has been deleted. The caller must
+// hold the lock.
+func (f *FlagChangeTracker) updateVersion(item toposort.Vertex, descriptor st.ItemDescriptor) {
+	if descriptor.Item == nil {
+		delete(f.versions, item)
+	} else {
+		f.versions[item] = descriptor.Version
+	}
+}
+
+// computeChangedItemsForFullDataSet returns the previous version of every item that was added, removed, or
+// changed version between the old and new data sets.
+func computeChangedItemsForFullDataSet(
+	oldDataMap map[st.DataKind]map[string]st.ItemDescriptor,
+	newDataMap map[st.DataKind]map[string]st.ItemDescriptor,
+) map[toposort.Vertex]ldvalue.OptionalInt {
+	oldVersions := make(map[toposort.Vertex]ldvalue.OptionalInt)
+	for _, kind := range datakinds.AllDataKinds() {
+		oldItems := oldDataMap[kind]
+		newItems := newDataMap[kind]
+		allKeys := make([]string, 0, len(oldItems)+len(newItems))
+		for key := range oldItems {
+			allKeys = append(allKeys, key)
+		}
+		for key := range newItems {
+			if _, found := oldItems[key]; !found {
+				allKeys = append(allKeys, key)
+			}
+		}
+		for _, key := range allKeys {
+			oldItem, haveOld := oldItems[key]
+			newItem, haveNew := newItems[key]
+			if haveOld || haveNew {
+				if !haveOld || !haveNew || oldItem.Version < newItem.Version {
+					var oldVersion ldvalue.OptionalInt
+					if haveOld && oldItem.Item != nil {
+						oldVersion = ldvalue.NewOptionalInt(oldItem.Version)
+					}
+					oldVersions[toposort.NewVertex(kind, key)] = oldVersion
+				}
+			}
+		}
+	}
+	return oldVersions
+}
+
+func fullDataSetToMap(allData []st.Collection) map[st.DataKind]map[string]st.ItemDescriptor {
+	ret := make(map[st.DataKind]map[string]st.ItemDescriptor, len(allData))
+	for _, coll := range allData {
+		m := make(map[string]st.ItemDescriptor, len(coll.Items))
+		for _, item := range coll.Items {
+			m[item.Key] = item.Item
+		}
+		ret[coll.Kind] = m
+	}
+	return ret
+}
@@ -0,0 +1,218 @@
+package datasource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	intf "github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	st "github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+
+	th "github.com/launchdarkly/go-test-helpers/v3"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSourceName = "TestSource"
+
+func flagChangeTrackerTest(action func(*FlagChangeTracker, <-chan intf.FlagChangeEvent)) {
+	broadcaster := internal.NewBroadcaster[intf.FlagChangeEvent]()
+	defer broadcaster.Close()
+	tracker := NewFlagChangeTracker(broadcaster)
+	tracker.SetBasis(testSourceName, nil, sharedtest.NewDataSetBuilder().
+		Flags(
+			ldbuilders.NewFlagBuilder("flag1").Version(1).Build(),
+			ldbuilders.NewFlagBuilder("flag2").Version(2).AddPrerequisite("flag1", 0).Build(),
+			ldbuilders.NewFlagBuilder("flag3").Version(3).AddPrerequisite("flag2", 0).
+				AddRule(ldbuilders.NewRuleBuilder().Clauses(ldbuilders.SegmentMatchClause("segment1"))).Build(),
+		).
+		Segments(ldbuilders.NewSegmentBuilder("segment1").Version(1).Build()).
+		Build())
+	action(tracker, broadcaster.AddListener())
+}
+
+func expectFlagChangeEvents(t *testing.T, ch <-chan intf.FlagChangeEvent, expected ...intf.FlagChangeEvent) {
+	for _, e := range expected {
+		assert.Equal(t, e, th.RequireValue(t, ch, time.Second, "timed out waiting for flag change event"))
+	}
+	th.AssertNoMoreValues(t, ch, time.Millisecond*50)
+}
+
+func TestFlagChangeTrackerReportsKindAndVersions(t *testing.T) {
+	t.Run("added", func(t *testing.T) {
+		flagChangeTrackerTest(func(tracker *FlagChangeTracker, ch <-chan intf.FlagChangeEvent) {
+			tracker.Upsert(testSourceName, datakinds.Features, "flag4",
+				sharedtest.FlagDescriptor(ldbuilders.NewFlagBuilder("flag4").Version(1).Build()))
+
+			expectFlagChangeEvents(t, ch, intf.FlagChangeEvent{
+				Key:        "flag4",
+				Kind:       intf.FlagChangeKindAdded,
+				Cause:      intf.FlagChangeCauseDirect,
+				NewVersion: ldvalue.NewOptionalInt(1),
+				Source:     testSourceName,
+			})
+		})
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		flagChangeTrackerTest(func(tracker *FlagChangeTracker, ch <-chan intf.FlagChangeEvent) {
+			tracker.Upsert(testSourceName, datakinds.Features, "flag3",
+				sharedtest.FlagDescriptor(ldbuilders.NewFlagBuilder("flag3").Version(4).Build()))
+
+			expectFlagChangeEvents(t, ch, intf.FlagChangeEvent{
+				Key:        "flag3",
+				Kind:       intf.FlagChangeKindUpdated,
+				Cause:      intf.FlagChangeCauseDirect,
+				OldVersion: ldvalue.NewOptionalInt(3),
+				NewVersion: ldvalue.NewOptionalInt(4),
+				Source:     testSourceName,
+			})
+		})
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		flagChangeTrackerTest(func(tracker *FlagChangeTracker, ch <-chan intf.FlagChangeEvent) {
+			tracker.Upsert(testSourceName, datakinds.Features, "flag3", st.ItemDescriptor{Version: 4, Item: nil})
+
+			expectFlagChangeEvents(t, ch, intf.FlagChangeEvent{
+				Key:        "flag3",
+				Kind:       intf.FlagChangeKindDeleted,
+				Cause:      intf.FlagChangeCauseDirect,
+				OldVersion: ldvalue.NewOptionalInt(3),
+				Source:     testSourceName,
+			})
+		})
+	})
+
+	t.Run("re-added after deletion", func(t *testing.T) {
+		flagChangeTrackerTest(func(tracker *FlagChangeTracker, ch <-chan intf.FlagChangeEvent) {
+			tracker.Upsert(testSourceName, datakinds.Features, "flag3", st.ItemDescriptor{Version: 4, Item: nil})
+			<-ch
+			tracker.Upsert(testSourceName, datakinds.Features, "flag3",
+				sharedtest.FlagDescriptor(ldbuilders.NewFlagBuilder("flag3").Version(5).Build()))
+
+			expectFlagChangeEvents(t, ch, intf.FlagChangeEvent{
+				Key:        "flag3",
+				Kind:       intf.FlagChangeKindAdded,
+				Cause:      intf.FlagChangeCauseDirect,
+				NewVersion: ldvalue.NewOptionalInt(5),
+				Source:     testSourceName,
+			})
+		})
+	})
+}
+
+func TestFlagChangeTrackerReportsCause(t *testing.T) {
+	t.Run("prerequisite", func(t *testing.T) {
+		flagChangeTrackerTest(func(tracker *FlagChangeTracker, ch <-chan intf.FlagChangeEvent) {
+			tracker.Upsert(testSourceName, datakinds.Features, "flag1",
+				sharedtest.FlagDescriptor(ldbuilders.NewFlagBuilder("flag1").Version(2).Build()))
+
+			expectFlagChangeEvents(t, ch,
+				intf.FlagChangeEvent{
+					Key:        "flag1",
+					Kind:       intf.FlagChangeKindUpdated,
+					Cause:      intf.FlagChangeCauseDirect,
+					OldVersion: ldvalue.NewOptionalInt(1),
+					NewVersion: ldvalue.NewOptionalInt(2),
+					Source:     testSourceName,
+				},
+				intf.FlagChangeEvent{
+					Key:        "flag2",
+					Kind:       intf.FlagChangeKindUpdated,
+					Cause:      intf.FlagChangeCausePrerequisite,
+					CauseKey:   "flag1",
+					OldVersion: ldvalue.NewOptionalInt(2),
+					NewVersion: ldvalue.NewOptionalInt(2),
+					Source:     testSourceName,
+				},
+				intf.FlagChangeEvent{
+					Key:        "flag3",
+					Kind:       intf.FlagChangeKindUpdated,
+					Cause:      intf.FlagChangeCausePrerequisite,
+					CauseKey:   "flag1",
+					OldVersion: ldvalue.NewOptionalInt(3),
+					NewVersion: ldvalue.NewOptionalInt(3),
+					Source:     testSourceName,
+				},
+			)
+		})
+	})
+
+	t.Run("segment", func(t *testing.T) {
+		flagChangeTrackerTest(func(tracker *FlagChangeTracker, ch <-chan intf.FlagChangeEvent) {
+			tracker.Upsert(testSourceName, datakinds.Segments, "segment1",
+				sharedtest.SegmentDescriptor(ldbuilders.NewSegmentBuilder("segment1").Version(2).Build()))
+
+			expectFlagChangeEvents(t, ch, intf.FlagChangeEvent{
+				Key:        "flag3",
+				Kind:       intf.FlagChangeKindUpdated,
+				Cause:      intf.FlagChangeCauseSegment,
+				CauseKey:   "segment1",
+				OldVersion: ldvalue.NewOptionalInt(3),
+				NewVersion: ldvalue.NewOptionalInt(3),
+				Source:     testSourceName,
+			})
+		})
+	})
+
+	t.Run("direct change takes precedence", func(t *testing.T) {
+		flagChangeTrackerTest(func(tracker *FlagChangeTracker, ch <-chan intf.FlagChangeEvent) {
+			tracker.ApplyDelta(testSourceName, sharedtest.NewDataSetBuilder().
+				Flags(
+					ldbuilders.NewFlagBuilder("flag1").Version(2).Build(),
+					ldbuilders.NewFlagBuilder("flag2").Version(3).AddPrerequisite("flag1", 0).Build(),
+				).
+				Build())
+
+			events := []intf.FlagChangeEvent{
+				th.RequireValue(t, ch, time.Second), th.RequireValue(t, ch, time.Second), th.RequireValue(t, ch, time.Second),
+			}
+			assert.Equal(t, "flag2", events[1].Key)
+			assert.Equal(t, intf.FlagChangeCauseDirect, events[1].Cause)
+			assert.Equal(t, ldvalue.NewOptionalInt(2), events[1].OldVersion)
+			assert.Equal(t, ldvalue.NewOptionalInt(3), events[1].NewVersion)
+			assert.Equal(t, "flag3", events[2].Key)
+			assert.Equal(t, intf.FlagChangeCausePrerequisite, events[2].Cause)
+		})
+	})
+}
+
+func TestFlagChangeTrackerComparesFullDataSets(t *testing.T) {
+	broadcaster := internal.NewBroadcaster[intf.FlagChangeEvent]()
+	defer broadcaster.Close()
+	tracker := NewFlagChangeTracker(broadcaster)
+	ch := broadcaster.AddListener()
+
+	oldData := sharedtest.NewDataSetBuilder().Flags(
+		ldbuilders.NewFlagBuilder("flag1").Version(1).Build(),
+		ldbuilders.NewFlagBuilder("flag2").Version(1).Build(),
+	).Build()
+	tracker.SetBasis(testSourceName, nil, oldData)
+
+	tracker.SetBasis(testSourceName, fullDataSetToMap(oldData), sharedtest.NewDataSetBuilder().Flags(
+		ldbuilders.NewFlagBuilder("flag2").Version(1).Build(),
+		ldbuilders.NewFlagBuilder("flag3").Version(1).Build(),
+	).Build())
+
+	expectFlagChangeEvents(t, ch,
+		intf.FlagChangeEvent{
+			Key:        "flag1",
+			Kind:       intf.FlagChangeKindDeleted,
+			Cause:      intf.FlagChangeCauseDirect,
+			OldVersion: ldvalue.NewOptionalInt(1),
+			Source:     testSourceName,
+		},
+		intf.FlagChangeEvent{
+			Key:        "flag3",
+			Kind:       intf.FlagChangeKindAdded,
+			Cause:      intf.FlagChangeCauseDirect,
+			NewVersion: ldvalue.NewOptionalInt(1),
+			Source:     testSourceName,
+		},
+	)
+}
@@ -158,6 +158,11 @@ func (pp *PollingProcessor) poll() error {
 	return nil
 }
 
+//nolint:revive // no doc comment for standard method
+func (pp *PollingProcessor) Name() string {
+	return "PollingDataSource"
+}
+
 //nolint:revive // no doc comment for standard method
 func (pp *PollingProcessor) Close() error {
 	pp.closeOnce.Do(func() {
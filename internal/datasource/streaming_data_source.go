@@ -386,6 +386,11 @@ func (sp *StreamProcessor) logConnectionResult(success bool) {
 	}
 }
 
+//nolint:revive // no doc comment for standard method
+func (sp *StreamProcessor) Name() string {
+	return "StreamingDataSource"
+}
+
 //nolint:revive // no doc comment for standard method
 func (sp *StreamProcessor) Close() error {
 	sp.closeOnce.Do(func() {
@@ -0,0 +1,45 @@
+package datasourcev2
+
+import (
+	"net/http"
+
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// basisParam is the query parameter that tells LaunchDarkly which version of the data the SDK already has, so
+// that it can send only the changes since that version.
+const basisParam = "basis"
+
+// addBasisParam adds the basis parameter to the request URL if the selector is set. The request must not be
+// shared, since its URL is modified in place.
+func addBasisParam(req *http.Request, selector *fdv2proto.Selector) {
+	if !selector.IsSet() {
+		return
+	}
+	query := req.URL.Query()
+	query.Set(basisParam, selector.State())
+	req.URL.RawQuery = query.Encode()
+}
+
+// basisTransport adds the basis parameter to every request, using whatever selector the SDK has at the time
+// the request is made.
+type basisTransport struct {
+	base          http.RoundTripper
+	selectorStore subsystems.SelectorStore
+}
+
+func (t *basisTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	selector := t.selectorStore.Selector()
+	if !selector.IsSet() {
+		return base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request it was given.
+	reqCopy := req.Clone(req.Context())
+	addBasisParam(reqCopy, selector)
+	return base.RoundTrip(reqCopy)
+}
@@ -1,6 +1,8 @@
 package datasourcev2
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -21,7 +23,8 @@ const (
 // PollingRequester allows PollingProcessor to delegate fetching data to another component.
 // This is useful for testing the PollingProcessor without needing to set up a test HTTP server.
 type PollingRequester interface {
-	Request() (*PollingResponse, error)
+	// Request polls for data. If basis is set, the response may contain only the changes since that basis.
+	Request(basis *fdv2proto.Selector) (*PollingResponse, error)
 	BaseURI() string
 	FilterKey() string
 }
@@ -33,6 +36,7 @@ type PollingRequester interface {
 // DataSource interface.
 type PollingProcessor struct {
 	dataDestination    subsystems.DataDestination
+	selectorStore      subsystems.SelectorStore
 	statusReporter     subsystems.DataSourceStatusReporter
 	requester          PollingRequester
 	pollInterval       time.Duration
@@ -47,22 +51,25 @@ type PollingProcessor struct {
 func NewPollingProcessor(
 	context subsystems.ClientContext,
 	dataDestination subsystems.DataDestination,
+	selectorStore subsystems.SelectorStore,
 	statusReporter subsystems.DataSourceStatusReporter,
 	cfg datasource.PollingConfig,
 ) *PollingProcessor {
 	httpRequester := newPollingRequester(context, context.GetHTTP().CreateHTTPClient(), cfg.BaseURI, cfg.FilterKey)
-	return newPollingProcessor(context, dataDestination, statusReporter, httpRequester, cfg.PollInterval)
+	return newPollingProcessor(context, dataDestination, selectorStore, statusReporter, httpRequester, cfg.PollInterval)
 }
 
 func newPollingProcessor(
 	context subsystems.ClientContext,
 	dataDestination subsystems.DataDestination,
+	selectorStore subsystems.SelectorStore,
 	statusReporter subsystems.DataSourceStatusReporter,
 	requester PollingRequester,
 	pollInterval time.Duration,
 ) *PollingProcessor {
 	pp := &PollingProcessor{
 		dataDestination: dataDestination,
+		selectorStore:   selectorStore,
 		statusReporter:  statusReporter,
 		requester:       requester,
 		pollInterval:    pollInterval,
@@ -142,7 +149,11 @@ func (pp *PollingProcessor) Start(closeWhenReady chan<- struct{}) {
 }
 
 func (pp *PollingProcessor) poll() error {
-	response, err := pp.requester.Request()
+	basis := fdv2proto.NoSelector()
+	if pp.selectorStore != nil {
+		basis = pp.selectorStore.Selector()
+	}
+	response, err := pp.requester.Request(basis)
 
 	if err != nil {
 		return err
@@ -162,6 +173,38 @@ func (pp *PollingProcessor) poll() error {
 	return nil
 }
 
+//nolint:revive // no doc comment for standard method
+func (pp *PollingProcessor) Name() string {
+	return "PollingDataSourceV2"
+}
+
+// Fetch makes a single polling request, allowing the PollingProcessor to be used as a DataInitializer.
+func (pp *PollingProcessor) Fetch(ctx context.Context) (*subsystems.Basis, error) {
+	type result struct {
+		response *PollingResponse
+		err      error
+	}
+	results := make(chan result, 1)
+	go func() {
+		// An initializer must obtain a full data set, so it never sends a basis.
+		response, err := pp.requester.Request(fdv2proto.NoSelector())
+		results <- result{response, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-results:
+		if r.err != nil {
+			return nil, r.err
+		}
+		if r.response.Cached() || r.response.Intent() != fdv2proto.IntentTransferFull {
+			return nil, errors.New("polling response did not contain a full data set")
+		}
+		return &subsystems.Basis{Events: r.response.Events(), Selector: r.response.Selector(), Persist: true}, nil
+	}
+}
+
 //nolint:revive // no doc comment for standard method
 func (pp *PollingProcessor) Close() error {
 	pp.closeOnce.Do(func() {
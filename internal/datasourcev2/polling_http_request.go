@@ -70,12 +70,12 @@ func (r *pollingRequester) FilterKey() string {
 	return r.filterKey
 }
 
-func (r *pollingRequester) Request() (*PollingResponse, error) {
+func (r *pollingRequester) Request(basis *fdv2proto.Selector) (*PollingResponse, error) {
 	if r.loggers.IsDebugEnabled() {
 		r.loggers.Debug("Polling LaunchDarkly for feature flag updates")
 	}
 
-	body, cached, err := r.makeRequest(endpoints.PollingRequestPath)
+	body, cached, err := r.makeRequest(endpoints.PollingRequestPath, basis)
 	if err != nil {
 		return nil, err
 	}
@@ -100,6 +100,7 @@ func (r *pollingRequester) Request() (*PollingResponse, error) {
 	updates := make([]fdv2proto.Event, 0, len(payload.Events))
 
 	var intentCode fdv2proto.IntentCode
+	selector := fdv2proto.NoSelector()
 
 	for _, event := range payload.Events {
 		switch event.Name {
@@ -146,7 +147,7 @@ func (r *pollingRequester) Request() (*PollingResponse, error) {
 						}
 					}
 				}
-				updates = append(updates, fdv2proto.PutObject{Kind: kind, Key: key, Object: item, Version: version})
+				updates = append(updates, fdv2proto.PutObject{Kind: kind, Key: key, Object: item.Item, Version: version})
 			}
 		case fdv2proto.EventDeleteObject:
 			{
@@ -174,8 +175,11 @@ func (r *pollingRequester) Request() (*PollingResponse, error) {
 				updates = append(updates, fdv2proto.DeleteObject{Kind: kind, Key: key, Version: version})
 			}
 		case fdv2proto.EventPayloadTransferred:
-			//nolint:godox
-			// TODO: deserialize the state and create a fdv2proto.Selector.
+			var transferred fdv2proto.Selector
+			if err := json.Unmarshal(event.Data, &transferred); err != nil {
+				return nil, malformedJSONError{err}
+			}
+			selector = &transferred
 		}
 	}
 
@@ -183,10 +187,10 @@ func (r *pollingRequester) Request() (*PollingResponse, error) {
 		return nil, errors.New("no server-intent event found in polling response")
 	}
 
-	return NewPollingResponse(intentCode, updates, fdv2proto.NoSelector()), nil
+	return NewPollingResponse(intentCode, updates, selector), nil
 }
 
-func (r *pollingRequester) makeRequest(resource string) ([]byte, bool, error) {
+func (r *pollingRequester) makeRequest(resource string, basis *fdv2proto.Selector) ([]byte, bool, error) {
 	req, reqErr := http.NewRequest("GET", endpoints.AddPath(r.baseURI, resource), nil)
 	if reqErr != nil {
 		reqErr = fmt.Errorf(
@@ -200,6 +204,7 @@ func (r *pollingRequester) makeRequest(resource string) ([]byte, bool, error) {
 			"filter": {r.filterKey},
 		}.Encode()
 	}
+	addBasisParam(req, basis)
 	url := req.URL.String()
 	if r.headers != nil {
 		req.Header = maps.Clone(r.headers)
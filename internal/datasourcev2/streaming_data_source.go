@@ -78,6 +78,7 @@ type changeSet struct {
 type StreamProcessor struct {
 	cfg                        datasource.StreamConfig
 	dataDestination            subsystems.DataDestination
+	selectorStore              subsystems.SelectorStore
 	statusReporter             subsystems.DataSourceStatusReporter
 	client                     *http.Client
 	headers                    http.Header
@@ -96,11 +97,13 @@ type StreamProcessor struct {
 func NewStreamProcessor(
 	context subsystems.ClientContext,
 	dataDestination subsystems.DataDestination,
+	selectorStore subsystems.SelectorStore,
 	statusReporter subsystems.DataSourceStatusReporter,
 	cfg datasource.StreamConfig,
 ) *StreamProcessor {
 	sp := &StreamProcessor{
 		dataDestination: dataDestination,
+		selectorStore:   selectorStore,
 		statusReporter:  statusReporter,
 		headers:         context.GetHTTP().DefaultHeaders,
 		loggers:         context.GetLogging().Loggers,
@@ -118,6 +121,11 @@ func NewStreamProcessor(
 	// sure it's zero and not the usual configured default. What we do want is a *connection* timeout,
 	// which is set by Config.newHTTPClient as a property of the Dialer.
 	sp.client.Timeout = 0
+	// The eventsource library reuses the same request for every reconnection attempt, so the basis parameter
+	// has to be added by the transport, where it can reflect the data we have at the time of each attempt.
+	if selectorStore != nil {
+		sp.client.Transport = &basisTransport{base: sp.client.Transport, selectorStore: selectorStore}
+	}
 
 	return sp
 }
@@ -209,9 +217,11 @@ func (sp *StreamProcessor) consumeStream(stream *es.Stream, closeWhenReady chan<
 					break
 				}
 
-				if serverIntent.Payloads[0].Code == "none" {
+				if serverIntent.Payloads[0].Code == fdv2proto.IntentNone {
+					// The data we already have, as identified by the basis we sent, is up to date.
 					sp.loggers.Info("Server intent is none, skipping")
-					continue
+					sp.setInitializedAndNotifyClient(true, closeWhenReady)
+					break
 				}
 
 				currentChangeSet = changeSet{events: make([]es.Event, 0), intent: &serverIntent}
@@ -273,7 +283,9 @@ func (sp *StreamProcessor) consumeStream(stream *es.Stream, closeWhenReady chan<
 						sp.setInitializedAndNotifyClient(true, closeWhenReady)
 					}
 				case fdv2proto.IntentTransferChanges:
+					// This may be the first payload on the connection, if we sent a basis when connecting.
 					sp.dataDestination.ApplyDelta(updates, selector, true)
+					sp.setInitializedAndNotifyClient(true, closeWhenReady)
 				}
 
 				currentChangeSet = changeSet{events: make([]es.Event, 0)}
@@ -422,6 +434,11 @@ func (sp *StreamProcessor) logConnectionResult(success bool) {
 	}
 }
 
+//nolint:revive // no doc comment for standard method
+func (sp *StreamProcessor) Name() string {
+	return "StreamingDataSourceV2"
+}
+
 //nolint:revive // no doc comment for standard method
 func (sp *StreamProcessor) Close() error {
 	sp.closeOnce.Do(func() {
@@ -19,6 +19,7 @@ type dataStoreStatusPoller struct {
 	refreshOnRecovery bool
 	pollCloser        chan struct{}
 	closeOnce         sync.Once
+	closed            bool
 	loggers           ldlog.Loggers
 }
 
@@ -59,7 +60,7 @@ func (m *dataStoreStatusPoller) UpdateAvailability(available bool) {
 	m.statusUpdater(newStatus)
 
 	// If the store has just become unavailable, start a poller to detect when it comes back.
-	if !available {
+	if !available && !m.closed {
 		m.loggers.Warn("Detected persistent store unavailability; updates will be cached until it recovers")
 		// Start a goroutine to poll until the store starts working again or we shut down.
 		m.pollCloser = m.startStatusPoller()
@@ -69,6 +70,9 @@ func (m *dataStoreStatusPoller) UpdateAvailability(available bool) {
 // Close shuts down all channels and goroutines used by the manager.
 func (m *dataStoreStatusPoller) Close() {
 	m.closeOnce.Do(func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		m.closed = true
 		if m.pollCloser != nil {
 			close(m.pollCloser)
 			m.pollCloser = nil
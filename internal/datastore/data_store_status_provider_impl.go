@@ -1,6 +1,8 @@
 package datastore
 
 import (
+	"context"
+
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
 )
@@ -38,3 +40,18 @@ func (d *dataStoreStatusProviderImpl) AddStatusListener() <-chan interfaces.Data
 func (d *dataStoreStatusProviderImpl) RemoveStatusListener(ch <-chan interfaces.DataStoreStatus) {
 	d.dataStoreUpdates.getBroadcaster().RemoveListener(ch)
 }
+
+func (d *dataStoreStatusProviderImpl) AddStatusListenerFunc(
+	ctx context.Context,
+	listener func(interfaces.DataStoreStatus),
+	options interfaces.ListenerOptions,
+) func() {
+	return d.dataStoreUpdates.getBroadcaster().AddCallback(ctx, listener, options)
+}
+
+func (d *dataStoreStatusProviderImpl) AddStatusListenerCtx(
+	ctx context.Context,
+	options interfaces.ListenerOptions,
+) <-chan interfaces.DataStoreStatus {
+	return d.dataStoreUpdates.getBroadcaster().AddListenerCtx(ctx, options)
+}
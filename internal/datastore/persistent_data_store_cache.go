@@ -0,0 +1,139 @@
+package datastore
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// persistentDataStoreCache is the in-memory cache used by persistentDataStoreWrapper. It is a map of
+// values that each expire after a TTL, optionally limited to a maximum number of entries, in which case the
+// least recently used entries are evicted first.
+//
+// Expired entries are removed when they are read, and also every cachePurgeInterval when a value is stored,
+// so that keys that are never read again do not stay in memory. If keepExpired is true, expired entries are
+// not removed in either case, so that the wrapper can still return them while it is refreshing them; they are
+// only removed by eviction or by being replaced.
+type persistentDataStoreCache struct {
+	ttl         time.Duration
+	maxEntries  int
+	keepExpired bool
+	entries     map[string]*list.Element
+	lru         *list.List
+	nextPurge   time.Time
+	now         func() time.Time
+	lock        sync.Mutex
+}
+
+// cachePurgeInterval is how often expired entries are removed from the cache. This is the same interval
+// that was used by the go-cache package, which the cache previously used.
+const cachePurgeInterval = 5 * time.Minute
+
+type persistentDataStoreCacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time // zero if the entry never expires
+}
+
+// newPersistentDataStoreCache creates a persistentDataStoreCache. A negative ttl means that entries never
+// expire, and a maxEntries of zero or less means that there is no limit.
+func newPersistentDataStoreCache(ttl time.Duration, maxEntries int, keepExpired bool) *persistentDataStoreCache {
+	return &persistentDataStoreCache{
+		ttl:         ttl,
+		maxEntries:  maxEntries,
+		keepExpired: keepExpired,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		now:         time.Now,
+	}
+}
+
+// get returns the cached value for a key, if any, and whether it has not yet expired. An expired value is
+// only returned if keepExpired is true.
+func (c *persistentDataStoreCache) get(key string) (value interface{}, fresh bool, present bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, false
+	}
+	entry := elem.Value.(*persistentDataStoreCacheEntry)
+	fresh = entry.expiresAt.IsZero() || c.now().Before(entry.expiresAt)
+	if !fresh && !c.keepExpired {
+		c.removeElement(elem)
+		return nil, false, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.value, fresh, true
+}
+
+// set stores a value that expires after the cache's TTL.
+func (c *persistentDataStoreCache) set(key string, value interface{}) {
+	c.setWithTTL(key, value, c.ttl)
+}
+
+// setWithTTL stores a value that expires after the specified TTL; a negative TTL means it never expires.
+func (c *persistentDataStoreCache) setWithTTL(key string, value interface{}, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	c.purgeExpiredIfDue(now)
+	var expiresAt time.Time
+	if ttl >= 0 {
+		expiresAt = now.Add(ttl)
+	}
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*persistentDataStoreCacheEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&persistentDataStoreCacheEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *persistentDataStoreCache) delete(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+func (c *persistentDataStoreCache) flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// purgeExpiredIfDue removes all expired entries, if cachePurgeInterval has passed since it last did so. The
+// caller must hold the lock.
+func (c *persistentDataStoreCache) purgeExpiredIfDue(now time.Time) {
+	if c.keepExpired {
+		return
+	}
+	if c.nextPurge.IsZero() {
+		c.nextPurge = now.Add(cachePurgeInterval)
+		return
+	}
+	if now.Before(c.nextPurge) {
+		return
+	}
+	c.nextPurge = now.Add(cachePurgeInterval)
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if expiresAt := elem.Value.(*persistentDataStoreCacheEntry).expiresAt; !expiresAt.IsZero() &&
+			!now.Before(expiresAt) {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+}
+
+func (c *persistentDataStoreCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*persistentDataStoreCacheEntry).key)
+}
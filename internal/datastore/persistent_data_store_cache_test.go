@@ -0,0 +1,149 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCacheClock struct {
+	now time.Time
+}
+
+func (c *fakeCacheClock) get() time.Time {
+	return c.now
+}
+
+func makeTestPersistentDataStoreCache(
+	ttl time.Duration,
+	maxEntries int,
+	keepExpired bool,
+) (*persistentDataStoreCache, *fakeCacheClock) {
+	clock := &fakeCacheClock{now: time.Unix(1000, 0)}
+	c := newPersistentDataStoreCache(ttl, maxEntries, keepExpired)
+	c.now = clock.get
+	return c, clock
+}
+
+func assertCached(t *testing.T, c *persistentDataStoreCache, key string, value interface{}, fresh bool) {
+	t.Helper()
+	v, f, present := c.get(key)
+	assert.True(t, present, "key %s should be present", key)
+	assert.Equal(t, value, v)
+	assert.Equal(t, fresh, f)
+}
+
+func assertNotCached(t *testing.T, c *persistentDataStoreCache, key string) {
+	t.Helper()
+	_, _, present := c.get(key)
+	assert.False(t, present, "key %s should not be present", key)
+}
+
+func TestPersistentDataStoreCache(t *testing.T) {
+	t.Run("expired entry is removed", func(t *testing.T) {
+		c, clock := makeTestPersistentDataStoreCache(time.Minute, 0, false)
+		c.set("a", 1)
+		assertCached(t, c, "a", 1, true)
+
+		clock.now = clock.now.Add(time.Minute)
+		assertNotCached(t, c, "a")
+		assert.Len(t, c.entries, 0)
+	})
+
+	t.Run("expired entry is kept if keepExpired is true", func(t *testing.T) {
+		c, clock := makeTestPersistentDataStoreCache(time.Minute, 0, true)
+		c.set("a", 1)
+
+		clock.now = clock.now.Add(time.Minute)
+		assertCached(t, c, "a", 1, false)
+
+		c.set("a", 2)
+		assertCached(t, c, "a", 2, true)
+	})
+
+	t.Run("expired entries that are not read are purged", func(t *testing.T) {
+		c, clock := makeTestPersistentDataStoreCache(time.Minute, 0, false)
+		c.setWithTTL("a", 1, time.Second)
+		c.set("b", 2)
+		c.setWithTTL("c", 3, -1)
+
+		clock.now = clock.now.Add(time.Minute)
+		c.set("d", 4) // not due for a purge yet
+		assert.Len(t, c.entries, 4)
+
+		clock.now = clock.now.Add(cachePurgeInterval)
+		c.set("e", 5)
+		assert.Len(t, c.entries, 2)
+		assert.Equal(t, 2, c.lru.Len())
+		assertCached(t, c, "c", 3, true)
+		assertCached(t, c, "e", 5, true)
+	})
+
+	t.Run("expired entries are not purged if keepExpired is true", func(t *testing.T) {
+		c, clock := makeTestPersistentDataStoreCache(time.Minute, 0, true)
+		c.set("a", 1)
+
+		clock.now = clock.now.Add(cachePurgeInterval * 2)
+		c.set("b", 2)
+		clock.now = clock.now.Add(cachePurgeInterval * 2)
+		c.set("c", 3)
+		assertCached(t, c, "a", 1, false)
+	})
+
+	t.Run("negative TTL never expires", func(t *testing.T) {
+		c, clock := makeTestPersistentDataStoreCache(-1, 0, false)
+		c.set("a", 1)
+
+		clock.now = clock.now.Add(time.Hour * 24 * 365)
+		assertCached(t, c, "a", 1, true)
+	})
+
+	t.Run("setWithTTL", func(t *testing.T) {
+		c, clock := makeTestPersistentDataStoreCache(time.Minute, 0, false)
+		c.setWithTTL("a", 1, time.Second)
+		c.set("b", 2)
+
+		clock.now = clock.now.Add(time.Second)
+		assertNotCached(t, c, "a")
+		assertCached(t, c, "b", 2, true)
+	})
+
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		c, _ := makeTestPersistentDataStoreCache(time.Minute, 2, false)
+		c.set("a", 1)
+		c.set("b", 2)
+		assertCached(t, c, "a", 1, true)
+
+		c.set("c", 3)
+		assertNotCached(t, c, "b")
+		assertCached(t, c, "a", 1, true)
+		assertCached(t, c, "c", 3, true)
+
+		c.set("a", 4) // replacing an entry does not evict anything
+		assertCached(t, c, "a", 4, true)
+		assertCached(t, c, "c", 3, true)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c, _ := makeTestPersistentDataStoreCache(time.Minute, 0, false)
+		c.set("a", 1)
+		c.set("b", 2)
+
+		c.delete("a")
+		c.delete("unknown")
+		assertNotCached(t, c, "a")
+		assertCached(t, c, "b", 2, true)
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		c, _ := makeTestPersistentDataStoreCache(time.Minute, 0, false)
+		c.set("a", 1)
+		c.set("b", 2)
+
+		c.flush()
+		assertNotCached(t, c, "a")
+		assertNotCached(t, c, "b")
+		assert.Equal(t, 0, c.lru.Len())
+	})
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
 	st "github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
 
-	"github.com/patrickmn/go-cache"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/singleflight"
 )
@@ -20,12 +19,40 @@ type persistentDataStoreWrapper struct {
 	core             subsystems.PersistentDataStore
 	dataStoreUpdates subsystems.DataStoreUpdateSink
 	statusPoller     *dataStoreStatusPoller
-	cache            *cache.Cache
-	cacheTTL         time.Duration
+	cache            *persistentDataStoreCache
+	cacheConfig      PersistentDataStoreCacheConfig
 	requests         singleflight.Group
 	loggers          ldlog.Loggers
 	inited           bool
 	initLock         sync.RWMutex
+	preloaded        chan struct{}
+	preloadedOnce    sync.Once
+	preloadLock      sync.Mutex
+	closer           chan struct{}
+	closeOnce        sync.Once
+}
+
+// PersistentDataStoreCacheConfig contains the caching options for NewPersistentDataStoreWrapper.
+type PersistentDataStoreCacheConfig struct {
+	// TTL is how long items are cached. Zero disables caching, and a negative value means items are cached
+	// forever.
+	TTL time.Duration
+	// NotFoundTTL, if greater than zero, is used instead of TTL for items that the store did not have, or
+	// that were deleted. It is ignored if TTL is negative.
+	NotFoundTTL time.Duration
+	// MaxEntries, if greater than zero, is the maximum number of cache entries; the least recently used
+	// entries are evicted first. It is ignored if TTL is negative, because then the cache has to hold all
+	// of the data.
+	MaxEntries int
+	// StaleWhileRevalidate means that an expired item is still returned, while it is reloaded from the
+	// store in the background, rather than making the caller wait for the store.
+	StaleWhileRevalidate bool
+	// Preload means that all flags and segments are read into the cache as soon as the wrapper is created.
+	// It is ignored if caching is disabled.
+	Preload bool
+	// PreloadRefreshInterval, if greater than zero and Preload is true, is how often all flags and segments
+	// are read into the cache again.
+	PreloadRefreshInterval time.Duration
 }
 
 const initCheckedKey = "$initChecked"
@@ -35,21 +62,23 @@ const initCheckedKey = "$initChecked"
 func NewPersistentDataStoreWrapper(
 	core subsystems.PersistentDataStore,
 	dataStoreUpdates subsystems.DataStoreUpdateSink,
-	cacheTTL time.Duration,
+	cacheConfig PersistentDataStoreCacheConfig,
 	loggers ldlog.Loggers,
 ) subsystems.DataStore {
-	var myCache *cache.Cache
-	if cacheTTL != 0 {
-		myCache = cache.New(cacheTTL, 5*time.Minute)
-		// Note that the documented behavior of go-cache is that if cacheTTL is negative, the
-		// cache never expires. That is consistent with we've defined the parameter.
+	var myCache *persistentDataStoreCache
+	if cacheConfig.TTL != 0 {
+		maxEntries := cacheConfig.MaxEntries
+		if cacheConfig.TTL < 0 {
+			maxEntries = 0
+		}
+		myCache = newPersistentDataStoreCache(cacheConfig.TTL, maxEntries, cacheConfig.StaleWhileRevalidate)
 	}
 
 	w := &persistentDataStoreWrapper{
 		core:             core,
 		dataStoreUpdates: dataStoreUpdates,
 		cache:            myCache,
-		cacheTTL:         cacheTTL,
+		cacheConfig:      cacheConfig,
 		loggers:          loggers,
 	}
 
@@ -57,17 +86,25 @@ func NewPersistentDataStoreWrapper(
 		true,
 		w.pollAvailabilityAfterOutage,
 		dataStoreUpdates.UpdateStatus,
-		myCache == nil || cacheTTL > 0, // needsRefresh=true unless we're in infinite cache mode
+		myCache == nil || cacheConfig.TTL > 0, // needsRefresh=true unless we're in infinite cache mode
 		loggers,
 	)
 
+	if myCache != nil && cacheConfig.Preload {
+		w.preloaded = make(chan struct{})
+		w.closer = make(chan struct{})
+		go w.runPreload()
+	}
+
 	return w
 }
 
 func (w *persistentDataStoreWrapper) Init(allData []st.Collection) error {
+	w.preloadLock.Lock()
+	defer w.preloadLock.Unlock()
 	err := w.initCore(allData)
 	if w.cache != nil {
-		w.cache.Flush()
+		w.cache.flush()
 	}
 	if err != nil && !w.hasInfiniteCache() {
 		// If the underlying store failed to do the update, and we've got an expiring cache, then:
@@ -97,23 +134,32 @@ func (w *persistentDataStoreWrapper) Get(kind st.DataKind, key string) (st.ItemD
 		return item, err
 	}
 	cacheKey := dataStoreCacheKey(kind, key)
-	if data, present := w.cache.Get(cacheKey); present {
-		if item, ok := data.(st.ItemDescriptor); ok {
-			return item, nil
-		}
-	}
-	// Item was not cached or cached value was not valid. Use singleflight to ensure that we'll only
-	// do this core query once even if multiple goroutines are requesting it
 	reqKey := fmt.Sprintf("get:%s:%s", kind.GetName(), key)
-	itemIntf, err, _ := w.requests.Do(reqKey, func() (interface{}, error) {
+	query := func() (interface{}, error) {
 		item, err := w.getAndDeserializeItem(kind, key)
 		w.processError(err)
 		if err == nil {
-			w.cache.Set(cacheKey, item, cache.DefaultExpiration)
+			if item.Item == nil && w.cacheConfig.NotFoundTTL > 0 && !w.hasInfiniteCache() {
+				w.cache.setWithTTL(cacheKey, item, w.cacheConfig.NotFoundTTL)
+			} else {
+				w.cache.set(cacheKey, item)
+			}
 			return item, nil
 		}
 		return nil, err
-	})
+	}
+	if data, fresh, present := w.cache.get(cacheKey); present {
+		if item, ok := data.(st.ItemDescriptor); ok {
+			if !fresh {
+				// We're in stale-while-revalidate mode; reload the item without waiting for it
+				w.requests.DoChan(reqKey, query)
+			}
+			return item, nil
+		}
+	}
+	// Item was not cached or cached value was not valid. Use singleflight to ensure that we'll only
+	// do this core query once even if multiple goroutines are requesting it
+	itemIntf, err, _ := w.requests.Do(reqKey, query)
 	if err != nil || itemIntf == nil {
 		return st.ItemDescriptor{}.NotFound(), err
 	}
@@ -133,23 +179,28 @@ func (w *persistentDataStoreWrapper) GetAll(kind st.DataKind) ([]st.KeyedItemDes
 	}
 	// Check whether we have a cache item for the entire data set
 	cacheKey := dataStoreAllItemsCacheKey(kind)
-	if data, present := w.cache.Get(cacheKey); present {
-		if items, ok := data.([]st.KeyedItemDescriptor); ok {
-			return items, nil
-		}
-	}
-	// Data set was not cached or cached value was not valid. Use singleflight to ensure that we'll only
-	// do this core query once even if multiple goroutines are requesting it
 	reqKey := fmt.Sprintf("all:%s", kind.GetName())
-	itemsIntf, err, _ := w.requests.Do(reqKey, func() (interface{}, error) {
+	query := func() (interface{}, error) {
 		items, err := w.getAllAndDeserialize(kind)
 		w.processError(err)
 		if err == nil {
-			w.cache.Set(cacheKey, items, cache.DefaultExpiration)
+			w.cache.set(cacheKey, items)
 			return items, nil
 		}
 		return nil, err
-	})
+	}
+	if data, fresh, present := w.cache.get(cacheKey); present {
+		if items, ok := data.([]st.KeyedItemDescriptor); ok {
+			if !fresh {
+				// We're in stale-while-revalidate mode; reload the data set without waiting for it
+				w.requests.DoChan(reqKey, query)
+			}
+			return items, nil
+		}
+	}
+	// Data set was not cached or cached value was not valid. Use singleflight to ensure that we'll only
+	// do this core query once even if multiple goroutines are requesting it
+	itemsIntf, err, _ := w.requests.Do(reqKey, query)
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +217,8 @@ func (w *persistentDataStoreWrapper) Upsert(
 	key string,
 	newItem st.ItemDescriptor,
 ) (bool, error) {
+	w.preloadLock.Lock()
+	defer w.preloadLock.Unlock()
 	serializedItem := w.serialize(kind, newItem)
 	updated, err := w.core.Upsert(kind, key, serializedItem)
 	w.processError(err)
@@ -183,24 +236,24 @@ func (w *persistentDataStoreWrapper) Upsert(
 		allCacheKey := dataStoreAllItemsCacheKey(kind)
 		if err == nil {
 			if updated {
-				w.cache.Set(cacheKey, newItem, cache.DefaultExpiration)
+				w.cache.set(cacheKey, newItem)
 				// If the cache has a finite TTL, then we should remove the "all items" cache entry to force
 				// a reread the next time All is called. However, if it's an infinite TTL, we need to just
 				// update the item within the existing "all items" entry (since we want things to still work
 				// even if the underlying store is unavailable).
 				if w.hasInfiniteCache() {
-					if data, present := w.cache.Get(allCacheKey); present {
+					if data, _, present := w.cache.get(allCacheKey); present {
 						if items, ok := data.([]st.KeyedItemDescriptor); ok {
-							w.cache.Set(allCacheKey, updateSingleItem(items, key, newItem), cache.DefaultExpiration)
+							w.cache.set(allCacheKey, updateSingleItem(items, key, newItem))
 						}
 					}
 				} else {
-					w.cache.Delete(allCacheKey)
+					w.cache.delete(allCacheKey)
 				}
 			} else {
 				// there was a concurrent modification elsewhere - update the cache to get the new state
-				w.cache.Delete(cacheKey)
-				w.cache.Delete(allCacheKey)
+				w.cache.delete(cacheKey)
+				w.cache.delete(allCacheKey)
 				_, _ = w.Get(kind, key) // doing this query repopulates the cache
 			}
 		} else {
@@ -208,14 +261,14 @@ func (w *persistentDataStoreWrapper) Upsert(
 			// ahead and update the cache so that it always has the latest data; we may be able to use the
 			// cached data to repopulate the store later if it starts working again.
 			if w.hasInfiniteCache() {
-				w.cache.Set(cacheKey, newItem, cache.DefaultExpiration)
+				w.cache.set(cacheKey, newItem)
 				cachedItems := []st.KeyedItemDescriptor{}
-				if data, present := w.cache.Get(allCacheKey); present {
+				if data, _, present := w.cache.get(allCacheKey); present {
 					if items, ok := data.([]st.KeyedItemDescriptor); ok {
 						cachedItems = items
 					}
 				}
-				w.cache.Set(allCacheKey, updateSingleItem(cachedItems, key, newItem), cache.DefaultExpiration)
+				w.cache.set(allCacheKey, updateSingleItem(cachedItems, key, newItem))
 			}
 		}
 	}
@@ -231,7 +284,7 @@ func (w *persistentDataStoreWrapper) IsInitialized() bool {
 	}
 
 	if w.cache != nil {
-		if _, found := w.cache.Get(initCheckedKey); found {
+		if _, fresh, _ := w.cache.get(initCheckedKey); fresh {
 			return false
 		}
 	}
@@ -242,10 +295,10 @@ func (w *persistentDataStoreWrapper) IsInitialized() bool {
 		defer w.initLock.Unlock()
 		w.inited = true
 		if w.cache != nil {
-			w.cache.Delete(initCheckedKey)
+			w.cache.delete(initCheckedKey)
 		}
 	} else if w.cache != nil {
-		w.cache.Set(initCheckedKey, "", cache.DefaultExpiration)
+		w.cache.set(initCheckedKey, "")
 	}
 	return newValue
 }
@@ -255,10 +308,121 @@ func (w *persistentDataStoreWrapper) IsStatusMonitoringEnabled() bool {
 }
 
 func (w *persistentDataStoreWrapper) Close() error {
+	if w.closer != nil {
+		w.closeOnce.Do(func() { close(w.closer) })
+		w.preloadedOnce.Do(func() { close(w.preloaded) })
+	}
 	w.statusPoller.Close()
 	return w.core.Close()
 }
 
+// Preloaded returns a channel that is closed once all flags and segments have been read into the cache
+// for the first time, or when the store is closed. It returns nil if the cache is not being preloaded.
+func (w *persistentDataStoreWrapper) Preloaded() <-chan struct{} {
+	if w.preloaded == nil {
+		return nil
+	}
+	return w.preloaded
+}
+
+func (w *persistentDataStoreWrapper) runPreload() {
+	w.preload()
+	if w.cacheConfig.PreloadRefreshInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(w.cacheConfig.PreloadRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.preload()
+		case <-w.closer:
+			return
+		}
+	}
+}
+
+// preload reads all flags and segments from the underlying store into the cache. If that fails, the
+// status poller will detect when the store is available again, and pollAvailabilityAfterOutage will
+// try again if the cache has not yet been preloaded.
+//
+// Init and Upsert hold the same lock, so that an update cannot happen between reading the store and
+// updating the cache, which would put the older item back in the cache.
+func (w *persistentDataStoreWrapper) preload() {
+	w.preloadLock.Lock()
+	defer w.preloadLock.Unlock()
+	select {
+	case <-w.closer:
+		return
+	default:
+	}
+	kinds := datakinds.AllDataKinds()
+	allData := make([]st.Collection, 0, len(kinds))
+	for _, kind := range kinds {
+		items, err := w.getAllAndDeserialize(kind)
+		if err != nil {
+			w.loggers.Warnf("Unable to preload data store cache: %s", err)
+			w.processError(err)
+			return
+		}
+		allData = append(allData, st.Collection{Kind: kind, Items: items})
+	}
+	for _, coll := range allData {
+		w.cacheItems(coll.Kind, w.mergeWithCachedItems(coll.Kind, coll.Items))
+	}
+	w.preloadedOnce.Do(func() {
+		w.loggers.Info("Preloaded data store cache")
+		close(w.preloaded)
+	})
+}
+
+// mergeWithCachedItems combines items that were read from the underlying store for preloading with the
+// items that are already cached. In infinite cache mode, the cache can have updates that the store did
+// not receive because it was unavailable, and those will be written to the store once it is available
+// again; so a cached item is kept if its version is higher, and an item that the store does not have at
+// all is kept in infinite cache mode. Otherwise it is removed from the cache, since it would stay there
+// until it expired.
+func (w *persistentDataStoreWrapper) mergeWithCachedItems(
+	kind st.DataKind,
+	items []st.KeyedItemDescriptor,
+) []st.KeyedItemDescriptor {
+	merged := make([]st.KeyedItemDescriptor, 0, len(items))
+	storedKeys := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		storedKeys[item.Key] = struct{}{}
+		if data, _, present := w.cache.get(dataStoreCacheKey(kind, item.Key)); present {
+			if cachedItem, ok := data.(st.ItemDescriptor); ok && cachedItem.Version > item.Item.Version {
+				item.Item = cachedItem
+			}
+		}
+		merged = append(merged, item)
+	}
+	if data, _, present := w.cache.get(dataStoreAllItemsCacheKey(kind)); present {
+		if cachedItems, ok := data.([]st.KeyedItemDescriptor); ok {
+			for _, cachedItem := range cachedItems {
+				if _, ok := storedKeys[cachedItem.Key]; ok {
+					continue
+				}
+				if w.hasInfiniteCache() {
+					merged = append(merged, cachedItem)
+				} else {
+					w.cache.delete(dataStoreCacheKey(kind, cachedItem.Key))
+				}
+			}
+		}
+	}
+	return merged
+}
+
+func (w *persistentDataStoreWrapper) isPreloaded() bool {
+	select {
+	case <-w.preloaded:
+		return true
+	default:
+		return false
+	}
+}
+
 func (w *persistentDataStoreWrapper) pollAvailabilityAfterOutage() bool {
 	if !w.core.IsStoreAvailable() {
 		return false
@@ -271,7 +435,7 @@ func (w *persistentDataStoreWrapper) pollAvailabilityAfterOutage() bool {
 		allData := make([]st.Collection, 0, len(kinds))
 		for _, kind := range kinds {
 			allCacheKey := dataStoreAllItemsCacheKey(kind)
-			if data, present := w.cache.Get(allCacheKey); present {
+			if data, _, present := w.cache.get(allCacheKey); present {
 				if items, ok := data.([]st.KeyedItemDescriptor); ok {
 					allData = append(allData, st.Collection{Kind: kind, Items: items})
 				}
@@ -289,11 +453,15 @@ func (w *persistentDataStoreWrapper) pollAvailabilityAfterOutage() bool {
 			// in infinite cache mode, we set it even if the database update failed.
 		}
 	}
+	if w.preloaded != nil && !w.isPreloaded() {
+		// This is done asynchronously because it may report the store as unavailable again.
+		go w.preload()
+	}
 	return true
 }
 
 func (w *persistentDataStoreWrapper) hasInfiniteCache() bool {
-	return w.cache != nil && w.cacheTTL < 0
+	return w.cache != nil && w.cacheConfig.TTL < 0
 }
 func dataStoreCacheKey(kind st.DataKind, key string) string {
 	return kind.GetName() + ":" + key
@@ -351,10 +519,10 @@ func (w *persistentDataStoreWrapper) cacheItems(
 ) {
 	if w.cache != nil {
 		copyOfItems := slices.Clone(items)
-		w.cache.Set(dataStoreAllItemsCacheKey(kind), copyOfItems, cache.DefaultExpiration)
+		w.cache.set(dataStoreAllItemsCacheKey(kind), copyOfItems)
 
 		for _, item := range items {
-			w.cache.Set(dataStoreCacheKey(kind, item.Key), item.Item, cache.DefaultExpiration)
+			w.cache.set(dataStoreCacheKey(kind, item.Key), item.Item)
 		}
 	}
 }
@@ -0,0 +1,194 @@
+package datastore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	st "github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+
+	th "github.com/launchdarkly/go-test-helpers/v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makePreloadingWrapper(
+	t *testing.T,
+	core *mocks.MockPersistentDataStore,
+	cacheConfig PersistentDataStoreCacheConfig,
+) *persistentDataStoreWrapper {
+	broadcaster := internal.NewBroadcaster[interfaces.DataStoreStatus]()
+	t.Cleanup(broadcaster.Close)
+	cacheConfig.Preload = true
+	w := NewPersistentDataStoreWrapper(core, NewDataStoreUpdateSinkImpl(broadcaster), cacheConfig,
+		sharedtest.NewTestLoggers()).(*persistentDataStoreWrapper)
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+func serializedFlag(flag ldmodel.FeatureFlag) st.SerializedItemDescriptor {
+	return st.SerializedItemDescriptor{
+		Version:        flag.Version,
+		SerializedItem: datakinds.Features.Serialize(sharedtest.FlagDescriptor(flag)),
+	}
+}
+
+func initCoreWithData(t *testing.T, core *mocks.MockPersistentDataStore, data []st.Collection) {
+	var serialized []st.SerializedCollection
+	for _, coll := range data {
+		sc := st.SerializedCollection{Kind: coll.Kind}
+		for _, item := range coll.Items {
+			sc.Items = append(sc.Items, st.KeyedSerializedItemDescriptor{
+				Key:  item.Key,
+				Item: st.SerializedItemDescriptor{Version: item.Item.Version, SerializedItem: coll.Kind.Serialize(item.Item)},
+			})
+		}
+		serialized = append(serialized, sc)
+	}
+	require.NoError(t, core.Init(serialized))
+}
+
+func TestPersistentDataStoreWrapperPreload(t *testing.T) {
+	flag1v1 := ldbuilders.NewFlagBuilder("flag1").Version(1).Build()
+	flag1v2 := ldbuilders.NewFlagBuilder("flag1").Version(2).Build()
+	flag2 := ldbuilders.NewFlagBuilder("flag2").Version(1).Build()
+	segment1 := ldbuilders.NewSegmentBuilder("segment1").Version(1).Build()
+
+	t.Run("Preloaded is nil if preloading is not enabled", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		broadcaster := internal.NewBroadcaster[interfaces.DataStoreStatus]()
+		defer broadcaster.Close()
+		w := NewPersistentDataStoreWrapper(core, NewDataStoreUpdateSinkImpl(broadcaster),
+			PersistentDataStoreCacheConfig{TTL: time.Minute}, sharedtest.NewTestLoggers())
+		defer w.Close()
+
+		assert.Nil(t, w.(*persistentDataStoreWrapper).Preloaded())
+	})
+
+	t.Run("Preloaded is nil if caching is disabled", func(t *testing.T) {
+		w := makePreloadingWrapper(t, mocks.NewMockPersistentDataStore(), PersistentDataStoreCacheConfig{})
+		assert.Nil(t, w.Preloaded())
+	})
+
+	t.Run("caches all flags and segments", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		initCoreWithData(t, core, sharedtest.NewDataSetBuilder().Flags(flag1v1, flag2).Segments(segment1).Build())
+		w := makePreloadingWrapper(t, core, PersistentDataStoreCacheConfig{TTL: time.Minute})
+
+		th.AssertChannelClosed(t, w.Preloaded(), time.Second)
+		require.NoError(t, core.Init(nil)) // the cache should not need to read from the store again
+
+		item, err := w.Get(datakinds.Features, flag1v1.Key)
+		require.NoError(t, err)
+		assert.Equal(t, flag1v1.Version, item.Version)
+		item, err = w.Get(datakinds.Segments, segment1.Key)
+		require.NoError(t, err)
+		assert.Equal(t, segment1.Version, item.Version)
+		items, err := w.GetAll(datakinds.Features)
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+	})
+
+	t.Run("retries when store becomes available", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		initCoreWithData(t, core, sharedtest.NewDataSetBuilder().Flags(flag1v1).Build())
+		core.SetAvailable(false)
+		core.SetFakeError(errors.New("sorry"))
+		w := makePreloadingWrapper(t, core, PersistentDataStoreCacheConfig{TTL: time.Minute})
+
+		th.AssertChannelNotClosed(t, w.Preloaded(), time.Millisecond*100)
+
+		core.SetFakeError(nil)
+		core.SetAvailable(true)
+		th.AssertChannelClosed(t, w.Preloaded(), time.Second*2)
+		item, _ := w.Get(datakinds.Features, flag1v1.Key)
+		assert.Equal(t, flag1v1.Version, item.Version)
+	})
+
+	t.Run("refreshes on interval", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		initCoreWithData(t, core, sharedtest.NewDataSetBuilder().Flags(flag1v1, flag2).Build())
+		w := makePreloadingWrapper(t, core,
+			PersistentDataStoreCacheConfig{TTL: time.Minute, PreloadRefreshInterval: time.Millisecond * 10})
+		th.AssertChannelClosed(t, w.Preloaded(), time.Second)
+
+		core.ForceSet(datakinds.Features, flag1v1.Key, serializedFlag(flag1v2))
+		core.ForceRemove(datakinds.Features, flag2.Key)
+
+		assert.Eventually(t, func() bool {
+			item1, _ := w.Get(datakinds.Features, flag1v1.Key)
+			item2, _ := w.Get(datakinds.Features, flag2.Key)
+			return item1.Version == flag1v2.Version && item2 == st.ItemDescriptor{}.NotFound()
+		}, time.Second, time.Millisecond*10)
+	})
+
+	t.Run("refresh does not overwrite concurrent update", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		initCoreWithData(t, core, sharedtest.NewDataSetBuilder().Flags(flag1v1).Build())
+		w := makePreloadingWrapper(t, core, PersistentDataStoreCacheConfig{TTL: time.Minute})
+		th.AssertChannelClosed(t, w.Preloaded(), time.Second)
+
+		queryStarted := core.EnableInstrumentedQueries(time.Millisecond * 50)
+		refreshed := make(chan struct{})
+		go func() {
+			w.preload()
+			close(refreshed)
+		}()
+		th.RequireValue(t, queryStarted, time.Second)
+
+		updated, err := w.Upsert(datakinds.Features, flag1v2.Key, sharedtest.FlagDescriptor(flag1v2))
+		require.NoError(t, err)
+		assert.True(t, updated)
+		th.AssertChannelClosed(t, refreshed, time.Second)
+
+		item, err := w.Get(datakinds.Features, flag1v1.Key)
+		require.NoError(t, err)
+		assert.Equal(t, flag1v2.Version, item.Version)
+	})
+
+	t.Run("refresh keeps updates that store did not receive in infinite cache mode", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		initCoreWithData(t, core, sharedtest.NewDataSetBuilder().Flags(flag1v1).Build())
+		w := makePreloadingWrapper(t, core, PersistentDataStoreCacheConfig{TTL: -1})
+		th.AssertChannelClosed(t, w.Preloaded(), time.Second)
+
+		core.SetAvailable(false) // so the status poller does not write the cache to the store
+		core.SetFakeError(errors.New("sorry"))
+		_, err := w.Upsert(datakinds.Features, flag1v2.Key, sharedtest.FlagDescriptor(flag1v2))
+		require.Error(t, err)
+		_, err = w.Upsert(datakinds.Features, flag2.Key, sharedtest.FlagDescriptor(flag2))
+		require.Error(t, err)
+		core.SetFakeError(nil)
+
+		w.preload()
+
+		item1, err := w.Get(datakinds.Features, flag1v1.Key)
+		require.NoError(t, err)
+		assert.Equal(t, flag1v2.Version, item1.Version)
+		item2, err := w.Get(datakinds.Features, flag2.Key)
+		require.NoError(t, err)
+		assert.Equal(t, flag2.Version, item2.Version)
+		items, err := w.GetAll(datakinds.Features)
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+	})
+
+	t.Run("Preloaded is closed when store is closed", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		core.SetAvailable(false)
+		core.SetFakeError(errors.New("sorry"))
+		w := makePreloadingWrapper(t, core, PersistentDataStoreCacheConfig{TTL: time.Minute})
+
+		require.NoError(t, w.Close())
+		th.AssertChannelClosed(t, w.Preloaded(), time.Second)
+	})
+}
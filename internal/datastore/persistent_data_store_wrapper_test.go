@@ -52,7 +52,8 @@ func makePersistentDataStoreWrapper(
 ) subsystems.DataStore {
 	broadcaster := internal.NewBroadcaster[interfaces.DataStoreStatus]()
 	dataStoreUpdates := NewDataStoreUpdateSinkImpl(broadcaster)
-	return NewPersistentDataStoreWrapper(core, dataStoreUpdates, mode.ttl(), s.NewTestLoggers())
+	return NewPersistentDataStoreWrapper(core, dataStoreUpdates, PersistentDataStoreCacheConfig{TTL: mode.ttl()},
+		s.NewTestLoggers())
 }
 
 func TestPersistentDataStoreWrapper(t *testing.T) {
@@ -558,3 +559,152 @@ func testPersistentDataStoreWrapperUpdateFailuresWithCache(t *testing.T, mode te
 		})
 	}
 }
+
+func testWithCacheConfig(
+	t *testing.T,
+	name string,
+	cacheConfig PersistentDataStoreCacheConfig,
+	action func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock),
+) {
+	t.Run(name, func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		broadcaster := internal.NewBroadcaster[interfaces.DataStoreStatus]()
+		defer broadcaster.Close()
+		w := NewPersistentDataStoreWrapper(core, NewDataStoreUpdateSinkImpl(broadcaster), cacheConfig,
+			s.NewTestLoggers()).(*persistentDataStoreWrapper)
+		defer w.Close()
+		clock := &fakeCacheClock{now: time.Unix(1000, 0)}
+		w.cache.now = clock.get
+		action(t, core, w, clock)
+	})
+}
+
+func TestPersistentDataStoreWrapperCacheOptions(t *testing.T) {
+	ttl := 30 * time.Second
+	swr := PersistentDataStoreCacheConfig{TTL: ttl, StaleWhileRevalidate: true}
+
+	testWithCacheConfig(t, "expired item is reloaded before returning it by default", PersistentDataStoreCacheConfig{TTL: ttl}, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		itemv1 := mocks.MockDataItem{Key: "item", Version: 1}
+		itemv2 := mocks.MockDataItem{Key: itemv1.Key, Version: 2}
+
+		core.ForceSet(mocks.MockData, itemv1.Key, itemv1.ToSerializedItemDescriptor())
+		_, _ = w.Get(mocks.MockData, itemv1.Key)
+		core.ForceSet(mocks.MockData, itemv1.Key, itemv2.ToSerializedItemDescriptor())
+		clock.now = clock.now.Add(ttl)
+
+		item, err := w.Get(mocks.MockData, itemv1.Key)
+		require.NoError(t, err)
+		assert.Equal(t, itemv2.ToItemDescriptor(), item)
+	})
+
+	testWithCacheConfig(t, "stale-while-revalidate returns expired item and reloads it", swr, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		itemv1 := mocks.MockDataItem{Key: "item", Version: 1}
+		itemv2 := mocks.MockDataItem{Key: itemv1.Key, Version: 2}
+
+		core.ForceSet(mocks.MockData, itemv1.Key, itemv1.ToSerializedItemDescriptor())
+		_, _ = w.Get(mocks.MockData, itemv1.Key)
+		core.ForceSet(mocks.MockData, itemv1.Key, itemv2.ToSerializedItemDescriptor())
+		clock.now = clock.now.Add(ttl)
+
+		item, err := w.Get(mocks.MockData, itemv1.Key)
+		require.NoError(t, err)
+		assert.Equal(t, itemv1.ToItemDescriptor(), item)
+
+		require.Eventually(t, func() bool {
+			item, _ := w.Get(mocks.MockData, itemv1.Key)
+			return item == itemv2.ToItemDescriptor()
+		}, time.Second, time.Millisecond*10)
+	})
+
+	testWithCacheConfig(t, "stale-while-revalidate returns expired data set and reloads it", swr, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		item1 := mocks.MockDataItem{Key: "item1", Version: 1}
+		item2 := mocks.MockDataItem{Key: "item2", Version: 1}
+
+		core.ForceSet(mocks.MockData, item1.Key, item1.ToSerializedItemDescriptor())
+		_, _ = w.GetAll(mocks.MockData)
+		core.ForceSet(mocks.MockData, item2.Key, item2.ToSerializedItemDescriptor())
+		clock.now = clock.now.Add(ttl)
+
+		items, err := w.GetAll(mocks.MockData)
+		require.NoError(t, err)
+		assert.Len(t, items, 1)
+
+		require.Eventually(t, func() bool {
+			items, _ := w.GetAll(mocks.MockData)
+			return len(items) == 2
+		}, time.Second, time.Millisecond*10)
+	})
+
+	testWithCacheConfig(t, "stale-while-revalidate returns expired item if store fails", swr, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		itemv1 := mocks.MockDataItem{Key: "item", Version: 1}
+
+		core.ForceSet(mocks.MockData, itemv1.Key, itemv1.ToSerializedItemDescriptor())
+		_, _ = w.Get(mocks.MockData, itemv1.Key)
+		core.SetFakeError(errors.New("sorry"))
+		clock.now = clock.now.Add(ttl)
+
+		for i := 0; i < 2; i++ {
+			item, err := w.Get(mocks.MockData, itemv1.Key)
+			require.NoError(t, err)
+			assert.Equal(t, itemv1.ToItemDescriptor(), item)
+		}
+	})
+
+	testWithCacheConfig(t, "not-found TTL", PersistentDataStoreCacheConfig{TTL: ttl, NotFoundTTL: time.Second}, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		item1 := mocks.MockDataItem{Key: "item1", Version: 1}
+		item1v2 := mocks.MockDataItem{Key: item1.Key, Version: 2}
+		item2 := mocks.MockDataItem{Key: "item2", Version: 1}
+
+		core.ForceSet(mocks.MockData, item1.Key, item1.ToSerializedItemDescriptor())
+		_, _ = w.Get(mocks.MockData, item1.Key)
+		item, _ := w.Get(mocks.MockData, item2.Key)
+		assert.Equal(t, st.ItemDescriptor{}.NotFound(), item)
+
+		core.ForceSet(mocks.MockData, item1.Key, item1v2.ToSerializedItemDescriptor())
+		core.ForceSet(mocks.MockData, item2.Key, item2.ToSerializedItemDescriptor())
+		clock.now = clock.now.Add(time.Second)
+
+		item, _ = w.Get(mocks.MockData, item1.Key)
+		assert.Equal(t, item1.ToItemDescriptor(), item) // still cached with the regular TTL
+		item, _ = w.Get(mocks.MockData, item2.Key)
+		assert.Equal(t, item2.ToItemDescriptor(), item)
+	})
+
+	testWithCacheConfig(t, "not-found TTL is ignored with infinite TTL", PersistentDataStoreCacheConfig{TTL: -1, NotFoundTTL: time.Second}, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		item1 := mocks.MockDataItem{Key: "item1", Version: 1}
+
+		item, _ := w.Get(mocks.MockData, item1.Key)
+		assert.Equal(t, st.ItemDescriptor{}.NotFound(), item)
+
+		core.ForceSet(mocks.MockData, item1.Key, item1.ToSerializedItemDescriptor())
+		clock.now = clock.now.Add(time.Second)
+
+		item, _ = w.Get(mocks.MockData, item1.Key)
+		assert.Equal(t, st.ItemDescriptor{}.NotFound(), item)
+	})
+
+	testWithCacheConfig(t, "max entries", PersistentDataStoreCacheConfig{TTL: ttl, MaxEntries: 2}, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		items := []mocks.MockDataItem{{Key: "a", Version: 1}, {Key: "b", Version: 1}, {Key: "c", Version: 1}}
+		for _, item := range items {
+			core.ForceSet(mocks.MockData, item.Key, item.ToSerializedItemDescriptor())
+			_, _ = w.Get(mocks.MockData, item.Key)
+		}
+		for _, item := range items {
+			core.ForceSet(mocks.MockData, item.Key, mocks.MockDataItem{Key: item.Key, Version: 2}.ToSerializedItemDescriptor())
+		}
+
+		item, _ := w.Get(mocks.MockData, "c")
+		assert.Equal(t, 1, item.Version) // still cached
+		item, _ = w.Get(mocks.MockData, "a")
+		assert.Equal(t, 2, item.Version) // was evicted, so it was reloaded
+	})
+
+	testWithCacheConfig(t, "max entries is ignored with infinite TTL", PersistentDataStoreCacheConfig{TTL: -1, MaxEntries: 2}, func(t *testing.T, core *mocks.MockPersistentDataStore, w *persistentDataStoreWrapper, clock *fakeCacheClock) {
+		items := []mocks.MockDataItem{{Key: "a", Version: 1}, {Key: "b", Version: 1}, {Key: "c", Version: 1}}
+		require.NoError(t, w.Init(mocks.MakeMockDataSet(items...)))
+		core.ForceSet(mocks.MockData, "a", mocks.MockDataItem{Key: "a", Version: 2}.ToSerializedItemDescriptor())
+
+		item, _ := w.Get(mocks.MockData, "a")
+		assert.Equal(t, 1, item.Version)
+	})
+}
@@ -63,6 +63,7 @@ func NewFDv1(offline bool, dataStoreFactory subsystems.ComponentConfigurer[subsy
 		return nil, err
 	}
 	system.dataSource = dataSource
+	dataSourceUpdateSink.SetSourceName(dataSourceName(dataSource))
 	system.dataSourceStatusProvider = datasource.NewDataSourceStatusProviderImpl(
 		system.dataSourceStatusBroadcaster,
 		dataSourceUpdateSink,
@@ -91,6 +92,16 @@ func createDataSource(
 	return factory.Build(&contextCopy)
 }
 
+// dataSourceName returns the name that a data source reports for itself in flag change events, or an empty
+// string if it does not have one. This is not part of the DataSource interface, since custom data sources
+// are not required to have a name.
+func dataSourceName(dataSource subsystems.DataSource) string {
+	if named, ok := dataSource.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return ""
+}
+
 //nolint:revive // Data system implementation.
 func (f *FDv1) DataSourceStatusBroadcaster() *internal.Broadcaster[interfaces.DataSourceStatus] {
 	return f.dataSourceStatusBroadcaster
@@ -116,9 +127,31 @@ func (f *FDv1) FlagChangeEventBroadcaster() *internal.Broadcaster[interfaces.Fla
 	return f.flagChangeEventBroadcaster
 }
 
+// storePreloaded returns a channel that is closed when a data store that preloads its cache has finished
+// doing so, or nil if the store does not do that. This is not part of the DataStore interface, since it
+// only applies to the SDK's persistent data store wrapper.
+func storePreloaded(store subsystems.DataStore) <-chan struct{} {
+	if preloading, ok := store.(interface{ Preloaded() <-chan struct{} }); ok {
+		return preloading.Preloaded()
+	}
+	return nil
+}
+
 //nolint:revive // Data system implementation.
 func (f *FDv1) Start(closeWhenReady chan struct{}) {
-	f.dataSource.Start(closeWhenReady)
+	preloaded := storePreloaded(f.dataStore)
+	if preloaded == nil {
+		f.dataSource.Start(closeWhenReady)
+		return
+	}
+	// The SDK is not ready until both the data source and the store's cache are.
+	dataSourceReady := make(chan struct{})
+	f.dataSource.Start(dataSourceReady)
+	go func() {
+		<-dataSourceReady
+		<-preloaded
+		close(closeWhenReady)
+	}()
 }
 
 //nolint:revive // Data system implementation.
@@ -146,7 +179,7 @@ func (f *FDv1) DataAvailability() DataAvailability {
 	if f.offline {
 		return Defaults
 	}
-	if f.dataSource.IsInitialized() {
+	if f.dataSource.IsInitialized() && isPreloaded(storePreloaded(f.dataStore)) {
 		return Refreshed
 	}
 	if f.dataStore.IsInitialized() {
@@ -155,6 +188,19 @@ func (f *FDv1) DataAvailability() DataAvailability {
 	return Defaults
 }
 
+// isPreloaded returns true if the channel returned by storePreloaded is nil or closed.
+func isPreloaded(preloaded <-chan struct{}) bool {
+	if preloaded == nil {
+		return true
+	}
+	select {
+	case <-preloaded:
+		return true
+	default:
+		return false
+	}
+}
+
 //nolint:revive // Data system implementation.
 func (f *FDv1) Store() subsystems.ReadOnlyStore {
 	return f.dataStore
@@ -0,0 +1,523 @@
+package datasystem
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datasource"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datastore"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+type synchronizerRole int
+
+const (
+	primarySynchronizer synchronizerRole = iota
+	secondarySynchronizer
+)
+
+// FDv2 implements the FDv2 data system. Data sources write to a Store through the DataDestination
+// interface, and report their status through the DataSourceStatusReporter interface.
+//
+// When started, FDv2 runs each initializer in order until one of them obtains a basis, and then starts the
+// primary synchronizer. A basis that has no selector is only considered cached, not up to date, until a
+// synchronizer has received data. If a writable persistent store already contains data along with the selector that
+// identifies it, that data is used as the basis instead, and the initializers are skipped. If the primary
+// stays interrupted for longer than the fallback delay, or stops permanently, the secondary synchronizer is
+// started; the primary keeps trying to reconnect, and as soon as it reports that it is valid again the
+// secondary is stopped. The status reported to the application is always that of whichever synchronizer is
+// active.
+//
+// If the persistent store preloads its cache, the data is not considered up to date, and readiness is not
+// signaled, until that has finished, as in FDv1.
+//
+// If a writable persistent store becomes unavailable, the data continues to be served from memory; when the
+// store becomes available again and may be missing updates, the whole of the in-memory data is written to it.
+type FDv2 struct {
+	dataSourceStatusBroadcaster *internal.Broadcaster[interfaces.DataSourceStatus]
+	dataSourceStatusProvider    interfaces.DataSourceStatusProvider
+	dataSourceStatusReporter    *datasource.DataSourceStatusReporterImpl
+	dataStoreStatusBroadcaster  *internal.Broadcaster[interfaces.DataStoreStatus]
+	dataStoreStatusProvider     interfaces.DataStoreStatusProvider
+	flagChangeEventBroadcaster  *internal.Broadcaster[interfaces.FlagChangeEvent]
+	store                       *Store
+
+	// Receives persistent store status updates, if the persistent store is writable.
+	storeStatusCh <-chan interfaces.DataStoreStatus
+
+	// Closed when the persistent store has preloaded its cache; nil if it does not do that.
+	preloaded <-chan struct{}
+
+	// The context used to build the synchronizers, which refers back to this data system.
+	sourceContext *internal.ClientContextImpl
+
+	initializers      []subsystems.DataInitializer
+	primary           subsystems.DataSource
+	secondary         subsystems.DataSource
+	startingSecondary subsystems.DataSource
+	secondaryBuilder  subsystems.ComponentConfigurer[subsystems.DataSource]
+	fallbackDelay     time.Duration
+
+	active        synchronizerRole
+	fallbackTimer *time.Timer
+	initialized   bool
+	closed        bool
+	mu            sync.Mutex
+
+	readyOnce      sync.Once
+	closeWhenReady chan struct{}
+	cancel         context.CancelFunc
+
+	offline bool
+	loggers ldlog.Loggers
+}
+
+// NewFDv2 creates a new FDv2 instance from a data system configurer. Offline determines if the client is in
+// offline mode. If configuration is invalid, an error will be returned.
+func NewFDv2(offline bool, configurer subsystems.ComponentConfigurer[subsystems.DataSystemConfiguration],
+	clientContext *internal.ClientContextImpl) (*FDv2, error) {
+	cfg, err := configurer.Build(clientContext)
+	if err != nil {
+		return nil, err
+	}
+
+	loggers := clientContext.GetLogging().Loggers
+	system := &FDv2{
+		dataSourceStatusBroadcaster: internal.NewBroadcaster[interfaces.DataSourceStatus](),
+		dataStoreStatusBroadcaster:  internal.NewBroadcaster[interfaces.DataStoreStatus](),
+		flagChangeEventBroadcaster:  internal.NewBroadcaster[interfaces.FlagChangeEvent](),
+		fallbackDelay:               cfg.FallbackDelay,
+		offline:                     offline,
+		loggers:                     loggers,
+	}
+
+	system.dataSourceStatusReporter = datasource.NewDataSourceStatusReporterImpl(
+		system.dataSourceStatusBroadcaster,
+		clientContext.GetLogging().LogDataSourceOutageAsErrorAfter,
+		loggers,
+	)
+	system.dataSourceStatusProvider = datasource.NewDataSourceStatusProviderImpl(
+		system.dataSourceStatusBroadcaster,
+		system.dataSourceStatusReporter,
+	)
+
+	system.store = NewStore(loggers).
+		WithChangeTracker(datasource.NewFlagChangeTracker(system.flagChangeEventBroadcaster)).
+		WithStatusReporter(system.dataSourceStatusReporter)
+
+	dataStoreUpdateSink := datastore.NewDataStoreUpdateSinkImpl(system.dataStoreStatusBroadcaster)
+	if cfg.Store != nil {
+		storeContext := *clientContext
+		storeContext.BasicClientContext.DataStoreUpdateSink = dataStoreUpdateSink
+		persistentStore, err := cfg.Store.Build(&storeContext)
+		if err != nil {
+			return nil, err
+		}
+		system.dataStoreStatusProvider = datastore.NewDataStoreStatusProviderImpl(persistentStore, dataStoreUpdateSink)
+		system.store.WithPersistence(persistentStore, cfg.StoreMode, system.dataStoreStatusProvider)
+		system.preloaded = storePreloaded(persistentStore)
+	} else {
+		// Without a persistent store there is nothing to monitor; the in-memory store is only used here to
+		// report that status monitoring is disabled.
+		system.dataStoreStatusProvider = datastore.NewDataStoreStatusProviderImpl(
+			datastore.NewInMemoryDataStore(loggers), dataStoreUpdateSink)
+	}
+
+	if offline {
+		loggers.Info("Starting LaunchDarkly client in offline mode")
+		system.dataSourceStatusReporter.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+		return system, nil
+	}
+
+	if cfg.Store != nil && cfg.StoreMode == subsystems.DataStoreModeReadWrite {
+		system.storeStatusCh = system.dataStoreStatusProvider.AddStatusListener()
+	}
+
+	sourceContext := *clientContext
+	sourceContext.BasicClientContext.DataDestination = system.store
+	sourceContext.BasicClientContext.SelectorStore = system.store
+	system.sourceContext = &sourceContext
+
+	for _, initializerBuilder := range cfg.Initializers {
+		initializer, err := initializerBuilder.Build(system.sourceContext)
+		if err != nil {
+			return nil, err
+		}
+		system.initializers = append(system.initializers, initializer)
+	}
+
+	switch {
+	case cfg.PrimarySynchronizer != nil:
+		system.primary, err = system.buildSynchronizer(cfg.PrimarySynchronizer, primarySynchronizer)
+		if err != nil {
+			return nil, err
+		}
+		system.secondaryBuilder = cfg.SecondarySynchronizer
+	case len(system.initializers) == 0:
+		// There is nothing to obtain data from LaunchDarkly; the SDK only reads from the persistent store,
+		// which is populated by another process. As with ldcomponents.ExternalUpdatesOnly() in FDv1, the data
+		// is considered to be as fresh as it can be.
+		loggers.Info("LaunchDarkly client will not connect to LaunchDarkly for feature flag data")
+		system.dataSourceStatusReporter.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+		system.initialized = true
+	}
+
+	return system, nil
+}
+
+func (f *FDv2) buildSynchronizer(
+	builder subsystems.ComponentConfigurer[subsystems.DataSource],
+	role synchronizerRole,
+) (subsystems.DataSource, error) {
+	destination := &sourceDestination{store: f.store}
+	contextCopy := *f.sourceContext
+	contextCopy.BasicClientContext.DataDestination = destination
+	contextCopy.BasicClientContext.DataSourceStatusReporter = &synchronizerStatusReporter{system: f, role: role}
+	synchronizer, err := builder.Build(&contextCopy)
+	if err != nil {
+		return nil, err
+	}
+	// The synchronizer does not send any data until it is started, so its name can be filled in afterward.
+	destination.source = dataSourceName(synchronizer)
+	return synchronizer, nil
+}
+
+// sourceDestination passes the updates from one synchronizer to the store, identifying the synchronizer in
+// flag change events.
+type sourceDestination struct {
+	store  *Store
+	source string
+}
+
+func (d *sourceDestination) SetBasis(events []fdv2proto.Event, selector *fdv2proto.Selector, persist bool) {
+	d.store.setBasisFrom(d.source, events, selector, persist)
+}
+
+func (d *sourceDestination) ApplyDelta(events []fdv2proto.Event, selector *fdv2proto.Selector, persist bool) {
+	d.store.applyDeltaFrom(d.source, events, selector, persist)
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) DataSourceStatusBroadcaster() *internal.Broadcaster[interfaces.DataSourceStatus] {
+	return f.dataSourceStatusBroadcaster
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) DataSourceStatusProvider() interfaces.DataSourceStatusProvider {
+	return f.dataSourceStatusProvider
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) DataStoreStatusBroadcaster() *internal.Broadcaster[interfaces.DataStoreStatus] {
+	return f.dataStoreStatusBroadcaster
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) DataStoreStatusProvider() interfaces.DataStoreStatusProvider {
+	return f.dataStoreStatusProvider
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) FlagChangeEventBroadcaster() *internal.Broadcaster[interfaces.FlagChangeEvent] {
+	return f.flagChangeEventBroadcaster
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) Start(closeWhenReady chan struct{}) {
+	f.closeWhenReady = closeWhenReady
+	if f.offline {
+		f.notifyReady()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.mu.Lock()
+	f.cancel = cancel
+	f.mu.Unlock()
+
+	if f.storeStatusCh != nil {
+		go f.refreshStoreAfterOutages(f.storeStatusCh)
+	}
+
+	go func() {
+		if f.store.LoadPersistedBasis() {
+			// The synchronizers will ask for changes since the persisted data, so there is no need to obtain
+			// a full data set from the initializers.
+			f.loggers.Infof("Loaded data from persistent store with selector version %d", f.store.Selector().Version())
+		} else {
+			f.runInitializers(ctx)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if f.primary == nil {
+			f.notifyReady()
+			return
+		}
+		ready := make(chan struct{})
+		f.primary.Start(ready)
+		go func() {
+			<-ready
+			// If the primary failed permanently, it will already have triggered a fallback to the secondary,
+			// which will signal readiness itself.
+			if f.primary.IsInitialized() || f.secondaryBuilder == nil {
+				f.notifyReady()
+			}
+		}()
+	}()
+}
+
+func (f *FDv2) runInitializers(ctx context.Context) {
+	if len(f.initializers) == 0 {
+		return
+	}
+	for _, initializer := range f.initializers {
+		basis, err := initializer.Fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			f.loggers.Warnf("Initializer %s failed: %s", initializer.Name(), err)
+			continue
+		}
+		f.loggers.Infof("Initialized from %s", initializer.Name())
+		f.store.setBasisFrom(initializer.Name(), basis.Events, basis.Selector, basis.Persist)
+		// A basis without a selector, such as one read from a snapshot file, may be arbitrarily old, so like
+		// data from a persistent store it is only considered cached until a synchronizer confirms it.
+		confirmed := basis.Selector.IsSet()
+		if confirmed {
+			f.mu.Lock()
+			f.initialized = true
+			f.mu.Unlock()
+		}
+		if f.primary == nil {
+			f.dataSourceStatusReporter.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+		}
+		if confirmed || f.primary == nil {
+			f.notifyReady()
+		}
+		return
+	}
+	f.loggers.Warn("All initializers failed")
+	if f.primary == nil {
+		f.dataSourceStatusReporter.UpdateStatus(interfaces.DataSourceStateOff, interfaces.DataSourceErrorInfo{
+			Kind:    interfaces.DataSourceErrorKindUnknown,
+			Message: "all initializers failed",
+			Time:    time.Now(),
+		})
+	}
+}
+
+// refreshStoreAfterOutages writes the in-memory data to the persistent store whenever the store recovers from
+// an outage, if the store reports that it may have lost updates or if any updates failed to be written. This
+// plays the part of restarting the stream in FDv1: since FDv2 always has the full data set in memory, it does
+// not need to ask LaunchDarkly for it again.
+func (f *FDv2) refreshStoreAfterOutages(statusCh <-chan interfaces.DataStoreStatus) {
+	for status := range statusCh {
+		if !status.Available || !(status.NeedsRefresh || f.store.NeedsCommit()) {
+			continue
+		}
+		if err := f.store.Commit(); err != nil {
+			f.loggers.Errorf("Unable to refresh persistent store after outage: %s", err)
+			continue
+		}
+		f.loggers.Warn("Refreshed persistent store with the latest data after outage")
+		// If the only problem was that updates couldn't be stored, the data system is healthy again.
+		if last := f.dataSourceStatusReporter.GetLastStatus(); last.State == interfaces.DataSourceStateInterrupted &&
+			last.LastError.Kind == interfaces.DataSourceErrorKindStoreError {
+			f.dataSourceStatusReporter.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+		}
+	}
+}
+
+func (f *FDv2) notifyReady() {
+	f.readyOnce.Do(func() {
+		if f.preloaded == nil {
+			close(f.closeWhenReady)
+			return
+		}
+		// The channel is also closed when the store is closed, so this does not outlive the data system.
+		go func() {
+			<-f.preloaded
+			close(f.closeWhenReady)
+		}()
+	})
+}
+
+// handleSynchronizerStatus is called whenever one of the synchronizers reports a status. It forwards the
+// status if the synchronizer is the active one, and decides whether to fall back to the secondary
+// synchronizer or return to the primary.
+func (f *FDv2) handleSynchronizerStatus(
+	role synchronizerRole,
+	newState interfaces.DataSourceState,
+	newError interfaces.DataSourceErrorInfo,
+) {
+	var stoppedSecondary subsystems.DataSource
+	f.mu.Lock()
+	if role == primarySynchronizer && f.secondaryBuilder != nil && !f.closed {
+		switch newState {
+		case interfaces.DataSourceStateInterrupted:
+			if f.active == primarySynchronizer && f.fallbackTimer == nil {
+				f.fallbackTimer = time.AfterFunc(f.fallbackDelay, func() { f.fallBack(false) })
+			}
+		case interfaces.DataSourceStateOff:
+			if f.active == primarySynchronizer {
+				f.stopFallbackTimer()
+				defer f.fallBack(true)
+			}
+		case interfaces.DataSourceStateValid:
+			f.stopFallbackTimer()
+			if f.active == secondarySynchronizer {
+				f.loggers.Info("Primary synchronizer has recovered; stopping secondary synchronizer")
+				f.active = primarySynchronizer
+				stoppedSecondary, f.secondary = f.secondary, nil
+				// If the secondary is still being started, fallBack will close it when Start returns.
+				f.startingSecondary = nil
+			}
+		}
+	}
+	forward := role == f.active
+	f.mu.Unlock()
+
+	// Closing a synchronizer may cause it to report a status, so this must happen outside of the lock.
+	if stoppedSecondary != nil {
+		_ = stoppedSecondary.Close()
+	}
+	if forward {
+		f.dataSourceStatusReporter.UpdateStatus(newState, newError)
+	}
+}
+
+func (f *FDv2) stopFallbackTimer() {
+	if f.fallbackTimer != nil {
+		f.fallbackTimer.Stop()
+		f.fallbackTimer = nil
+	}
+}
+
+// fallBack starts the secondary synchronizer. primaryStopped is true if the primary has reported that it has
+// stopped permanently, rather than having been interrupted for longer than the fallback delay.
+func (f *FDv2) fallBack(primaryStopped bool) {
+	f.mu.Lock()
+	f.fallbackTimer = nil
+	if f.closed || f.active != primarySynchronizer {
+		f.mu.Unlock()
+		return
+	}
+	f.loggers.Warn("Primary synchronizer is unavailable; falling back to secondary synchronizer")
+	secondary, err := f.buildSynchronizer(f.secondaryBuilder, secondarySynchronizer)
+	if err != nil {
+		f.loggers.Errorf("Unable to create secondary synchronizer: %s", err)
+		f.mu.Unlock()
+		// The primary has already reported its state, which stays visible since it is still the active
+		// synchronizer. If it has stopped, nothing else will signal readiness, so the SDK must not wait for it.
+		if primaryStopped {
+			f.notifyReady()
+		}
+		return
+	}
+	f.active = secondarySynchronizer
+	f.startingSecondary = secondary
+	f.mu.Unlock()
+
+	// The secondary is not published in f.secondary until it has been started, so that Stop, or the primary
+	// recovering, cannot close it first and leave Start running on a closed data source.
+	ready := make(chan struct{})
+	secondary.Start(ready)
+
+	f.mu.Lock()
+	if f.closed || f.startingSecondary != secondary {
+		f.mu.Unlock()
+		_ = secondary.Close()
+		return
+	}
+	f.startingSecondary = nil
+	f.secondary = secondary
+	f.mu.Unlock()
+	go func() {
+		<-ready
+		f.notifyReady()
+	}()
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) Stop() error {
+	f.mu.Lock()
+	f.closed = true
+	f.stopFallbackTimer()
+	if f.cancel != nil {
+		f.cancel()
+	}
+	secondary := f.secondary
+	f.secondary = nil
+	f.mu.Unlock()
+
+	if f.primary != nil {
+		_ = f.primary.Close()
+	}
+	if secondary != nil {
+		_ = secondary.Close()
+	}
+	if f.store != nil {
+		_ = f.store.Close()
+	}
+	if f.dataSourceStatusBroadcaster != nil {
+		f.dataSourceStatusBroadcaster.Close()
+	}
+	if f.dataStoreStatusBroadcaster != nil {
+		f.dataStoreStatusBroadcaster.Close()
+	}
+	if f.flagChangeEventBroadcaster != nil {
+		f.flagChangeEventBroadcaster.Close()
+	}
+	return nil
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) DataAvailability() DataAvailability {
+	if f.offline {
+		return Defaults
+	}
+	if f.isRefreshed() {
+		return Refreshed
+	}
+	if f.store.IsInitialized() {
+		return Cached
+	}
+	return Defaults
+}
+
+func (f *FDv2) isRefreshed() bool {
+	if !isPreloaded(f.preloaded) {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.initialized ||
+		(f.primary != nil && f.primary.IsInitialized()) ||
+		(f.secondary != nil && f.secondary.IsInitialized())
+}
+
+//nolint:revive // Data system implementation.
+func (f *FDv2) Store() subsystems.ReadOnlyStore {
+	return f.store
+}
+
+// synchronizerStatusReporter is given to each synchronizer in place of the data system's own
+// DataSourceStatusReporter, so that FDv2 knows which synchronizer a status came from.
+type synchronizerStatusReporter struct {
+	system *FDv2
+	role   synchronizerRole
+}
+
+func (s *synchronizerStatusReporter) UpdateStatus(
+	newState interfaces.DataSourceState,
+	newError interfaces.DataSourceErrorInfo,
+) {
+	s.system.handleSynchronizerStatus(s.role, newState, newError)
+}
@@ -0,0 +1,668 @@
+package datasystem
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datastore"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+
+	th "github.com/launchdarkly/go-test-helpers/v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSynchronizer pushes a fixed set of events to its DataDestination when started. If there are no events,
+// it does nothing when started, and the test drives it through its destination and reporter.
+type fakeSynchronizer struct {
+	destination   subsystems.DataDestination
+	reporter      subsystems.DataSourceStatusReporter
+	selectorStore subsystems.SelectorStore
+	events        []fdv2proto.Event
+	ready         chan<- struct{}
+	startBlock    <-chan struct{}
+	started       internal.AtomicBoolean
+	initialized   internal.AtomicBoolean
+	closed        internal.AtomicBoolean
+	startedClosed internal.AtomicBoolean
+}
+
+func (f *fakeSynchronizer) IsInitialized() bool { return f.initialized.Get() }
+
+func (f *fakeSynchronizer) Name() string { return "fakeSynchronizer" }
+
+func (f *fakeSynchronizer) Close() error {
+	f.closed.Set(true)
+	return nil
+}
+
+func (f *fakeSynchronizer) Start(closeWhenReady chan<- struct{}) {
+	f.ready = closeWhenReady
+	f.started.Set(true)
+	if f.startBlock != nil {
+		<-f.startBlock
+	}
+	f.startedClosed.Set(f.closed.Get())
+	if len(f.events) != 0 {
+		f.destination.SetBasis(f.events, fdv2proto.NewSelector("state", 1), true)
+		f.succeed()
+	}
+}
+
+func (f *fakeSynchronizer) succeed() {
+	f.reporter.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+	if !f.initialized.GetAndSet(true) {
+		close(f.ready)
+	}
+}
+
+type fakeSynchronizerConfigurer struct {
+	events     []fdv2proto.Event
+	startBlock <-chan struct{}
+	all        []*fakeSynchronizer
+	built      *fakeSynchronizer
+	lock       sync.Mutex
+}
+
+func (c *fakeSynchronizerConfigurer) Build(context subsystems.ClientContext) (subsystems.DataSource, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.built = &fakeSynchronizer{
+		destination:   context.GetDataDestination(),
+		reporter:      context.GetDataSourceStatusReporter(),
+		selectorStore: context.GetSelectorStore(),
+		events:        c.events,
+		startBlock:    c.startBlock,
+	}
+	c.all = append(c.all, c.built)
+	return c.built, nil
+}
+
+func (c *fakeSynchronizerConfigurer) getAll() []*fakeSynchronizer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]*fakeSynchronizer(nil), c.all...)
+}
+
+type fakeInitializer struct {
+	name     string
+	events   []fdv2proto.Event
+	selector *fdv2proto.Selector
+	err      error
+	calls    int
+}
+
+func (f *fakeInitializer) Name() string { return f.name }
+
+func (f *fakeInitializer) Fetch(ctx context.Context) (*subsystems.Basis, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &subsystems.Basis{Events: f.events, Selector: f.selector}, nil
+}
+
+func makeFDv2TestContext() *internal.ClientContextImpl {
+	return &internal.ClientContextImpl{BasicClientContext: sharedtest.NewTestContext("sdk-key", nil, nil)}
+}
+
+func makeFlagEvent(key string, version int) fdv2proto.PutObject {
+	flag := ldbuilders.NewFlagBuilder(key).Version(version).Build()
+	return fdv2proto.PutObject{Kind: fdv2proto.FlagKind, Key: key, Version: version, Object: &flag}
+}
+
+func startAndWait(t *testing.T, system *FDv2) {
+	ready := make(chan struct{})
+	system.Start(ready)
+	th.AssertChannelClosed(t, ready, time.Second, "timed out waiting for data system to start")
+}
+
+func TestFDv2_SynchronizerWritesToStore(t *testing.T) {
+	synchronizer := &fakeSynchronizerConfigurer{events: []fdv2proto.Event{makeFlagEvent("flag", 1)}}
+	cfg := subsystems.DataSystemConfiguration{PrimarySynchronizer: synchronizer}
+
+	system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	assert.Equal(t, Defaults, system.DataAvailability())
+	assert.Equal(t, interfaces.DataSourceStateInitializing, system.DataSourceStatusProvider().GetStatus().State)
+
+	startAndWait(t, system)
+
+	assert.Equal(t, Refreshed, system.DataAvailability())
+	assert.Equal(t, interfaces.DataSourceStateValid, system.DataSourceStatusProvider().GetStatus().State)
+
+	item, err := system.Store().Get(datakinds.Features, "flag")
+	require.NoError(t, err)
+	assert.Equal(t, 1, item.Version)
+	assert.Equal(t, "flag", item.Item.(*ldmodel.FeatureFlag).Key)
+}
+
+func TestFDv2_BroadcastsFlagChanges(t *testing.T) {
+	synchronizer := &fakeSynchronizerConfigurer{events: []fdv2proto.Event{makeFlagEvent("flag1", 1), makeFlagEvent("flag2", 1)}}
+	cfg := subsystems.DataSystemConfiguration{PrimarySynchronizer: synchronizer}
+
+	system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	startAndWait(t, system)
+
+	ch := system.FlagChangeEventBroadcaster().AddListener()
+
+	synchronizer.built.destination.ApplyDelta([]fdv2proto.Event{makeFlagEvent("flag1", 2)}, fdv2proto.NewSelector("state", 2), true)
+	event := th.RequireValue(t, ch, time.Second, "timed out waiting for flag change event")
+	assert.Equal(t, interfaces.FlagChangeEvent{
+		Key:        "flag1",
+		Kind:       interfaces.FlagChangeKindUpdated,
+		Cause:      interfaces.FlagChangeCauseDirect,
+		OldVersion: ldvalue.NewOptionalInt(1),
+		NewVersion: ldvalue.NewOptionalInt(2),
+		Source:     "fakeSynchronizer",
+	}, event)
+
+	// An update with a version that is not newer is ignored by the store, so no event is sent.
+	synchronizer.built.destination.ApplyDelta([]fdv2proto.Event{makeFlagEvent("flag2", 1)}, fdv2proto.NewSelector("state", 3), true)
+	th.AssertNoMoreValues(t, ch, time.Millisecond*50)
+
+	synchronizer.built.destination.SetBasis([]fdv2proto.Event{makeFlagEvent("flag1", 2)}, fdv2proto.NewSelector("state", 4), true)
+	event = th.RequireValue(t, ch, time.Second, "timed out waiting for flag change event")
+	assert.Equal(t, interfaces.FlagChangeEvent{
+		Key:        "flag2",
+		Kind:       interfaces.FlagChangeKindDeleted,
+		Cause:      interfaces.FlagChangeCauseDirect,
+		OldVersion: ldvalue.NewOptionalInt(1),
+		Source:     "fakeSynchronizer",
+	}, event)
+	th.AssertNoMoreValues(t, ch, time.Millisecond*50)
+}
+
+func TestFDv2_Offline(t *testing.T) {
+	synchronizer := &fakeSynchronizerConfigurer{}
+	cfg := subsystems.DataSystemConfiguration{PrimarySynchronizer: synchronizer}
+
+	system, err := NewFDv2(true, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	startAndWait(t, system)
+
+	assert.Nil(t, synchronizer.built)
+	assert.Equal(t, Defaults, system.DataAvailability())
+	assert.Equal(t, interfaces.DataSourceStateValid, system.DataSourceStatusProvider().GetStatus().State)
+}
+
+func TestFDv2_ReadOnlyStoreWithoutSynchronizer(t *testing.T) {
+	persistent := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+	flag := ldbuilders.NewFlagBuilder("flag").Version(1).Build()
+	require.NoError(t, persistent.Init(sharedtest.NewDataSetBuilder().Flags(flag).Build()))
+
+	cfg := subsystems.DataSystemConfiguration{
+		Store:     mocks.SingleComponentConfigurer[subsystems.DataStore]{Instance: persistent},
+		StoreMode: subsystems.DataStoreModeRead,
+	}
+
+	system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	startAndWait(t, system)
+
+	assert.Equal(t, Refreshed, system.DataAvailability())
+	item, err := system.Store().Get(datakinds.Features, "flag")
+	require.NoError(t, err)
+	assert.Equal(t, 1, item.Version)
+}
+
+// preloadingDataStore is like the SDK's persistent data store wrapper when its cache is being preloaded.
+type preloadingDataStore struct {
+	subsystems.DataStore
+	preloaded chan struct{}
+}
+
+func (p *preloadingDataStore) Preloaded() <-chan struct{} { return p.preloaded }
+
+func TestFDv2_WaitsForPersistentStoreToPreload(t *testing.T) {
+	persistent := &preloadingDataStore{
+		DataStore: datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers()),
+		preloaded: make(chan struct{}),
+	}
+	require.NoError(t, persistent.Init(sharedtest.NewDataSetBuilder().Build()))
+
+	cfg := subsystems.DataSystemConfiguration{
+		Store:     mocks.SingleComponentConfigurer[subsystems.DataStore]{Instance: persistent},
+		StoreMode: subsystems.DataStoreModeRead,
+	}
+
+	system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	ready := make(chan struct{})
+	system.Start(ready)
+	th.AssertChannelNotClosed(t, ready, time.Millisecond*100)
+	assert.Equal(t, Cached, system.DataAvailability())
+
+	close(persistent.preloaded)
+	th.AssertChannelClosed(t, ready, time.Second)
+	assert.Equal(t, Refreshed, system.DataAvailability())
+}
+
+func TestFDv2_ConfigurationErrors(t *testing.T) {
+	fakeError := errors.New("sorry")
+
+	t.Run("data system configurer", func(t *testing.T) {
+		_, err := NewFDv2(false, mocks.ComponentConfigurerThatReturnsError[subsystems.DataSystemConfiguration]{Err: fakeError},
+			makeFDv2TestContext())
+		assert.Equal(t, fakeError, err)
+	})
+
+	t.Run("store", func(t *testing.T) {
+		cfg := subsystems.DataSystemConfiguration{
+			Store: mocks.ComponentConfigurerThatReturnsError[subsystems.DataStore]{Err: fakeError},
+		}
+		_, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		assert.Equal(t, fakeError, err)
+	})
+
+	t.Run("synchronizer", func(t *testing.T) {
+		cfg := subsystems.DataSystemConfiguration{
+			PrimarySynchronizer: mocks.ComponentConfigurerThatReturnsError[subsystems.DataSource]{Err: fakeError},
+		}
+		_, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		assert.Equal(t, fakeError, err)
+	})
+}
+
+func TestFDv2_Initializers(t *testing.T) {
+	t.Run("runs in order until one succeeds", func(t *testing.T) {
+		failing := &fakeInitializer{name: "failing", err: errors.New("sorry")}
+		succeeding := &fakeInitializer{name: "succeeding", events: []fdv2proto.Event{makeFlagEvent("flag", 1)},
+			selector: fdv2proto.NewSelector("state", 1)}
+		skipped := &fakeInitializer{name: "skipped", events: []fdv2proto.Event{makeFlagEvent("other", 1)}}
+		cfg := subsystems.DataSystemConfiguration{
+			Initializers: []subsystems.ComponentConfigurer[subsystems.DataInitializer]{
+				mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: failing},
+				mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: succeeding},
+				mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: skipped},
+			},
+		}
+
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		defer system.Stop()
+
+		startAndWait(t, system)
+
+		assert.Equal(t, 1, failing.calls)
+		assert.Equal(t, 1, succeeding.calls)
+		assert.Equal(t, 0, skipped.calls)
+		assert.Equal(t, Refreshed, system.DataAvailability())
+		assert.Equal(t, interfaces.DataSourceStateValid, system.DataSourceStatusProvider().GetStatus().State)
+
+		item, err := system.Store().Get(datakinds.Features, "flag")
+		require.NoError(t, err)
+		assert.Equal(t, 1, item.Version)
+	})
+
+	t.Run("basis without a selector is cached", func(t *testing.T) {
+		initializer := &fakeInitializer{name: "initializer", events: []fdv2proto.Event{makeFlagEvent("flag", 1)}}
+		cfg := subsystems.DataSystemConfiguration{
+			Initializers: []subsystems.ComponentConfigurer[subsystems.DataInitializer]{
+				mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: initializer},
+			},
+		}
+
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		defer system.Stop()
+
+		startAndWait(t, system)
+
+		assert.Equal(t, Cached, system.DataAvailability())
+		item, err := system.Store().Get(datakinds.Features, "flag")
+		require.NoError(t, err)
+		assert.Equal(t, 1, item.Version)
+	})
+
+	t.Run("basis without a selector is cached until synchronizer is valid", func(t *testing.T) {
+		initializer := &fakeInitializer{name: "initializer", events: []fdv2proto.Event{makeFlagEvent("flag", 1)}}
+		synchronizer := &fakeSynchronizerConfigurer{}
+		cfg := subsystems.DataSystemConfiguration{
+			Initializers: []subsystems.ComponentConfigurer[subsystems.DataInitializer]{
+				mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: initializer},
+			},
+			PrimarySynchronizer: synchronizer,
+		}
+
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		defer system.Stop()
+
+		ready := make(chan struct{})
+		system.Start(ready)
+		require.Eventually(t, func() bool { return synchronizer.built.started.Get() }, time.Second, time.Millisecond)
+		th.AssertChannelNotClosed(t, ready, time.Millisecond*50)
+		assert.Equal(t, Cached, system.DataAvailability())
+
+		synchronizer.built.succeed()
+		th.AssertChannelClosed(t, ready, time.Second)
+		assert.Equal(t, Refreshed, system.DataAvailability())
+	})
+
+	t.Run("all fail without a synchronizer", func(t *testing.T) {
+		failing := &fakeInitializer{name: "failing", err: errors.New("sorry")}
+		cfg := subsystems.DataSystemConfiguration{
+			Initializers: []subsystems.ComponentConfigurer[subsystems.DataInitializer]{
+				mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: failing},
+			},
+		}
+
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		defer system.Stop()
+
+		startAndWait(t, system)
+
+		assert.Equal(t, Defaults, system.DataAvailability())
+		assert.Equal(t, interfaces.DataSourceStateOff, system.DataSourceStatusProvider().GetStatus().State)
+	})
+
+	t.Run("synchronizer starts after initializer", func(t *testing.T) {
+		initializer := &fakeInitializer{name: "initializer", events: []fdv2proto.Event{makeFlagEvent("flag", 1)}}
+		synchronizer := &fakeSynchronizerConfigurer{events: []fdv2proto.Event{makeFlagEvent("flag", 2)}}
+		cfg := subsystems.DataSystemConfiguration{
+			Initializers: []subsystems.ComponentConfigurer[subsystems.DataInitializer]{
+				mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: initializer},
+			},
+			PrimarySynchronizer: synchronizer,
+		}
+
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		defer system.Stop()
+
+		startAndWait(t, system)
+
+		require.Eventually(t, func() bool {
+			item, _ := system.Store().Get(datakinds.Features, "flag")
+			return item.Version == 2
+		}, time.Second, time.Millisecond*10)
+	})
+}
+
+func TestFDv2_PersistedBasis(t *testing.T) {
+	persistent := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+	flag := ldbuilders.NewFlagBuilder("flag").Version(1).Build()
+	require.NoError(t, persistent.Init(append(sharedtest.NewDataSetBuilder().Flags(flag).Build(),
+		fdv2proto.SelectorCollection(fdv2proto.NewSelector("state", 1)))))
+
+	initializer := &fakeInitializer{name: "initializer", events: []fdv2proto.Event{makeFlagEvent("flag", 2)}}
+	synchronizer := &fakeSynchronizerConfigurer{}
+	cfg := subsystems.DataSystemConfiguration{
+		Store:     mocks.SingleComponentConfigurer[subsystems.DataStore]{Instance: persistent},
+		StoreMode: subsystems.DataStoreModeReadWrite,
+		Initializers: []subsystems.ComponentConfigurer[subsystems.DataInitializer]{
+			mocks.SingleComponentConfigurer[subsystems.DataInitializer]{Instance: initializer},
+		},
+		PrimarySynchronizer: synchronizer,
+	}
+
+	system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	ready := make(chan struct{})
+	system.Start(ready)
+	require.Eventually(t, func() bool { return synchronizer.getAll()[0].started.Get() }, time.Second, time.Millisecond*10)
+
+	// The initializers are skipped, and the synchronizer can resume from the persisted selector.
+	assert.Equal(t, 0, initializer.calls)
+	assert.Equal(t, fdv2proto.NewSelector("state", 1), synchronizer.built.selectorStore.Selector())
+	assert.Equal(t, Cached, system.DataAvailability())
+
+	item, err := system.Store().Get(datakinds.Features, "flag")
+	require.NoError(t, err)
+	assert.Equal(t, 1, item.Version)
+
+	synchronizer.built.destination.ApplyDelta([]fdv2proto.Event{makeFlagEvent("flag", 2)}, fdv2proto.NewSelector("state", 2), true)
+	synchronizer.built.succeed()
+	th.AssertChannelClosed(t, ready, time.Second, "timed out waiting for data system to start")
+	assert.Equal(t, Refreshed, system.DataAvailability())
+}
+
+func TestFDv2_PersistentStoreRecovery(t *testing.T) {
+	persistent := &failingUpsertStore{DataStore: datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())}
+	storeConfigurer := &mocks.ComponentConfigurerThatCapturesClientContext[subsystems.DataStore]{
+		Configurer: mocks.SingleComponentConfigurer[subsystems.DataStore]{Instance: persistent},
+	}
+	synchronizer := &fakeSynchronizerConfigurer{events: []fdv2proto.Event{makeFlagEvent("flag", 1)}}
+	cfg := subsystems.DataSystemConfiguration{
+		Store:               storeConfigurer,
+		StoreMode:           subsystems.DataStoreModeReadWrite,
+		PrimarySynchronizer: synchronizer,
+	}
+
+	system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	startAndWait(t, system)
+	statusCh := system.DataSourceStatusBroadcaster().AddListener()
+
+	persistent.failing = true
+	synchronizer.built.destination.ApplyDelta([]fdv2proto.Event{makeFlagEvent("flag", 2)}, fdv2proto.NewSelector("state", 2), true)
+	persistent.failing = false
+
+	status := th.RequireValue(t, statusCh, time.Second, "timed out waiting for data source status")
+	assert.Equal(t, interfaces.DataSourceStateInterrupted, status.State)
+	assert.Equal(t, interfaces.DataSourceErrorKindStoreError, status.LastError.Kind)
+
+	// The store recovers without asking for a refresh, but the SDK knows that it missed an update.
+	storeUpdates := storeConfigurer.ReceivedClientContext.GetDataStoreUpdateSink()
+	storeUpdates.UpdateStatus(interfaces.DataStoreStatus{Available: false})
+	storeUpdates.UpdateStatus(interfaces.DataStoreStatus{Available: true})
+
+	status = th.RequireValue(t, statusCh, time.Second, "timed out waiting for data source status")
+	assert.Equal(t, interfaces.DataSourceStateValid, status.State)
+
+	item, err := persistent.Get(datakinds.Features, "flag")
+	require.NoError(t, err)
+	assert.Equal(t, 2, item.Version)
+	item, err = persistent.Get(fdv2proto.SelectorDataKind, fdv2proto.SelectorKey)
+	require.NoError(t, err)
+	assert.Equal(t, fdv2proto.NewSelector("state", 2), item.Item)
+}
+
+func TestFDv2_PersistentStoreRefreshedWhenNeeded(t *testing.T) {
+	persistent := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+	storeConfigurer := &mocks.ComponentConfigurerThatCapturesClientContext[subsystems.DataStore]{
+		Configurer: mocks.SingleComponentConfigurer[subsystems.DataStore]{Instance: persistent},
+	}
+	synchronizer := &fakeSynchronizerConfigurer{events: []fdv2proto.Event{makeFlagEvent("flag", 1)}}
+	cfg := subsystems.DataSystemConfiguration{
+		Store:               storeConfigurer,
+		StoreMode:           subsystems.DataStoreModeReadWrite,
+		PrimarySynchronizer: synchronizer,
+	}
+
+	system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+		makeFDv2TestContext())
+	require.NoError(t, err)
+	defer system.Stop()
+
+	startAndWait(t, system)
+
+	// Simulate the store losing its data during an outage.
+	require.NoError(t, persistent.Init(nil))
+	storeUpdates := storeConfigurer.ReceivedClientContext.GetDataStoreUpdateSink()
+	storeUpdates.UpdateStatus(interfaces.DataStoreStatus{Available: false})
+	storeUpdates.UpdateStatus(interfaces.DataStoreStatus{Available: true, NeedsRefresh: true})
+
+	require.Eventually(t, func() bool {
+		item, _ := persistent.Get(datakinds.Features, "flag")
+		return item.Version == 1
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestFDv2_SynchronizerFallback(t *testing.T) {
+	fallbackDelay := time.Millisecond * 50
+	interrupted := interfaces.DataSourceErrorInfo{Kind: interfaces.DataSourceErrorKindNetworkError}
+
+	makeSystem := func(t *testing.T) (*FDv2, *fakeSynchronizerConfigurer, *fakeSynchronizerConfigurer) {
+		primary := &fakeSynchronizerConfigurer{}
+		secondary := &fakeSynchronizerConfigurer{}
+		cfg := subsystems.DataSystemConfiguration{
+			PrimarySynchronizer:   primary,
+			SecondarySynchronizer: secondary,
+			FallbackDelay:         fallbackDelay,
+		}
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		system.Start(make(chan struct{}))
+		return system, primary, secondary
+	}
+
+	requireState := func(t *testing.T, system *FDv2, state interfaces.DataSourceState) {
+		require.Eventually(t, func() bool {
+			return system.DataSourceStatusProvider().GetStatus().State == state
+		}, time.Second, time.Millisecond*10)
+	}
+
+	t.Run("falls back when primary stays interrupted, and returns when it recovers", func(t *testing.T) {
+		system, primary, secondary := makeSystem(t)
+		defer system.Stop()
+
+		require.Eventually(t, func() bool { return primary.built.started.Get() }, time.Second, time.Millisecond)
+		primary.built.succeed()
+		requireState(t, system, interfaces.DataSourceStateValid)
+
+		primary.built.reporter.UpdateStatus(interfaces.DataSourceStateInterrupted, interrupted)
+		requireState(t, system, interfaces.DataSourceStateInterrupted)
+		assert.Len(t, secondary.getAll(), 0)
+
+		require.Eventually(t, func() bool { return len(secondary.getAll()) == 1 }, time.Second, time.Millisecond*10)
+		require.Eventually(t, func() bool { return secondary.getAll()[0].started.Get() }, time.Second, time.Millisecond)
+		secondary.getAll()[0].succeed()
+		requireState(t, system, interfaces.DataSourceStateValid)
+
+		// Further reports from the primary are not visible while the secondary is active.
+		primary.built.reporter.UpdateStatus(interfaces.DataSourceStateInterrupted, interrupted)
+		time.Sleep(fallbackDelay * 2)
+		assert.Equal(t, interfaces.DataSourceStateValid, system.DataSourceStatusProvider().GetStatus().State)
+		assert.Len(t, secondary.getAll(), 1)
+
+		primary.built.succeed()
+		require.Eventually(t, func() bool { return secondary.getAll()[0].closed.Get() }, time.Second, time.Millisecond*10)
+		assert.False(t, primary.built.closed.Get())
+		requireState(t, system, interfaces.DataSourceStateValid)
+	})
+
+	t.Run("does not fall back if primary recovers within the delay", func(t *testing.T) {
+		system, primary, secondary := makeSystem(t)
+		defer system.Stop()
+
+		require.Eventually(t, func() bool { return primary.built.started.Get() }, time.Second, time.Millisecond)
+		primary.built.succeed()
+		primary.built.reporter.UpdateStatus(interfaces.DataSourceStateInterrupted, interrupted)
+		primary.built.reporter.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+
+		time.Sleep(fallbackDelay * 2)
+		assert.Len(t, secondary.getAll(), 0)
+	})
+
+	t.Run("falls back immediately when primary stops permanently", func(t *testing.T) {
+		system, primary, secondary := makeSystem(t)
+		defer system.Stop()
+
+		require.Eventually(t, func() bool { return primary.built.started.Get() }, time.Second, time.Millisecond)
+		primary.built.reporter.UpdateStatus(interfaces.DataSourceStateOff, interrupted)
+		assert.Len(t, secondary.getAll(), 1)
+	})
+
+	t.Run("is ready and reports primary state if secondary cannot be built", func(t *testing.T) {
+		primary := &fakeSynchronizerConfigurer{}
+		cfg := subsystems.DataSystemConfiguration{
+			PrimarySynchronizer: primary,
+			SecondarySynchronizer: mocks.ComponentConfigurerThatReturnsError[subsystems.DataSource]{
+				Err: errors.New("sorry"),
+			},
+			FallbackDelay: fallbackDelay,
+		}
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		defer system.Stop()
+		ready := make(chan struct{})
+		system.Start(ready)
+
+		require.Eventually(t, func() bool { return primary.built.started.Get() }, time.Second, time.Millisecond)
+		primary.built.reporter.UpdateStatus(interfaces.DataSourceStateInterrupted, interrupted)
+		time.Sleep(fallbackDelay * 2)
+		// An interruption before the first success is reported as still initializing.
+		requireState(t, system, interfaces.DataSourceStateInitializing)
+		th.AssertNoMoreValues(t, ready, time.Millisecond*10, "data system should wait for interrupted primary")
+
+		primary.built.reporter.UpdateStatus(interfaces.DataSourceStateOff, interrupted)
+		th.AssertChannelClosed(t, ready, time.Second, "timed out waiting for data system to be ready")
+		requireState(t, system, interfaces.DataSourceStateOff)
+		assert.Equal(t, Defaults, system.DataAvailability())
+	})
+
+	t.Run("secondary that is still starting is closed after Start returns if system is stopped", func(t *testing.T) {
+		primary := &fakeSynchronizerConfigurer{}
+		startBlock := make(chan struct{})
+		secondary := &fakeSynchronizerConfigurer{startBlock: startBlock}
+		cfg := subsystems.DataSystemConfiguration{
+			PrimarySynchronizer:   primary,
+			SecondarySynchronizer: secondary,
+			FallbackDelay:         fallbackDelay,
+		}
+		system, err := NewFDv2(false, mocks.SingleComponentConfigurer[subsystems.DataSystemConfiguration]{Instance: cfg},
+			makeFDv2TestContext())
+		require.NoError(t, err)
+		system.Start(make(chan struct{}))
+
+		require.Eventually(t, func() bool { return primary.built.started.Get() }, time.Second, time.Millisecond)
+		go primary.built.reporter.UpdateStatus(interfaces.DataSourceStateOff, interrupted)
+		require.Eventually(t, func() bool {
+			all := secondary.getAll()
+			return len(all) == 1 && all[0].started.Get()
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, system.Stop())
+		close(startBlock)
+
+		require.Eventually(t, func() bool { return secondary.getAll()[0].closed.Get() }, time.Second, time.Millisecond*10)
+		assert.False(t, secondary.getAll()[0].startedClosed.Get())
+	})
+}
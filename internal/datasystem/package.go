@@ -1,5 +1,5 @@
 // Package datasystem encapsulates the interactions between the SDK's data store, data source, and other related
 // components.
-// Currently, there is only one data system implementation, FDv1, which represents the functionality of the SDK
-// before the FDv2 protocol was introduced.
+// There are two data system implementations: FDv1, which represents the functionality of the SDK before the FDv2
+// protocol was introduced, and FDv2, which is enabled by setting Config.DataSystem.
 package datasystem
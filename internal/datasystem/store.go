@@ -2,7 +2,10 @@ package datasystem
 
 import (
 	"sync"
+	"time"
 
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datasource"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/toposort"
 
 	"github.com/launchdarkly/go-server-sdk/v7/internal/memorystorev2"
@@ -70,6 +73,16 @@ type Store struct {
 	// Identifies the current data.
 	selector *fdv2proto.Selector
 
+	// Optional; if set, flag change events are generated for every update applied to the store.
+	changeTracker *datasource.FlagChangeTracker
+
+	// Optional; if set, errors from writing to the persistent store are reported as a data source status.
+	statusReporter subsystems.DataSourceStatusReporter
+
+	// True if the last attempt to write to the persistent store failed, meaning that the persistent store is
+	// missing some of the data in the memory store.
+	persistenceFailed bool
+
 	mu sync.RWMutex
 
 	loggers ldlog.Loggers
@@ -133,6 +146,26 @@ func (s *Store) WithPersistence(persistent subsystems.DataStore, mode subsystems
 	return s
 }
 
+// WithChangeTracker configures the store to report updates to the given FlagChangeTracker, which generates
+// flag change events for anything affected by them. Like WithPersistence, it should be called before any other
+// method is called.
+func (s *Store) WithChangeTracker(tracker *datasource.FlagChangeTracker) *Store {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changeTracker = tracker
+	return s
+}
+
+// WithStatusReporter configures the store to report errors from writing to the persistent store, by setting
+// the data source state to DataSourceStateInterrupted with DataSourceErrorKindStoreError, as FDv1 does. Like
+// WithPersistence, it should be called before any other method is called.
+func (s *Store) WithStatusReporter(reporter subsystems.DataSourceStatusReporter) *Store {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusReporter = reporter
+	return s
+}
+
 // Selector returns the current selector.
 func (s *Store) Selector() *fdv2proto.Selector {
 	s.mu.RLock()
@@ -153,10 +186,35 @@ func (s *Store) Close() error {
 // SetBasis sets the basis of the store. Any existing data is discarded. To request data persistence,
 // set persist to true.
 func (s *Store) SetBasis(events []fdv2proto.Event, selector *fdv2proto.Selector, persist bool) {
+	s.setBasisFrom("", events, selector, persist)
+}
+
+// setBasisFrom is the same as SetBasis, but identifies the data source in flag change events.
+func (s *Store) setBasisFrom(source string, events []fdv2proto.Event, selector *fdv2proto.Selector, persist bool) {
 	collections := fdv2proto.ToStorableItems(events)
+	oldData, err := s.setBasis(collections, selector, persist)
+
+	// The status and the change tracker are updated after the lock is released, since listeners may call back
+	// into the store.
+	s.reportPersistenceError(err)
+	if s.changeTracker != nil {
+		s.changeTracker.SetBasis(source, oldData, collections)
+	}
+}
+
+func (s *Store) setBasis(
+	collections []ldstoretypes.Collection,
+	selector *fdv2proto.Selector,
+	persist bool,
+) (map[ldstoretypes.DataKind]map[string]ldstoretypes.ItemDescriptor, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	var oldData map[ldstoretypes.DataKind]map[string]ldstoretypes.ItemDescriptor
+	if s.changeTracker != nil {
+		oldData = s.snapshotActiveIfListening()
+	}
+
 	s.memoryStore.SetBasis(collections)
 
 	s.persist = persist
@@ -164,11 +222,51 @@ func (s *Store) SetBasis(events []fdv2proto.Event, selector *fdv2proto.Selector,
 
 	s.active = s.memoryStore
 
+	var err error
 	if s.shouldPersist() {
-		//nolint:godox
-		// TODO: figure out where to handle/report the error.
-		_ = s.persistentStore.impl.Init(toposort.Sort(collections))
+		err = s.recordPersistenceResult(s.persistentStore.impl.Init(withSelector(collections, selector)))
 	}
+
+	return oldData, err
+}
+
+// snapshotActiveIfListening returns all the data in the active store, so that flag change events can be computed
+// after a new basis is set. If nobody is listening for flag changes, it returns nil to avoid the cost of the query.
+func (s *Store) snapshotActiveIfListening() map[ldstoretypes.DataKind]map[string]ldstoretypes.ItemDescriptor {
+	if !s.changeTracker.HasListeners() || !s.active.IsInitialized() {
+		return nil
+	}
+	oldData := make(map[ldstoretypes.DataKind]map[string]ldstoretypes.ItemDescriptor)
+	for _, kind := range datakinds.AllDataKinds() {
+		if items, err := s.active.GetAll(kind); err == nil {
+			m := make(map[string]ldstoretypes.ItemDescriptor, len(items))
+			for _, item := range items {
+				m[item.Key] = item.Item
+			}
+			oldData[kind] = m
+		}
+	}
+	return oldData
+}
+
+// filterUpdated returns only the items in collections that the memory store reported as updated.
+func filterUpdated(
+	collections []ldstoretypes.Collection,
+	updated map[ldstoretypes.DataKind]map[string]bool,
+) []ldstoretypes.Collection {
+	var changed []ldstoretypes.Collection
+	for _, coll := range collections {
+		var items []ldstoretypes.KeyedItemDescriptor
+		for _, item := range coll.Items {
+			if updated[coll.Kind][item.Key] {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			changed = append(changed, ldstoretypes.Collection{Kind: coll.Kind, Items: items})
+		}
+	}
+	return changed
 }
 
 func (s *Store) shouldPersist() bool {
@@ -178,12 +276,31 @@ func (s *Store) shouldPersist() bool {
 // ApplyDelta applies a delta update to the store. ApplyDelta should not be called until SetBasis has been called.
 // To request data persistence, set persist to true.
 func (s *Store) ApplyDelta(events []fdv2proto.Event, selector *fdv2proto.Selector, persist bool) {
+	s.applyDeltaFrom("", events, selector, persist)
+}
+
+// applyDeltaFrom is the same as ApplyDelta, but identifies the data source in flag change events.
+func (s *Store) applyDeltaFrom(source string, events []fdv2proto.Event, selector *fdv2proto.Selector, persist bool) {
 	collections := fdv2proto.ToStorableItems(events)
+	updated, err := s.applyDelta(collections, selector, persist)
 
+	// The status and the change tracker are updated after the lock is released, since listeners may call back
+	// into the store.
+	s.reportPersistenceError(err)
+	if s.changeTracker != nil {
+		s.changeTracker.ApplyDelta(source, filterUpdated(collections, updated))
+	}
+}
+
+func (s *Store) applyDelta(
+	collections []ldstoretypes.Collection,
+	selector *fdv2proto.Selector,
+	persist bool,
+) (map[ldstoretypes.DataKind]map[string]bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.memoryStore.ApplyDelta(collections)
+	updated := s.memoryStore.ApplyDelta(collections)
 
 	s.persist = persist
 	s.selector = selector
@@ -193,17 +310,59 @@ func (s *Store) ApplyDelta(events []fdv2proto.Event, selector *fdv2proto.Selecto
 	// we still need to apply a series of upserts, so the state of the store may be inconsistent when that
 	// is happening. In practice, we often don't receive more than one event at a time, but this may change
 	// in the future.
+	var err error
 	if s.shouldPersist() {
-		//nolint:godox
-		// TODO: figure out where to handle/report the error.
 		for _, coll := range toposort.Sort(collections) {
 			for _, item := range coll.Items {
-				_, err := s.persistentStore.impl.Upsert(coll.Kind, item.Key, item.Item)
-				if err != nil {
-					s.loggers.Errorf("Failed to apply delta to persistent store: %s", err)
+				if _, upsertErr := s.persistentStore.impl.Upsert(coll.Kind, item.Key, item.Item); upsertErr != nil {
+					err = upsertErr
 				}
 			}
 		}
+		// The selector is written last, so that if the process stops partway through, the stored selector
+		// is older than the stored data rather than newer. Replaying changes is harmless since items are
+		// versioned, whereas skipping them is not. For the same reason, it isn't written at all if any of the
+		// items could not be written.
+		if err == nil && selector.IsSet() {
+			_, err = s.persistentStore.impl.Upsert(fdv2proto.SelectorDataKind, fdv2proto.SelectorKey,
+				ldstoretypes.ItemDescriptor{Version: selector.Version(), Item: selector})
+		}
+		err = s.recordPersistenceResult(err)
+	}
+
+	return updated, err
+}
+
+// recordPersistenceResult keeps track of whether the persistent store is missing data, and returns the error only
+// if it is the first one since the last successful write, so that an outage is logged and reported only once.
+// The caller must hold the lock.
+func (s *Store) recordPersistenceResult(err error) error {
+	if err == nil {
+		s.persistenceFailed = false
+		return nil
+	}
+	alreadyFailed := s.persistenceFailed
+	s.persistenceFailed = true
+	if alreadyFailed {
+		return nil
+	}
+	return err
+}
+
+func (s *Store) reportPersistenceError(err error) {
+	if err == nil {
+		return
+	}
+	s.loggers.Warnf("Unexpected data store error when trying to store an update received from the data source: %s", err)
+	if s.statusReporter != nil {
+		s.statusReporter.UpdateStatus(
+			interfaces.DataSourceStateInterrupted,
+			interfaces.DataSourceErrorInfo{
+				Kind:    interfaces.DataSourceErrorKindStoreError,
+				Message: err.Error(),
+				Time:    time.Now(),
+			},
+		)
 	}
 }
 
@@ -221,15 +380,84 @@ func (s *Store) GetDataStoreStatusProvider() interfaces.DataStoreStatusProvider
 // Commit persists the data in the memory store to the persistent store, if configured. The persistent store
 // must also be in write mode, and the last call to SetBasis or ApplyDelta must have had persist set to true.
 func (s *Store) Commit() error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if s.shouldPersist() {
-		return s.persistentStore.impl.Init(s.memoryStore.GetAllKinds())
+		err := s.persistentStore.impl.Init(withSelector(s.memoryStore.GetAllKinds(), s.selector))
+		s.persistenceFailed = err != nil
+		return err
 	}
 	return nil
 }
 
+// NeedsCommit returns true if an earlier attempt to write to the persistent store failed, so the persistent store
+// is missing data that is in the memory store.
+func (s *Store) NeedsCommit() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.persistenceFailed && s.shouldPersist()
+}
+
+// withSelector sorts the collections for a persistent store's Init method, and adds the selector to them so
+// that it is stored alongside the data it identifies.
+func withSelector(collections []ldstoretypes.Collection, selector *fdv2proto.Selector) []ldstoretypes.Collection {
+	return append(toposort.Sort(collections), fdv2proto.SelectorCollection(selector))
+}
+
+// LoadPersistedBasis copies the data in the persistent store into memory, if the store is writable and contains
+// a selector identifying that data. This allows data sources to resume from the selector, receiving only the
+// changes since the data was stored rather than a full data set. It returns true if the data was loaded.
+//
+// A read-only store is never loaded this way, since it is maintained by another process and the in-memory
+// copy would not see its updates.
+func (s *Store) LoadPersistedBasis() bool {
+	collections, ok := s.loadPersistedBasis()
+	if !ok {
+		return false
+	}
+	// There is no need to compute change events, since the data is identical to what was being served from the
+	// persistent store; but the change tracker must still learn about the dependencies in the data.
+	if s.changeTracker != nil {
+		s.changeTracker.SetBasis("", nil, collections)
+	}
+	return true
+}
+
+func (s *Store) loadPersistedBasis() ([]ldstoretypes.Collection, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.persistentStore.writable() || s.memoryStore.IsInitialized() || !s.persistentStore.impl.IsInitialized() {
+		return nil, false
+	}
+	item, err := s.persistentStore.impl.Get(fdv2proto.SelectorDataKind, fdv2proto.SelectorKey)
+	if err != nil {
+		s.loggers.Warnf("Unable to read selector from persistent store: %s", err)
+		return nil, false
+	}
+	selector, ok := item.Item.(*fdv2proto.Selector)
+	if !ok || !selector.IsSet() {
+		return nil, false
+	}
+
+	collections := make([]ldstoretypes.Collection, 0, len(datakinds.AllDataKinds()))
+	for _, kind := range datakinds.AllDataKinds() {
+		items, err := s.persistentStore.impl.GetAll(kind)
+		if err != nil {
+			s.loggers.Warnf("Unable to read %s from persistent store: %s", kind, err)
+			return nil, false
+		}
+		collections = append(collections, ldstoretypes.Collection{Kind: kind, Items: items})
+	}
+
+	s.memoryStore.SetBasis(collections)
+	s.selector = selector
+	s.persist = true
+	s.active = s.memoryStore
+	return collections, true
+}
+
 func (s *Store) getActive() subsystems.ReadOnlyStore {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -9,14 +9,20 @@ import (
 
 	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
 
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datastore"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
 
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
 	"github.com/stretchr/testify/require"
 
 	"github.com/launchdarkly/go-sdk-common/v3/ldlogtest"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+	th "github.com/launchdarkly/go-test-helpers/v3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -100,7 +106,10 @@ func TestStore_Commit(t *testing.T) {
 				Items: []ldstoretypes.KeyedItemDescriptor{
 					{Key: "bar", Item: ldstoretypes.ItemDescriptor{Version: 2, Item: ldmodel.Segment{}}},
 				},
-			}}
+			},
+			// Without a selector, the persisted selector is cleared.
+			fdv2proto.SelectorCollection(fdv2proto.NoSelector()),
+		}
 
 		// There should be an error since writing to the store will fail.
 		store.SetBasis(input, fdv2proto.NoSelector(), true)
@@ -248,6 +257,164 @@ func TestStore_SelectorIsRemembered(t *testing.T) {
 	store.SetBasis([]fdv2proto.Event{}, selector5, false)
 }
 
+func TestStore_SelectorIsPersisted(t *testing.T) {
+	getSelector := func(t *testing.T, persistent subsystems.DataStore) *fdv2proto.Selector {
+		item, err := persistent.Get(fdv2proto.SelectorDataKind, fdv2proto.SelectorKey)
+		require.NoError(t, err)
+		if item.Item == nil {
+			return nil
+		}
+		return item.Item.(*fdv2proto.Selector)
+	}
+
+	input := []fdv2proto.Event{
+		fdv2proto.PutObject{Kind: fdv2proto.FlagKind, Key: "foo", Object: ldstoretypes.ItemDescriptor{Version: 1}},
+	}
+
+	t.Run("set basis stores selector with data", func(t *testing.T) {
+		persistent := datastore.NewInMemoryDataStore(ldlogtest.NewMockLog().Loggers)
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		store.SetBasis(input, fdv2proto.NewSelector("a", 1), true)
+		assert.Equal(t, fdv2proto.NewSelector("a", 1), getSelector(t, persistent))
+	})
+
+	t.Run("apply delta updates selector", func(t *testing.T) {
+		persistent := datastore.NewInMemoryDataStore(ldlogtest.NewMockLog().Loggers)
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		store.SetBasis(input, fdv2proto.NewSelector("a", 1), true)
+		store.ApplyDelta([]fdv2proto.Event{
+			fdv2proto.PutObject{Kind: fdv2proto.FlagKind, Key: "foo", Object: ldstoretypes.ItemDescriptor{Version: 2}},
+		}, fdv2proto.NewSelector("b", 2), true)
+		assert.Equal(t, fdv2proto.NewSelector("b", 2), getSelector(t, persistent))
+	})
+
+	t.Run("basis without selector clears stored selector", func(t *testing.T) {
+		persistent := datastore.NewInMemoryDataStore(ldlogtest.NewMockLog().Loggers)
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		store.SetBasis(input, fdv2proto.NewSelector("a", 1), true)
+		store.SetBasis(input, fdv2proto.NoSelector(), true)
+		assert.Nil(t, getSelector(t, persistent))
+	})
+}
+
+func TestStore_LoadPersistedBasis(t *testing.T) {
+	makePersistentStore := func(selector *fdv2proto.Selector) subsystems.DataStore {
+		persistent := datastore.NewInMemoryDataStore(ldlogtest.NewMockLog().Loggers)
+		flag := ldbuilders.NewFlagBuilder("foo").Version(1).Build()
+		_ = persistent.Init([]ldstoretypes.Collection{
+			{Kind: ldstoreimpl.Features(), Items: []ldstoretypes.KeyedItemDescriptor{
+				{Key: "foo", Item: ldstoretypes.ItemDescriptor{Version: 1, Item: &flag}},
+			}},
+			{Kind: ldstoreimpl.Segments(), Items: nil},
+			fdv2proto.SelectorCollection(selector),
+		})
+		return persistent
+	}
+
+	t.Run("loads data and selector from read-write store", func(t *testing.T) {
+		persistent := makePersistentStore(fdv2proto.NewSelector("a", 1))
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		require.True(t, store.LoadPersistedBasis())
+		assert.Equal(t, fdv2proto.NewSelector("a", 1), store.Selector())
+
+		// The data is now served from memory, so changes to the persistent store are not visible.
+		_ = persistent.Init(nil)
+		foo, err := store.Get(ldstoreimpl.Features(), "foo")
+		require.NoError(t, err)
+		assert.Equal(t, 1, foo.Version)
+	})
+
+	t.Run("does not load from read-only store", func(t *testing.T) {
+		persistent := makePersistentStore(fdv2proto.NewSelector("a", 1))
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeRead, nil)
+		defer store.Close()
+
+		assert.False(t, store.LoadPersistedBasis())
+		assert.False(t, store.Selector().IsSet())
+	})
+
+	t.Run("does not load without selector", func(t *testing.T) {
+		persistent := makePersistentStore(fdv2proto.NoSelector())
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		assert.False(t, store.LoadPersistedBasis())
+	})
+
+	t.Run("does not load once data has been received", func(t *testing.T) {
+		persistent := makePersistentStore(fdv2proto.NewSelector("a", 1))
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		store.SetBasis([]fdv2proto.Event{}, fdv2proto.NewSelector("b", 2), false)
+		assert.False(t, store.LoadPersistedBasis())
+		assert.Equal(t, fdv2proto.NewSelector("b", 2), store.Selector())
+	})
+}
+
+func TestStore_PersistenceErrors(t *testing.T) {
+	input := []fdv2proto.Event{
+		fdv2proto.PutObject{Kind: fdv2proto.FlagKind, Key: "foo", Object: ldstoretypes.ItemDescriptor{Version: 1}},
+	}
+
+	t.Run("first error is reported as store error", func(t *testing.T) {
+		spy := &fakeStore{isDown: true}
+		reporter := mocks.NewMockStatusReporter()
+		store := NewStore(ldlogtest.NewMockLog().Loggers).
+			WithPersistence(spy, subsystems.DataStoreModeReadWrite, nil).
+			WithStatusReporter(reporter)
+		defer store.Close()
+
+		store.SetBasis(input, fdv2proto.NewSelector("a", 1), true)
+		store.ApplyDelta(input, fdv2proto.NewSelector("b", 2), true)
+
+		status := reporter.RequireStatusOf(t, interfaces.DataSourceStateInterrupted)
+		assert.Equal(t, interfaces.DataSourceErrorKindStoreError, status.LastError.Kind)
+		th.AssertNoMoreValues(t, reporter.Statuses, time.Millisecond*50)
+		assert.True(t, store.NeedsCommit())
+	})
+
+	t.Run("commit clears failure", func(t *testing.T) {
+		spy := &fakeStore{isDown: true}
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(spy, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		store.SetBasis(input, fdv2proto.NewSelector("a", 1), true)
+		require.True(t, store.NeedsCommit())
+
+		assert.Error(t, store.Commit())
+		assert.True(t, store.NeedsCommit())
+
+		spy.isDown = false
+		require.NoError(t, store.Commit())
+		assert.False(t, store.NeedsCommit())
+		assert.NotEmpty(t, spy.initPayload)
+	})
+
+	t.Run("selector is not stored if delta was not fully stored", func(t *testing.T) {
+		persistent := &failingUpsertStore{DataStore: datastore.NewInMemoryDataStore(ldlogtest.NewMockLog().Loggers)}
+		store := NewStore(ldlogtest.NewMockLog().Loggers).WithPersistence(persistent, subsystems.DataStoreModeReadWrite, nil)
+		defer store.Close()
+
+		store.SetBasis(input, fdv2proto.NewSelector("a", 1), true)
+		persistent.failing = true
+		store.ApplyDelta(input, fdv2proto.NewSelector("b", 2), true)
+		persistent.failing = false
+
+		item, err := persistent.Get(fdv2proto.SelectorDataKind, fdv2proto.SelectorKey)
+		require.NoError(t, err)
+		assert.Equal(t, fdv2proto.NewSelector("a", 1), item.Item)
+	})
+}
+
 func TestStore_Concurrency(t *testing.T) {
 	t.Run("methods using the active store", func(t *testing.T) {
 		logCapture := ldlogtest.NewMockLog()
@@ -317,6 +484,9 @@ func (f *fakeStore) Init(allData []ldstoretypes.Collection) error {
 }
 
 func (f *fakeStore) Upsert(kind ldstoretypes.DataKind, key string, item ldstoretypes.ItemDescriptor) (bool, error) {
+	if f.isDown {
+		return false, errors.New("store is down")
+	}
 	return false, nil
 }
 
@@ -328,6 +498,18 @@ func (f *fakeStore) Close() error {
 	return nil
 }
 
+type failingUpsertStore struct {
+	subsystems.DataStore
+	failing bool
+}
+
+func (f *failingUpsertStore) Upsert(kind ldstoretypes.DataKind, key string, item ldstoretypes.ItemDescriptor) (bool, error) {
+	if f.failing && kind != fdv2proto.SelectorDataKind {
+		return false, errors.New("store is down")
+	}
+	return f.DataStore.Upsert(kind, key, item)
+}
+
 // This matcher is required instead of calling ElementsMatch directly on two slices of collections because
 // the order of the collections, or the order within each collection, is not defined.
 func requireCollectionsMatch(t *testing.T, expected []ldstoretypes.Collection, actual []ldstoretypes.Collection) {
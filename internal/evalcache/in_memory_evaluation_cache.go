@@ -0,0 +1,78 @@
+package evalcache
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/launchdarkly/ccache"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// InMemoryEvaluationCache is a least-recently-used cache of evaluation results, with a maximum number of
+// entries and a maximum time that each entry is kept.
+//
+// Entries are grouped by flag key, so that all of the entries for a flag can be discarded at once.
+type InMemoryEvaluationCache struct {
+	cache *ccache.LayeredCache
+	ttl   time.Duration
+	lock  sync.RWMutex
+}
+
+// NewInMemoryEvaluationCache creates an InMemoryEvaluationCache.
+func NewInMemoryEvaluationCache(capacity int, ttl time.Duration) *InMemoryEvaluationCache {
+	return &InMemoryEvaluationCache{
+		cache: ccache.Layered(ccache.Configure().MaxSize(int64(capacity))),
+		ttl:   ttl,
+	}
+}
+
+//nolint:revive // no doc comment for standard method
+func (c *InMemoryEvaluationCache) Get(key subsystems.EvaluationCacheKey) (subsystems.EvaluationCacheEntry, bool) {
+	// Trying to use a ccache.LayeredCache after it's been stopped can cause a panic, so it is set to nil by
+	// Close and guarded by the lock.
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.cache == nil {
+		return subsystems.EvaluationCacheEntry{}, false
+	}
+	item := c.cache.Get(key.FlagKey, secondaryKey(key))
+	if item == nil || item.Expired() {
+		return subsystems.EvaluationCacheEntry{}, false
+	}
+	entry, ok := item.Value().(subsystems.EvaluationCacheEntry)
+	return entry, ok
+}
+
+//nolint:revive // no doc comment for standard method
+func (c *InMemoryEvaluationCache) Set(key subsystems.EvaluationCacheKey, entry subsystems.EvaluationCacheEntry) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.cache != nil {
+		c.cache.Set(key.FlagKey, secondaryKey(key), entry, c.ttl)
+	}
+}
+
+//nolint:revive // no doc comment for standard method
+func (c *InMemoryEvaluationCache) InvalidateFlag(flagKey string) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.cache != nil {
+		c.cache.DeleteAll(flagKey)
+	}
+}
+
+//nolint:revive // no doc comment for standard method
+func (c *InMemoryEvaluationCache) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cache != nil {
+		c.cache.Stop()
+		c.cache = nil
+	}
+	return nil
+}
+
+func secondaryKey(key subsystems.EvaluationCacheKey) string {
+	return strconv.Itoa(key.FlagVersion) + ":" + key.ContextFingerprint
+}
@@ -0,0 +1,80 @@
+package evalcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldeval "github.com/launchdarkly/go-server-sdk-evaluation/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeEntry(value string) subsystems.EvaluationCacheEntry {
+	return subsystems.EvaluationCacheEntry{
+		Result: ldeval.Result{Detail: ldreason.NewEvaluationDetail(ldvalue.String(value), 0, ldreason.NewEvalReasonOff())},
+	}
+}
+
+func TestInMemoryEvaluationCache(t *testing.T) {
+	key1 := subsystems.EvaluationCacheKey{FlagKey: "flag1", FlagVersion: 1, ContextFingerprint: "a"}
+	key2 := subsystems.EvaluationCacheKey{FlagKey: "flag1", FlagVersion: 1, ContextFingerprint: "b"}
+	key3 := subsystems.EvaluationCacheKey{FlagKey: "flag2", FlagVersion: 1, ContextFingerprint: "a"}
+
+	t.Run("get and set", func(t *testing.T) {
+		cache := NewInMemoryEvaluationCache(100, time.Minute)
+		defer cache.Close()
+
+		_, ok := cache.Get(key1)
+		assert.False(t, ok)
+
+		cache.Set(key1, makeEntry("x"))
+		entry, ok := cache.Get(key1)
+		assert.True(t, ok)
+		assert.Equal(t, makeEntry("x"), entry)
+
+		_, ok = cache.Get(subsystems.EvaluationCacheKey{FlagKey: "flag1", FlagVersion: 2, ContextFingerprint: "a"})
+		assert.False(t, ok)
+	})
+
+	t.Run("invalidate flag", func(t *testing.T) {
+		cache := NewInMemoryEvaluationCache(100, time.Minute)
+		defer cache.Close()
+
+		cache.Set(key1, makeEntry("x"))
+		cache.Set(key2, makeEntry("y"))
+		cache.Set(key3, makeEntry("z"))
+		cache.InvalidateFlag("flag1")
+
+		_, ok := cache.Get(key1)
+		assert.False(t, ok)
+		_, ok = cache.Get(key2)
+		assert.False(t, ok)
+		_, ok = cache.Get(key3)
+		assert.True(t, ok)
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		cache := NewInMemoryEvaluationCache(100, time.Millisecond*10)
+		defer cache.Close()
+
+		cache.Set(key1, makeEntry("x"))
+		assert.Eventually(t, func() bool {
+			_, ok := cache.Get(key1)
+			return !ok
+		}, time.Second, time.Millisecond*10)
+	})
+
+	t.Run("closed cache is empty", func(t *testing.T) {
+		cache := NewInMemoryEvaluationCache(100, time.Minute)
+		cache.Set(key1, makeEntry("x"))
+		assert.NoError(t, cache.Close())
+
+		cache.Set(key1, makeEntry("x"))
+		cache.InvalidateFlag("flag1")
+		_, ok := cache.Get(key1)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,3 @@
+// Package evalcache is an internal package containing the SDK's in-memory implementation of
+// subsystems.EvaluationCache. It is not visible from outside of the SDK.
+package evalcache
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+)
+
+// EventFlushScheduler flushes the event processors of several clients on a shared schedule, so that each
+// event processor does not have to run a flush timer of its own. Event processors that have the same flush
+// interval share one timer and one goroutine. This is used by ClientManager.
+type EventFlushScheduler struct {
+	groups map[time.Duration]*flushGroup
+	closed bool
+	lock   sync.Mutex
+}
+
+type flushGroup struct {
+	processors map[*scheduledProcessor]struct{}
+	closer     chan struct{}
+}
+
+// Each registration has its own pointer, so that it can be removed even if the same processor was added
+// more than once, and even if the processor type is not comparable.
+type scheduledProcessor struct {
+	processor ldevents.EventProcessor
+}
+
+// NewEventFlushScheduler creates an EventFlushScheduler.
+func NewEventFlushScheduler() *EventFlushScheduler {
+	return &EventFlushScheduler{groups: make(map[time.Duration]*flushGroup)}
+}
+
+// Add starts flushing an event processor at the specified interval. It returns a function that stops
+// doing so, which should be called before the event processor is closed.
+//
+// If the scheduler has already been closed, the processor is not flushed.
+func (s *EventFlushScheduler) Add(processor ldevents.EventProcessor, interval time.Duration) func() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return func() {}
+	}
+	group, ok := s.groups[interval]
+	if !ok {
+		group = &flushGroup{
+			processors: make(map[*scheduledProcessor]struct{}),
+			closer:     make(chan struct{}),
+		}
+		s.groups[interval] = group
+		go s.run(group, interval)
+	}
+	entry := &scheduledProcessor{processor: processor}
+	group.processors[entry] = struct{}{}
+
+	var removeOnce sync.Once
+	return func() {
+		removeOnce.Do(func() { s.remove(entry, interval) })
+	}
+}
+
+func (s *EventFlushScheduler) remove(entry *scheduledProcessor, interval time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	group, ok := s.groups[interval]
+	if !ok {
+		return
+	}
+	delete(group.processors, entry)
+	if len(group.processors) == 0 {
+		// No timer is kept running for an interval that no processor uses any more.
+		close(group.closer)
+		delete(s.groups, interval)
+	}
+}
+
+// Close stops flushing all of the event processors.
+func (s *EventFlushScheduler) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	for interval, group := range s.groups {
+		close(group.closer)
+		delete(s.groups, interval)
+	}
+}
+
+func (s *EventFlushScheduler) run(group *flushGroup, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Flush does not block, so it is fine to call it for every processor in turn. The lock is not
+			// held while doing so, in case a processor calls back into the scheduler.
+			s.lock.Lock()
+			processors := make([]ldevents.EventProcessor, 0, len(group.processors))
+			for entry := range group.processors {
+				processors = append(processors, entry.processor)
+			}
+			s.lock.Unlock()
+			for _, processor := range processors {
+				processor.Flush()
+			}
+		case <-group.closer:
+			return
+		}
+	}
+}
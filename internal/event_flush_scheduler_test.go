@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+
+	th "github.com/launchdarkly/go-test-helpers/v3"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flushCountingEventProcessor struct {
+	ldevents.EventProcessor
+	flushes chan struct{}
+}
+
+func newFlushCountingEventProcessor() *flushCountingEventProcessor {
+	return &flushCountingEventProcessor{
+		EventProcessor: ldevents.NewNullEventProcessor(),
+		flushes:        make(chan struct{}, 100),
+	}
+}
+
+func (p *flushCountingEventProcessor) Flush() {
+	p.flushes <- struct{}{}
+}
+
+func TestEventFlushScheduler(t *testing.T) {
+	t.Run("flushes each processor at its interval", func(t *testing.T) {
+		scheduler := NewEventFlushScheduler()
+		defer scheduler.Close()
+		fast1, fast2, slow := newFlushCountingEventProcessor(), newFlushCountingEventProcessor(),
+			newFlushCountingEventProcessor()
+		scheduler.Add(fast1, time.Millisecond*10)
+		scheduler.Add(fast2, time.Millisecond*10)
+		scheduler.Add(slow, time.Hour)
+
+		th.RequireValue(t, fast1.flushes, time.Second)
+		th.RequireValue(t, fast2.flushes, time.Second)
+		th.AssertNoMoreValues(t, slow.flushes, time.Millisecond*50)
+		assert.Len(t, scheduler.groups, 2)
+	})
+
+	t.Run("removed processor is not flushed", func(t *testing.T) {
+		scheduler := NewEventFlushScheduler()
+		defer scheduler.Close()
+		removed, kept := newFlushCountingEventProcessor(), newFlushCountingEventProcessor()
+		remove := scheduler.Add(removed, time.Millisecond*10)
+		scheduler.Add(kept, time.Millisecond*10)
+		remove()
+		remove() // does nothing the second time
+
+		th.RequireValue(t, kept.flushes, time.Second)
+		th.AssertNoMoreValues(t, removed.flushes, time.Millisecond*50)
+	})
+
+	t.Run("timer is stopped when last processor is removed", func(t *testing.T) {
+		scheduler := NewEventFlushScheduler()
+		defer scheduler.Close()
+		remove := scheduler.Add(newFlushCountingEventProcessor(), time.Millisecond*10)
+		remove()
+
+		assert.Len(t, scheduler.groups, 0)
+	})
+
+	t.Run("does not flush after Close", func(t *testing.T) {
+		scheduler := NewEventFlushScheduler()
+		before, after := newFlushCountingEventProcessor(), newFlushCountingEventProcessor()
+		remove := scheduler.Add(before, time.Millisecond*10)
+		scheduler.Close()
+		scheduler.Add(after, time.Millisecond*10)
+		remove()
+
+		th.AssertNoMoreValues(t, before.flushes, time.Millisecond*50)
+		th.AssertNoMoreValues(t, after.flushes, time.Millisecond*50)
+	})
+}
@@ -0,0 +1,386 @@
+package eventspool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+)
+
+const (
+	spoolFilePrefix = "events-"
+	spoolFileSuffix = ".json"
+	tempFileSuffix  = ".tmp"
+
+	// maxRejections is the number of times that a payload can fail to be delivered, while other payloads
+	// are being delivered successfully, before it is discarded.
+	maxRejections = 3
+)
+
+// DiskSpoolSender is an ldevents.EventSender that writes each analytics event payload to a directory
+// before passing it to another EventSender, and deletes it once it has been delivered. Payloads that could
+// not be delivered stay in the directory, and are delivered in the background after the next successful
+// delivery, or when a new DiskSpoolSender is created for the same directory, such as after the process
+// restarts.
+//
+// Delivery is at-least-once: if the process stops after a payload was delivered but before its file was
+// deleted, it is delivered again. Diagnostic payloads are passed through without being spooled.
+//
+// A payload that the destination keeps rejecting, such as one that it considers malformed, would otherwise
+// stop every later payload from being delivered; so if a payload fails while the payloads around it are
+// delivered, it is skipped, and after maxRejections such failures it is discarded.
+type DiskSpoolSender struct {
+	target     ldevents.EventSender
+	directory  string
+	maxBytes   int64
+	maxAge     time.Duration
+	onDropped  func(int)
+	loggers    ldlog.Loggers
+	now        func() time.Time
+	inFlight   map[string]struct{}
+	rejections map[string]int
+	sequence   uint64
+	replaying  bool
+	closed     bool
+	replayWG   sync.WaitGroup
+	pruneLock  sync.Mutex
+	lock       sync.Mutex
+}
+
+// spoolFile describes a payload in the spool directory. The file name contains everything except the
+// size: the time when the payload was spooled, a sequence number to keep names unique, and the number of
+// events in the payload.
+type spoolFile struct {
+	name       string
+	size       int64
+	spooledAt  time.Time
+	sequence   uint64
+	eventCount int
+}
+
+// NewDiskSpoolSender creates a DiskSpoolSender, creating the directory if necessary, and starts
+// delivering any payloads that are already in it.
+//
+// If maxBytes or maxAge is greater than zero, the oldest payloads are discarded when the total size of
+// the spool exceeds maxBytes, and payloads are discarded once they are older than maxAge. The onDropped
+// function, if not nil, is called with the number of events in any payloads that are discarded.
+func NewDiskSpoolSender(
+	target ldevents.EventSender,
+	directory string,
+	maxBytes int64,
+	maxAge time.Duration,
+	onDropped func(eventCount int),
+	loggers ldlog.Loggers,
+) (*DiskSpoolSender, error) {
+	if err := os.MkdirAll(directory, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create event spool directory: %w", err)
+	}
+	s := newDiskSpoolSender(target, directory, maxBytes, maxAge, onDropped, loggers, time.Now)
+	if files := s.listFiles(); len(files) != 0 {
+		s.loggers.Infof("Found %d unsent event payload(s) in %s; will try to deliver them", len(files), directory)
+		s.startReplay(false)
+	}
+	return s, nil
+}
+
+func newDiskSpoolSender(
+	target ldevents.EventSender,
+	directory string,
+	maxBytes int64,
+	maxAge time.Duration,
+	onDropped func(eventCount int),
+	loggers ldlog.Loggers,
+	now func() time.Time,
+) *DiskSpoolSender {
+	return &DiskSpoolSender{
+		target:     target,
+		directory:  directory,
+		maxBytes:   maxBytes,
+		maxAge:     maxAge,
+		onDropped:  onDropped,
+		loggers:    loggers,
+		now:        now,
+		inFlight:   make(map[string]struct{}),
+		rejections: make(map[string]int),
+	}
+}
+
+// SendEventData writes the payload to the spool, and then tries to deliver it.
+func (s *DiskSpoolSender) SendEventData(
+	kind ldevents.EventDataKind,
+	data []byte,
+	eventCount int,
+) ldevents.EventSenderResult {
+	if kind != ldevents.AnalyticsEventDataKind {
+		return s.target.SendEventData(kind, data, eventCount)
+	}
+
+	name, err := s.writeFile(data, eventCount)
+	if err != nil {
+		// We can still try to deliver it, but it will be lost if that fails.
+		s.loggers.Errorf("Unable to write event payload to spool: %s", err)
+		return s.target.SendEventData(kind, data, eventCount)
+	}
+
+	result := s.target.SendEventData(kind, data, eventCount)
+
+	s.lock.Lock()
+	delete(s.inFlight, name)
+	s.lock.Unlock()
+
+	if result.Success {
+		s.removeFile(name)
+		s.startReplay(true)
+	} else {
+		s.prune()
+	}
+	return result
+}
+
+// Close stops delivering spooled payloads in the background, and waits for any payload that is currently
+// being delivered. Payloads that have not been delivered stay in the directory.
+//
+// The SDK calls this after the event processor has been closed.
+func (s *DiskSpoolSender) Close() error {
+	s.lock.Lock()
+	s.closed = true
+	s.lock.Unlock()
+	s.replayWG.Wait()
+	return nil
+}
+
+// startReplay starts delivering spooled payloads on a separate goroutine, unless that is already being
+// done or the sender has been closed. The payloads are not delivered on the caller's goroutine, because
+// that is one of the event processor's flush workers, and the event processor waits for those when it is
+// flushed or closed.
+func (s *DiskSpoolSender) startReplay(afterSuccess bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.replaying || s.closed {
+		return
+	}
+	s.replaying = true
+	s.replayWG.Add(1)
+	go func() {
+		defer s.replayWG.Done()
+		s.replay(afterSuccess)
+	}()
+}
+
+// replay delivers spooled payloads, oldest first, until there are none left, the destination appears to be
+// unavailable, or the sender is closed. Only one replay runs at a time: the caller must have set s.replaying.
+// The afterSuccess parameter is true if a payload has just been delivered.
+//
+// A payload that fails right after or right before a successful delivery is assumed to have been rejected by
+// the destination, and is skipped; two failures in a row mean that the destination is unavailable.
+func (s *DiskSpoolSender) replay(afterSuccess bool) {
+	defer func() {
+		s.lock.Lock()
+		s.replaying = false
+		s.lock.Unlock()
+	}()
+
+	s.prune()
+	previousSucceeded := afterSuccess
+	var previousFailed *spoolFile
+	for _, f := range s.listFiles() {
+		s.lock.Lock()
+		closed := s.closed
+		s.lock.Unlock()
+		if closed {
+			return
+		}
+		result, ok := s.replayFile(f)
+		if !ok {
+			continue
+		}
+		if result.MustShutDown {
+			return
+		}
+		if result.Success {
+			if previousFailed != nil {
+				s.reject(*previousFailed)
+			}
+			previousSucceeded, previousFailed = true, nil
+			continue
+		}
+		switch {
+		case previousSucceeded:
+			s.reject(f)
+			previousSucceeded = false
+		case previousFailed != nil:
+			return
+		default:
+			f := f
+			previousFailed = &f
+		}
+	}
+}
+
+// replayFile delivers one spooled payload, and deletes it if it was delivered. It returns false if the
+// payload no longer exists or could not be read.
+func (s *DiskSpoolSender) replayFile(f spoolFile) (ldevents.EventSenderResult, bool) {
+	// Marking the file as in flight stops prune from discarding it while it is being delivered.
+	s.lock.Lock()
+	s.inFlight[f.name] = struct{}{}
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		delete(s.inFlight, f.name)
+		s.lock.Unlock()
+	}()
+
+	data, err := os.ReadFile(filepath.Join(s.directory, f.name))
+	if err != nil {
+		if !os.IsNotExist(err) { // it might have been discarded by prune() before it was marked
+			s.loggers.Errorf("Unable to read spooled event payload %s: %s", f.name, err)
+			s.drop(f, "it could not be read")
+		}
+		return ldevents.EventSenderResult{}, false
+	}
+	result := s.target.SendEventData(ldevents.AnalyticsEventDataKind, data, f.eventCount)
+	if result.Success {
+		s.removeFile(f.name)
+	}
+	return result, true
+}
+
+// reject records that a payload was not accepted by the destination, and discards it if this has happened
+// maxRejections times.
+func (s *DiskSpoolSender) reject(f spoolFile) {
+	s.lock.Lock()
+	s.rejections[f.name]++
+	rejected := s.rejections[f.name] >= maxRejections
+	s.lock.Unlock()
+	if rejected {
+		s.drop(f, fmt.Sprintf("the destination rejected them %d times", maxRejections))
+	}
+}
+
+// prune discards payloads that are older than maxAge, and then the oldest payloads until the total size
+// is no more than maxBytes.
+func (s *DiskSpoolSender) prune() {
+	// Several flush workers can fail at the same time, and without this they would all try to discard the same
+	// payloads.
+	s.pruneLock.Lock()
+	defer s.pruneLock.Unlock()
+
+	files := s.listFiles()
+	if s.maxAge > 0 {
+		cutoff := s.now().Add(-s.maxAge)
+		for len(files) != 0 && files[0].spooledAt.Before(cutoff) {
+			s.drop(files[0], "it was older than the maximum age")
+			files = files[1:]
+		}
+	}
+	if s.maxBytes > 0 {
+		var total int64
+		for _, f := range files {
+			total += f.size
+		}
+		for len(files) != 0 && total > s.maxBytes {
+			s.drop(files[0], "the spool was full")
+			total -= files[0].size
+			files = files[1:]
+		}
+	}
+}
+
+// drop discards a payload. It does nothing if the payload has already been removed.
+func (s *DiskSpoolSender) drop(f spoolFile, reason string) {
+	if !s.removeFile(f.name) {
+		return
+	}
+	s.loggers.Warnf("Discarded %d spooled analytics event(s) because %s", f.eventCount, reason)
+	if s.onDropped != nil {
+		s.onDropped(f.eventCount)
+	}
+}
+
+func (s *DiskSpoolSender) writeFile(data []byte, eventCount int) (string, error) {
+	s.lock.Lock()
+	s.sequence++
+	name := fmt.Sprintf("%s%d-%d-%d%s", spoolFilePrefix, s.now().UnixNano(), s.sequence, eventCount, spoolFileSuffix)
+	s.inFlight[name] = struct{}{}
+	s.lock.Unlock()
+
+	// The file is written under a temporary name and then renamed, so that a partly written file is never
+	// mistaken for a payload.
+	path := filepath.Join(s.directory, name)
+	err := os.WriteFile(path+tempFileSuffix, data, 0o600)
+	if err == nil {
+		err = os.Rename(path+tempFileSuffix, path)
+	}
+	if err != nil {
+		_ = os.Remove(path + tempFileSuffix)
+		s.lock.Lock()
+		delete(s.inFlight, name)
+		s.lock.Unlock()
+		return "", err
+	}
+	return name, nil
+}
+
+// removeFile deletes a spooled payload, returning true if this call deleted it.
+func (s *DiskSpoolSender) removeFile(name string) bool {
+	s.lock.Lock()
+	delete(s.rejections, name)
+	s.lock.Unlock()
+	if err := os.Remove(filepath.Join(s.directory, name)); err != nil {
+		if !os.IsNotExist(err) {
+			s.loggers.Errorf("Unable to delete spooled event payload %s: %s", name, err)
+		}
+		return false
+	}
+	return true
+}
+
+// listFiles returns the spooled payloads that are not currently being delivered, oldest first.
+func (s *DiskSpoolSender) listFiles() []spoolFile {
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		s.loggers.Errorf("Unable to read event spool directory: %s", err)
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	files := make([]spoolFile, 0, len(entries))
+	for _, entry := range entries {
+		f, ok := parseFileName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, inFlight := s.inFlight[f.name]; inFlight {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			f.size = info.Size()
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].spooledAt.Before(files[j].spooledAt) ||
+			(files[i].spooledAt.Equal(files[j].spooledAt) && files[i].sequence < files[j].sequence)
+	})
+	return files
+}
+
+func parseFileName(name string) (spoolFile, bool) {
+	if !strings.HasPrefix(name, spoolFilePrefix) || !strings.HasSuffix(name, spoolFileSuffix) {
+		return spoolFile{}, false
+	}
+	var nanos int64
+	var sequence uint64
+	var eventCount int
+	fields := strings.TrimSuffix(strings.TrimPrefix(name, spoolFilePrefix), spoolFileSuffix)
+	if n, err := fmt.Sscanf(fields, "%d-%d-%d", &nanos, &sequence, &eventCount); n != 3 || err != nil {
+		return spoolFile{}, false
+	}
+	return spoolFile{name: name, spooledAt: time.Unix(0, nanos), sequence: sequence, eventCount: eventCount}, true
+}
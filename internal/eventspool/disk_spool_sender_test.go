@@ -0,0 +1,410 @@
+package eventspool
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-sdk-common/v3/ldlogtest"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentPayload struct {
+	kind       ldevents.EventDataKind
+	data       string
+	eventCount int
+}
+
+type fakeSender struct {
+	payloads  []sentPayload
+	success   bool
+	rejected  map[string]bool
+	block     chan struct{}
+	blockFrom int // if block is set, only payloads after this many are blocked
+	lock      sync.Mutex
+}
+
+func (f *fakeSender) SendEventData(
+	kind ldevents.EventDataKind,
+	data []byte,
+	eventCount int,
+) ldevents.EventSenderResult {
+	f.lock.Lock()
+	f.payloads = append(f.payloads, sentPayload{kind, string(data), eventCount})
+	success := f.success && !f.rejected[string(data)]
+	block := f.block
+	if len(f.payloads) <= f.blockFrom {
+		block = nil
+	}
+	f.lock.Unlock()
+	if block != nil {
+		<-block
+	}
+	return ldevents.EventSenderResult{Success: success}
+}
+
+func (f *fakeSender) setSuccess(success bool) {
+	f.lock.Lock()
+	f.success = success
+	f.lock.Unlock()
+}
+
+func (f *fakeSender) getPayloads() []sentPayload {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]sentPayload(nil), f.payloads...)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) get() time.Time {
+	return c.now
+}
+
+type spoolTestParams struct {
+	dir     string
+	target  *fakeSender
+	clock   *fakeClock
+	dropped []int
+	mockLog *ldlogtest.MockLog
+}
+
+func (p *spoolTestParams) makeSender(maxBytes int64, maxAge time.Duration) *DiskSpoolSender {
+	return newDiskSpoolSender(p.target, p.dir, maxBytes, maxAge, func(count int) { p.dropped = append(p.dropped, count) },
+		p.mockLog.Loggers, p.clock.get)
+}
+
+func (p *spoolTestParams) spooledFiles(t *testing.T) []string {
+	entries, err := os.ReadDir(p.dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func withSpoolTestParams(t *testing.T, action func(p *spoolTestParams)) {
+	p := &spoolTestParams{
+		dir:     t.TempDir(),
+		target:  &fakeSender{success: true},
+		clock:   &fakeClock{now: time.Unix(1000, 0)},
+		mockLog: ldlogtest.NewMockLog(),
+	}
+	defer p.mockLog.DumpIfTestFailed(t)
+	action(p)
+}
+
+func TestSuccessfulDeliveryDeletesPayload(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, 0)
+
+		result := s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["a"]`), 1)
+		assert.True(t, result.Success)
+
+		assert.Equal(t, []sentPayload{{ldevents.AnalyticsEventDataKind, `["a"]`, 1}}, p.target.getPayloads())
+		assert.Len(t, p.spooledFiles(t), 0)
+	})
+}
+
+func TestFailedDeliveryKeepsPayloadUntilNextSuccess(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, 0)
+
+		p.target.setSuccess(false)
+		result := s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["a"]`), 1)
+		assert.False(t, result.Success)
+		assert.Len(t, p.spooledFiles(t), 1)
+
+		p.clock.now = p.clock.now.Add(time.Second)
+		p.target.setSuccess(true)
+		result = s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["b","c"]`), 2)
+		assert.True(t, result.Success)
+		s.replayWG.Wait()
+
+		assert.Equal(t, []sentPayload{
+			{ldevents.AnalyticsEventDataKind, `["a"]`, 1},
+			{ldevents.AnalyticsEventDataKind, `["b","c"]`, 2},
+			{ldevents.AnalyticsEventDataKind, `["a"]`, 1},
+		}, p.target.getPayloads())
+		assert.Len(t, p.spooledFiles(t), 0)
+		assert.Len(t, p.dropped, 0)
+	})
+}
+
+func TestReplayStopsAtFirstFailure(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, 0)
+
+		p.target.setSuccess(false)
+		for _, data := range []string{`["a"]`, `["b"]`} {
+			s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(data), 1)
+			p.clock.now = p.clock.now.Add(time.Second)
+		}
+		p.target.setSuccess(true)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["c"]`), 1)
+		s.replayWG.Wait()
+
+		payloads := p.target.getPayloads()
+		require.Len(t, payloads, 5)
+		assert.Equal(t, `["a"]`, payloads[3].data)
+		assert.Equal(t, `["b"]`, payloads[4].data)
+		assert.Len(t, p.spooledFiles(t), 0)
+	})
+}
+
+func TestReplayStopsWhenDestinationIsUnavailable(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		p.target.setSuccess(false)
+		s := p.makeSender(0, 0)
+		for _, data := range []string{`["a"]`, `["b"]`, `["c"]`} {
+			s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(data), 1)
+			p.clock.now = p.clock.now.Add(time.Second)
+		}
+
+		s.replaying = true
+		s.replay(false)
+
+		payloads := p.target.getPayloads()
+		require.Len(t, payloads, 5)
+		assert.Equal(t, `["a"]`, payloads[3].data)
+		assert.Equal(t, `["b"]`, payloads[4].data)
+		assert.Len(t, p.spooledFiles(t), 3)
+		assert.Len(t, p.dropped, 0)
+	})
+}
+
+func TestRejectedPayloadDoesNotBlockReplayAndIsEventuallyDropped(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, 0)
+
+		p.target.setSuccess(false)
+		for _, data := range []string{`["bad"]`, `["a"]`} {
+			s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(data), 1)
+			p.clock.now = p.clock.now.Add(time.Second)
+		}
+		p.target.lock.Lock()
+		p.target.rejected = map[string]bool{`["bad"]`: true}
+		p.target.lock.Unlock()
+		p.target.setSuccess(true)
+
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["b"]`), 1)
+		s.replayWG.Wait()
+		payloads := p.target.getPayloads()
+		assert.Equal(t, `["a"]`, payloads[len(payloads)-1].data)
+		assert.Len(t, p.spooledFiles(t), 1)
+		assert.Len(t, p.dropped, 0)
+
+		for i := 1; i < maxRejections; i++ {
+			s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["c"]`), 1)
+			s.replayWG.Wait()
+		}
+		assert.Len(t, p.spooledFiles(t), 0)
+		assert.Equal(t, []int{1}, p.dropped)
+	})
+}
+
+func TestPayloadIsOnlyDroppedOnceWhenPrunedConcurrently(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, time.Minute)
+		p.target.setSuccess(false)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["a","b"]`), 2)
+		p.clock.now = p.clock.now.Add(time.Hour)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.prune()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, []int{2}, p.dropped)
+		assert.Len(t, p.mockLog.GetOutput(ldlog.Warn), 1)
+	})
+}
+
+func TestCloseStopsReplay(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		p.target.setSuccess(false)
+		s := p.makeSender(0, 0)
+		for _, data := range []string{`["a"]`, `["b"]`} {
+			s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(data), 1)
+			p.clock.now = p.clock.now.Add(time.Second)
+		}
+
+		block := make(chan struct{})
+		target := &fakeSender{success: true, block: block}
+		s, err := NewDiskSpoolSender(target, p.dir, 0, 0, nil, ldlog.NewDisabledLoggers())
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return len(target.getPayloads()) == 1 }, time.Second,
+			time.Millisecond*10)
+
+		closed := make(chan struct{})
+		go func() {
+			_ = s.Close()
+			close(closed)
+		}()
+		require.Eventually(t, func() bool {
+			s.lock.Lock()
+			defer s.lock.Unlock()
+			return s.closed
+		}, time.Second, time.Millisecond)
+		close(block)
+
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			require.Fail(t, "timed out waiting for Close")
+		}
+		assert.Len(t, target.getPayloads(), 1)
+		assert.Len(t, p.spooledFiles(t), 1)
+	})
+}
+
+func TestReplayAfterSuccessDoesNotHoldUpSenderAndIsStoppedByClose(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, 0)
+		p.target.setSuccess(false)
+		for _, data := range []string{`["a"]`, `["b"]`, `["c"]`} {
+			s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(data), 1)
+			p.clock.now = p.clock.now.Add(time.Second)
+		}
+		block := make(chan struct{})
+		p.target.lock.Lock()
+		p.target.success = true
+		p.target.block, p.target.blockFrom = block, 4 // only the replayed payloads are blocked
+		p.target.lock.Unlock()
+
+		// The replay is still blocked on the first spooled payload when this returns.
+		result := s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["d"]`), 1)
+		assert.True(t, result.Success)
+		require.Eventually(t, func() bool { return len(p.target.getPayloads()) == 5 }, time.Second,
+			time.Millisecond*10)
+
+		closed := make(chan struct{})
+		go func() {
+			_ = s.Close()
+			close(closed)
+		}()
+		require.Eventually(t, func() bool {
+			s.lock.Lock()
+			defer s.lock.Unlock()
+			return s.closed
+		}, time.Second, time.Millisecond)
+		close(block)
+
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			require.Fail(t, "timed out waiting for Close")
+		}
+		assert.Len(t, p.target.getPayloads(), 5)
+		assert.Len(t, p.spooledFiles(t), 2)
+	})
+}
+
+func TestPayloadsAreDeliveredWhenSpoolIsReopened(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		p.target.setSuccess(false)
+		p.makeSender(0, 0).SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["a"]`), 1)
+		require.Len(t, p.spooledFiles(t), 1)
+
+		target := &fakeSender{success: true}
+		_, err := NewDiskSpoolSender(target, p.dir, 0, 0, nil, ldlog.NewDisabledLoggers())
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool { return len(target.getPayloads()) == 1 }, time.Second,
+			time.Millisecond*10)
+		assert.Equal(t, sentPayload{ldevents.AnalyticsEventDataKind, `["a"]`, 1}, target.getPayloads()[0])
+		require.Eventually(t, func() bool { return len(p.spooledFiles(t)) == 0 }, time.Second,
+			time.Millisecond*10)
+	})
+}
+
+func TestNewDiskSpoolSenderCreatesDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "a", "b")
+	_, err := NewDiskSpoolSender(&fakeSender{}, dir, 0, 0, nil, ldlog.NewDisabledLoggers())
+	require.NoError(t, err)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
+
+func TestPayloadsOlderThanMaxAgeAreDropped(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, time.Minute)
+
+		p.target.setSuccess(false)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["a","b"]`), 2)
+		p.clock.now = p.clock.now.Add(time.Second * 30)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["c"]`), 1)
+		assert.Len(t, p.dropped, 0)
+
+		p.clock.now = p.clock.now.Add(time.Second * 31)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["d"]`), 1)
+
+		assert.Equal(t, []int{2}, p.dropped)
+		assert.Len(t, p.spooledFiles(t), 2)
+		assert.Len(t, p.mockLog.GetOutput(ldlog.Warn), 1)
+	})
+}
+
+func TestOldestPayloadsAreDroppedWhenOverMaxBytes(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(10, 0)
+
+		p.target.setSuccess(false)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["a"]`), 1)
+		p.clock.now = p.clock.now.Add(time.Second)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["b"]`), 3)
+		assert.Len(t, p.dropped, 0)
+
+		p.clock.now = p.clock.now.Add(time.Second)
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["c"]`), 1)
+
+		assert.Equal(t, []int{1}, p.dropped)
+		files := p.spooledFiles(t)
+		require.Len(t, files, 2)
+		data, err := os.ReadFile(filepath.Join(p.dir, files[0]))
+		require.NoError(t, err)
+		assert.Equal(t, `["b"]`, string(data))
+	})
+}
+
+func TestDiagnosticPayloadsAreNotSpooled(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		s := p.makeSender(0, 0)
+
+		p.target.setSuccess(false)
+		result := s.SendEventData(ldevents.DiagnosticEventDataKind, []byte(`{}`), 1)
+		assert.False(t, result.Success)
+
+		assert.Equal(t, []sentPayload{{ldevents.DiagnosticEventDataKind, `{}`, 1}}, p.target.getPayloads())
+		assert.Len(t, p.spooledFiles(t), 0)
+	})
+}
+
+func TestUnrecognizedFilesAreIgnored(t *testing.T) {
+	withSpoolTestParams(t, func(p *spoolTestParams) {
+		require.NoError(t, os.WriteFile(filepath.Join(p.dir, "other.json"), []byte(`[]`), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(p.dir, "events-1-2-3.json.tmp"), []byte(`[]`), 0o600))
+		s := p.makeSender(0, 0)
+
+		s.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`["a"]`), 1)
+
+		assert.Len(t, p.target.getPayloads(), 1)
+		assert.ElementsMatch(t, []string{"other.json", "events-1-2-3.json.tmp"}, p.spooledFiles(t))
+	})
+}
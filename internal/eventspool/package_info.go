@@ -0,0 +1,3 @@
+// Package eventspool is an internal package containing the SDK's disk-backed buffer for analytics event
+// payloads. It is not visible from outside of the SDK.
+package eventspool
@@ -37,6 +37,17 @@ func (s *Selector) Version() int {
 	return s.version
 }
 
+// MarshalJSON marshals a Selector to JSON.
+func (s *Selector) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return nil, errors.New("cannot marshal nil selector")
+	}
+	return json.Marshal(map[string]interface{}{
+		"state":   s.state,
+		"version": s.version,
+	})
+}
+
 // UnmarshalJSON unmarshals a Selector from JSON.
 func (s *Selector) UnmarshalJSON(data []byte) error {
 	var raw map[string]interface{}
@@ -0,0 +1,62 @@
+package fdv2proto
+
+import (
+	"encoding/json"
+
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+)
+
+// SelectorKey is the key under which the selector is stored in SelectorDataKind.
+const SelectorKey = "selector"
+
+type selectorDataKind struct{}
+
+// SelectorDataKind is the DataKind used to save the Selector in a persistent store alongside the data that
+// it identifies. It contains at most one item, whose key is SelectorKey and whose value is a *Selector.
+//
+// Storing the selector as an ordinary item, rather than requiring a new persistent store capability, means
+// that it works with every existing PersistentDataStore implementation.
+var SelectorDataKind ldstoretypes.DataKind = selectorDataKind{} //nolint:gochecknoglobals
+
+// GetName returns the unique namespace identifier for selector objects.
+func (selectorDataKind) GetName() string {
+	return "selector"
+}
+
+// String returns a human-readable string identifier.
+func (selectorDataKind) String() string {
+	return "selector"
+}
+
+// Serialize is used internally by the SDK when communicating with a PersistentDataStore.
+func (selectorDataKind) Serialize(item ldstoretypes.ItemDescriptor) []byte {
+	if selector, ok := item.Item.(*Selector); ok && selector.IsSet() {
+		if bytes, err := json.Marshal(selector); err == nil {
+			return bytes
+		}
+	}
+	return nil
+}
+
+// Deserialize is used internally by the SDK when communicating with a PersistentDataStore.
+func (selectorDataKind) Deserialize(data []byte) (ldstoretypes.ItemDescriptor, error) {
+	var selector Selector
+	if err := json.Unmarshal(data, &selector); err != nil {
+		return ldstoretypes.ItemDescriptor{}, err
+	}
+	return ldstoretypes.ItemDescriptor{Version: selector.Version(), Item: &selector}, nil
+}
+
+// SelectorCollection returns a collection of SelectorDataKind containing the given selector, or an empty
+// collection if the selector is not set. Passing the empty collection to a persistent store's Init method
+// removes any selector that was previously stored.
+func SelectorCollection(selector *Selector) ldstoretypes.Collection {
+	coll := ldstoretypes.Collection{Kind: SelectorDataKind, Items: []ldstoretypes.KeyedItemDescriptor{}}
+	if selector.IsSet() {
+		coll.Items = append(coll.Items, ldstoretypes.KeyedItemDescriptor{
+			Key:  SelectorKey,
+			Item: ldstoretypes.ItemDescriptor{Version: selector.Version(), Item: selector},
+		})
+	}
+	return coll
+}
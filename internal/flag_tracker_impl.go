@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
 
@@ -16,23 +19,38 @@ import (
 // When a value change listener is created with AddFlagValueChangeListener, this is implemented
 // by creating a regular FlagChangeEvent channel and starting a goroutine that reads it and posts
 // events as appropriate to a FlagValueChangeEvent channel; the flagTrackerImpl maintains its own
-// mapping of this to the underlying channel which is necessary for unregistering it.
+// mapping of this to the underlying channel which is necessary for unregistering it. Listeners created
+// with AddFlagValuesChangeListener instead subscribe with a callback, so that they never block the
+// broadcaster (see valuesChangeListener), and evaluate flags with evaluateValuesFn, which does not
+// generate analytics events.
 type flagTrackerImpl struct {
-	broadcaster              *Broadcaster[interfaces.FlagChangeEvent]
-	evaluateFn               func(string, ldcontext.Context, ldvalue.Value) ldvalue.Value
-	valueChangeSubscriptions map[<-chan interfaces.FlagValueChangeEvent]<-chan interfaces.FlagChangeEvent
-	lock                     sync.Mutex
+	broadcaster               *Broadcaster[interfaces.FlagChangeEvent]
+	evaluateFn                func(string, ldcontext.Context, ldvalue.Value) ldvalue.Value
+	evaluateValuesFn          EvaluateFlagValuesFn
+	valueChangeSubscriptions  map[<-chan interfaces.FlagValueChangeEvent]<-chan interfaces.FlagChangeEvent
+	valuesChangeSubscriptions map[<-chan interfaces.FlagValuesChangeBatch]func()
+	lock                      sync.Mutex
 }
 
+// EvaluateFlagValuesFn is the type of the function that FlagTracker uses to evaluate flags for
+// AddFlagValuesChangeListener. It evaluates the specified flags, or all flags if flagKeys is nil, for a
+// context without generating analytics events. Flags that do not exist are omitted from the result. It
+// returns false if flags cannot be evaluated at this time, for instance because the data store is
+// unavailable.
+type EvaluateFlagValuesFn func(context ldcontext.Context, flagKeys []string) (map[string]ldvalue.Value, bool)
+
 // NewFlagTrackerImpl creates the internal implementation of FlagTracker.
 func NewFlagTrackerImpl(
 	broadcaster *Broadcaster[interfaces.FlagChangeEvent],
 	evaluateFn func(flagKey string, context ldcontext.Context, defaultValue ldvalue.Value) ldvalue.Value,
+	evaluateValuesFn EvaluateFlagValuesFn,
 ) interfaces.FlagTracker {
 	return &flagTrackerImpl{
-		broadcaster:              broadcaster,
-		evaluateFn:               evaluateFn,
-		valueChangeSubscriptions: make(map[<-chan interfaces.FlagValueChangeEvent]<-chan interfaces.FlagChangeEvent),
+		broadcaster:               broadcaster,
+		evaluateFn:                evaluateFn,
+		evaluateValuesFn:          evaluateValuesFn,
+		valueChangeSubscriptions:  make(map[<-chan interfaces.FlagValueChangeEvent]<-chan interfaces.FlagChangeEvent),
+		valuesChangeSubscriptions: make(map[<-chan interfaces.FlagValuesChangeBatch]func()),
 	}
 }
 
@@ -46,6 +64,23 @@ func (f *flagTrackerImpl) RemoveFlagChangeListener(listener <-chan interfaces.Fl
 	f.broadcaster.RemoveListener(listener)
 }
 
+// AddFlagChangeListenerFunc is a standard method of FlagTracker.
+func (f *flagTrackerImpl) AddFlagChangeListenerFunc(
+	ctx context.Context,
+	listener func(interfaces.FlagChangeEvent),
+	options interfaces.ListenerOptions,
+) func() {
+	return f.broadcaster.AddCallback(ctx, listener, options)
+}
+
+// AddFlagChangeListenerCtx is a standard method of FlagTracker.
+func (f *flagTrackerImpl) AddFlagChangeListenerCtx(
+	ctx context.Context,
+	options interfaces.ListenerOptions,
+) <-chan interfaces.FlagChangeEvent {
+	return f.broadcaster.AddListenerCtx(ctx, options)
+}
+
 // AddFlagValueChangeListener is a standard method of FlagTracker.
 func (f *flagTrackerImpl) AddFlagValueChangeListener(
 	flagKey string,
@@ -75,6 +110,45 @@ func (f *flagTrackerImpl) RemoveFlagValueChangeListener(listener <-chan interfac
 	}
 }
 
+// AddFlagValuesChangeListener is a standard method of FlagTracker.
+func (f *flagTrackerImpl) AddFlagValuesChangeListener(
+	config interfaces.FlagValuesChangeListenerConfig,
+) <-chan interfaces.FlagValuesChangeBatch {
+	// Copy the slices, since the caller could modify them after this call
+	config.FlagKeys = append([]string(nil), config.FlagKeys...)
+	config.Contexts = append([]ldcontext.Context(nil), config.Contexts...)
+
+	listener := newValuesChangeListener(f.evaluateValuesFn, config)
+	unsubscribe := f.broadcaster.addCallback(nil, listener.flagChanged,
+		interfaces.ListenerOptions{BufferSize: valuesChangeNotificationBufferSize}, listener.close)
+	// The initial evaluation is done before returning, so that any change after this point will be reported.
+	for i, context := range config.Contexts {
+		listener.currentValues[i], _ = f.evaluateValuesFn(context, config.FlagKeys)
+		if listener.currentValues[i] == nil {
+			listener.currentValues[i] = make(map[string]ldvalue.Value)
+		}
+	}
+	go listener.run()
+
+	f.lock.Lock()
+	f.valuesChangeSubscriptions[listener.batchCh] = unsubscribe
+	f.lock.Unlock()
+
+	return listener.batchCh
+}
+
+// RemoveFlagValuesChangeListener is a standard method of FlagTracker.
+func (f *flagTrackerImpl) RemoveFlagValuesChangeListener(listener <-chan interfaces.FlagValuesChangeBatch) {
+	f.lock.Lock()
+	unsubscribe, ok := f.valuesChangeSubscriptions[listener]
+	delete(f.valuesChangeSubscriptions, listener)
+	f.lock.Unlock()
+
+	if ok {
+		unsubscribe()
+	}
+}
+
 func runValueChangeListener(
 	flagCh <-chan interfaces.FlagChangeEvent,
 	valueCh chan<- interfaces.FlagValueChangeEvent,
@@ -103,3 +177,163 @@ func runValueChangeListener(
 		valueCh <- event
 	}
 }
+
+// valuesChangeListener implements AddFlagValuesChangeListener. The broadcaster calls flagChanged, which only
+// records the flag key, so it never has to wait for flags to be evaluated or for the application to read
+// the channel. The listener's own goroutine evaluates the recorded flags and keeps any changes in a pending
+// batch until the application receives it; if more changes are found before then, they are added to the
+// same batch.
+type valuesChangeListener struct {
+	batchCh          chan interfaces.FlagValuesChangeBatch
+	evaluateValuesFn EvaluateFlagValuesFn
+	config           interfaces.FlagValuesChangeListenerConfig
+	watchedKeys      map[string]bool // nil means all flags are watched
+	currentValues    []map[string]ldvalue.Value
+	pendingKeys      map[string]struct{}
+	pending          interfaces.FlagValuesChangeBatch
+	pendingIndex     map[valuesChangeKey]int
+	lock             sync.Mutex
+	wakeCh           chan struct{}
+	closeCh          chan struct{}
+}
+
+type valuesChangeKey struct {
+	contextIndex int
+	flagKey      string
+}
+
+// The flagChanged callback returns almost immediately, so notifications only accumulate in the subscriber's
+// queue while the broadcaster is reporting a large update, which has a notification for each flag. The
+// limit is high so that none of those are discarded.
+const valuesChangeNotificationBufferSize = 10000
+
+func newValuesChangeListener(
+	evaluateValuesFn EvaluateFlagValuesFn,
+	config interfaces.FlagValuesChangeListenerConfig,
+) *valuesChangeListener {
+	l := &valuesChangeListener{
+		batchCh:          make(chan interfaces.FlagValuesChangeBatch),
+		evaluateValuesFn: evaluateValuesFn,
+		config:           config,
+		currentValues:    make([]map[string]ldvalue.Value, len(config.Contexts)),
+		pendingKeys:      make(map[string]struct{}),
+		pendingIndex:     make(map[valuesChangeKey]int),
+		wakeCh:           make(chan struct{}, 1),
+		closeCh:          make(chan struct{}),
+	}
+	if len(config.FlagKeys) > 0 {
+		l.watchedKeys = make(map[string]bool, len(config.FlagKeys))
+		for _, key := range config.FlagKeys {
+			l.watchedKeys[key] = true
+		}
+	}
+	return l
+}
+
+func (l *valuesChangeListener) flagChanged(flagChange interfaces.FlagChangeEvent) {
+	if l.watchedKeys != nil && !l.watchedKeys[flagChange.Key] {
+		return
+	}
+	l.lock.Lock()
+	l.pendingKeys[flagChange.Key] = struct{}{}
+	l.lock.Unlock()
+	select {
+	case l.wakeCh <- struct{}{}:
+	default: // already signaled
+	}
+}
+
+// close is called once the subscription has been removed, or the broadcaster has been closed.
+func (l *valuesChangeListener) close() {
+	close(l.closeCh)
+}
+
+func (l *valuesChangeListener) run() {
+	defer close(l.batchCh)
+	var batchTimer <-chan time.Time
+	for {
+		var sendCh chan<- interfaces.FlagValuesChangeBatch // nil, so that select skips it, if nothing is pending
+		if len(l.pending.Changes) > 0 {
+			sendCh = l.batchCh
+		}
+		select {
+		case <-l.closeCh:
+			return
+		case sendCh <- l.pending:
+			l.pending = interfaces.FlagValuesChangeBatch{}
+			l.pendingIndex = make(map[valuesChangeKey]int)
+			continue
+		case <-l.wakeCh:
+			if l.config.BatchInterval > 0 {
+				if batchTimer == nil {
+					batchTimer = time.After(l.config.BatchInterval)
+				}
+				continue
+			}
+			// Without a batch interval, we still combine any notifications that have been received since
+			// the last evaluation.
+		case <-batchTimer:
+			batchTimer = nil
+		}
+		l.evaluatePendingKeys()
+	}
+}
+
+func (l *valuesChangeListener) evaluatePendingKeys() {
+	l.lock.Lock()
+	keys := make([]string, 0, len(l.pendingKeys))
+	for key := range l.pendingKeys {
+		keys = append(keys, key)
+	}
+	l.pendingKeys = make(map[string]struct{})
+	l.lock.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+
+	for i, context := range l.config.Contexts {
+		newValues, ok := l.evaluateValuesFn(context, keys)
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			oldValue, newValue := l.currentValues[i][key], newValues[key]
+			if newValue.Equal(oldValue) {
+				continue
+			}
+			l.addPendingChange(i, interfaces.ContextFlagValueChangeEvent{
+				Context: context, Key: key, OldValue: oldValue, NewValue: newValue,
+			})
+			if _, exists := newValues[key]; exists {
+				l.currentValues[i][key] = newValue
+			} else {
+				delete(l.currentValues[i], key)
+			}
+		}
+	}
+}
+
+func (l *valuesChangeListener) addPendingChange(contextIndex int, change interfaces.ContextFlagValueChangeEvent) {
+	key := valuesChangeKey{contextIndex: contextIndex, flagKey: change.Key}
+	index, exists := l.pendingIndex[key]
+	if !exists {
+		l.pendingIndex[key] = len(l.pending.Changes)
+		l.pending.Changes = append(l.pending.Changes, change)
+		return
+	}
+	// The application has not received the earlier change yet, so this one replaces it, starting from the
+	// value that the application last saw. If the flag has changed back to that value, nothing is reported.
+	change.OldValue = l.pending.Changes[index].OldValue
+	if !change.NewValue.Equal(change.OldValue) {
+		l.pending.Changes[index] = change
+		return
+	}
+	l.pending.Changes = append(l.pending.Changes[:index], l.pending.Changes[index+1:]...)
+	delete(l.pendingIndex, key)
+	for k, i := range l.pendingIndex {
+		if i > index {
+			l.pendingIndex[k] = i - 1
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	th "github.com/launchdarkly/go-test-helpers/v3"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFlagChangeListeners(t *testing.T) {
@@ -23,7 +24,7 @@ func TestFlagChangeListeners(t *testing.T) {
 
 	broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
 	defer broadcaster.Close()
-	tracker := NewFlagTrackerImpl(broadcaster, nil)
+	tracker := NewFlagTrackerImpl(broadcaster, nil, nil)
 
 	ch1 := tracker.AddFlagChangeListener()
 	ch2 := tracker.AddFlagChangeListener()
@@ -54,7 +55,7 @@ func TestFlagValueChangeListener(t *testing.T) {
 		resultLock.Lock()
 		defer resultLock.Unlock()
 		return resultMap[user.Key()]
-	})
+	}, nil)
 
 	resultMap[user.Key()] = ldvalue.Bool(false)
 	resultMap[otherUser.Key()] = ldvalue.Bool(false)
@@ -88,3 +89,216 @@ func TestFlagValueChangeListener(t *testing.T) {
 	broadcaster.Broadcast(intf.FlagChangeEvent{Key: "other-flag"})
 	th.AssertNoMoreValues(t, ch1, timeout)
 }
+
+func TestFlagValuesChangeListener(t *testing.T) {
+	user1 := lduser.NewUser("user1")
+	user2 := lduser.NewUser("user2")
+	timeout := time.Millisecond * 100
+
+	// values maps context key -> flag key -> value
+	values := map[string]map[string]ldvalue.Value{
+		user1.Key(): {"flag1": ldvalue.Bool(false), "flag2": ldvalue.Int(1)},
+		user2.Key(): {"flag1": ldvalue.Bool(false), "flag2": ldvalue.Int(1)},
+	}
+	var evaluatedKeys [][]string
+	lock := sync.Mutex{}
+	setValue := func(contextKey, flagKey string, value ldvalue.Value) {
+		lock.Lock()
+		defer lock.Unlock()
+		if value.IsNull() {
+			delete(values[contextKey], flagKey)
+		} else {
+			values[contextKey][flagKey] = value
+		}
+	}
+	evaluateValuesFn := func(context ldcontext.Context, flagKeys []string) (map[string]ldvalue.Value, bool) {
+		lock.Lock()
+		defer lock.Unlock()
+		evaluatedKeys = append(evaluatedKeys, flagKeys)
+		ret := make(map[string]ldvalue.Value)
+		for key, value := range values[context.Key()] {
+			if flagKeys == nil || slicesContain(flagKeys, key) {
+				ret[key] = value
+			}
+		}
+		return ret, true
+	}
+
+	t.Run("sends batch of changes for all contexts", func(t *testing.T) {
+		broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
+		defer broadcaster.Close()
+		tracker := NewFlagTrackerImpl(broadcaster, nil, evaluateValuesFn)
+
+		ch := tracker.AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+			Contexts: []ldcontext.Context{user1, user2},
+		})
+		defer tracker.RemoveFlagValuesChangeListener(ch)
+		th.AssertNoMoreValues(t, ch, timeout)
+
+		setValue(user1.Key(), "flag1", ldvalue.Bool(true))
+		setValue(user2.Key(), "flag2", ldvalue.Int(2))
+		setValue(user2.Key(), "flag3", ldvalue.String("new"))
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag1"})
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag2"})
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag3"})
+
+		var changes []intf.ContextFlagValueChangeEvent
+		for len(changes) < 3 {
+			batch := th.RequireValue(t, ch, time.Second)
+			changes = append(changes, batch.Changes...)
+		}
+		assert.ElementsMatch(t, []intf.ContextFlagValueChangeEvent{
+			{Context: user1, Key: "flag1", OldValue: ldvalue.Bool(false), NewValue: ldvalue.Bool(true)},
+			{Context: user2, Key: "flag2", OldValue: ldvalue.Int(1), NewValue: ldvalue.Int(2)},
+			{Context: user2, Key: "flag3", OldValue: ldvalue.Null(), NewValue: ldvalue.String("new")},
+		}, changes)
+		th.AssertNoMoreValues(t, ch, timeout)
+
+		setValue(user2.Key(), "flag3", ldvalue.Null())
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag3"})
+		batch := th.RequireValue(t, ch, time.Second)
+		assert.Equal(t, []intf.ContextFlagValueChangeEvent{
+			{Context: user2, Key: "flag3", OldValue: ldvalue.String("new"), NewValue: ldvalue.Null()},
+		}, batch.Changes)
+	})
+
+	t.Run("coalesces changes within batch interval", func(t *testing.T) {
+		broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
+		defer broadcaster.Close()
+		tracker := NewFlagTrackerImpl(broadcaster, nil, evaluateValuesFn)
+
+		ch := tracker.AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+			Contexts:      []ldcontext.Context{user1},
+			BatchInterval: timeout,
+		})
+		defer tracker.RemoveFlagValuesChangeListener(ch)
+
+		setValue(user1.Key(), "flag1", ldvalue.String("a"))
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag1"})
+		setValue(user1.Key(), "flag2", ldvalue.String("b"))
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag2"})
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag1"})
+
+		batch := th.RequireValue(t, ch, time.Second)
+		assert.Equal(t, []string{"flag1", "flag2"}, []string{batch.Changes[0].Key, batch.Changes[1].Key})
+		assert.Len(t, batch.Changes, 2)
+		th.AssertNoMoreValues(t, ch, timeout*2)
+	})
+
+	t.Run("only re-evaluates watched flags that changed", func(t *testing.T) {
+		broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
+		defer broadcaster.Close()
+		tracker := NewFlagTrackerImpl(broadcaster, nil, evaluateValuesFn)
+
+		lock.Lock()
+		evaluatedKeys = nil
+		lock.Unlock()
+		ch := tracker.AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+			FlagKeys: []string{"flag1", "flag2"},
+			Contexts: []ldcontext.Context{user1},
+		})
+		defer tracker.RemoveFlagValuesChangeListener(ch)
+
+		setValue(user1.Key(), "flag2", ldvalue.String("c"))
+		setValue(user1.Key(), "flag4", ldvalue.String("d"))
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag4"})
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag2"})
+
+		batch := th.RequireValue(t, ch, time.Second)
+		assert.Equal(t, []intf.ContextFlagValueChangeEvent{
+			{Context: user1, Key: "flag2", OldValue: ldvalue.String("b"), NewValue: ldvalue.String("c")},
+		}, batch.Changes)
+
+		lock.Lock()
+		assert.Equal(t, [][]string{{"flag1", "flag2"}, {"flag2"}}, evaluatedKeys)
+		lock.Unlock()
+	})
+
+	t.Run("does not block broadcaster if channel is not read", func(t *testing.T) {
+		broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
+		defer broadcaster.Close()
+		tracker := NewFlagTrackerImpl(broadcaster, nil, evaluateValuesFn)
+
+		setValue(user1.Key(), "flag5", ldvalue.Int(0))
+		ch := tracker.AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+			FlagKeys: []string{"flag5"},
+			Contexts: []ldcontext.Context{user1},
+		})
+		defer tracker.RemoveFlagValuesChangeListener(ch)
+
+		broadcastsDone := make(chan struct{}, 1)
+		go func() {
+			for i := 1; i <= 100; i++ {
+				setValue(user1.Key(), "flag5", ldvalue.Int(i))
+				broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag5"})
+				time.Sleep(time.Millisecond) // so that the listener evaluates most of the changes separately
+			}
+			broadcastsDone <- struct{}{}
+		}()
+		th.RequireValue(t, broadcastsDone, time.Second*2)
+
+		// The changes that the application did not receive yet are combined into one.
+		var changes []intf.ContextFlagValueChangeEvent
+		for len(changes) == 0 || !changes[len(changes)-1].NewValue.Equal(ldvalue.Int(100)) {
+			batch := th.RequireValue(t, ch, time.Second)
+			require.Len(t, batch.Changes, 1)
+			changes = append(changes, batch.Changes...)
+		}
+		assert.Less(t, len(changes), 100)
+		assert.Equal(t, ldvalue.Int(0), changes[0].OldValue)
+		th.AssertNoMoreValues(t, ch, timeout)
+	})
+
+	t.Run("does not report a change that was reverted before it was received", func(t *testing.T) {
+		broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
+		defer broadcaster.Close()
+		tracker := NewFlagTrackerImpl(broadcaster, nil, evaluateValuesFn)
+
+		setValue(user1.Key(), "flag6", ldvalue.Int(0))
+		ch := tracker.AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+			FlagKeys: []string{"flag6"},
+			Contexts: []ldcontext.Context{user1},
+		})
+		defer tracker.RemoveFlagValuesChangeListener(ch)
+
+		setValue(user1.Key(), "flag6", ldvalue.Int(1))
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag6"})
+		<-time.After(timeout)
+		setValue(user1.Key(), "flag6", ldvalue.Int(0))
+		broadcaster.Broadcast(intf.FlagChangeEvent{Key: "flag6"})
+		<-time.After(timeout)
+		th.AssertNoMoreValues(t, ch, timeout)
+	})
+
+	t.Run("closing broadcaster closes channel", func(t *testing.T) {
+		broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
+		tracker := NewFlagTrackerImpl(broadcaster, nil, evaluateValuesFn)
+
+		ch := tracker.AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+			Contexts: []ldcontext.Context{user1},
+		})
+		broadcaster.Close()
+		th.AssertChannelClosed(t, ch, time.Second)
+	})
+
+	t.Run("remove closes channel", func(t *testing.T) {
+		broadcaster := NewBroadcaster[interfaces.FlagChangeEvent]()
+		defer broadcaster.Close()
+		tracker := NewFlagTrackerImpl(broadcaster, nil, evaluateValuesFn)
+
+		ch := tracker.AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+			Contexts: []ldcontext.Context{user1},
+		})
+		tracker.RemoveFlagValuesChangeListener(ch)
+		th.AssertChannelClosed(t, ch, time.Second)
+	})
+}
+
+func slicesContain(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
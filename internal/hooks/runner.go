@@ -2,9 +2,11 @@ package hooks
 
 import (
 	gocontext "context"
+	"fmt"
 
 	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
 	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
 	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
 	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
@@ -44,6 +46,71 @@ func (h *Runner) RunEvaluation(
 	return detail, flag, err
 }
 
+// RunTrack runs the track series surrounding the given function, which sends the custom event.
+func (h *Runner) RunTrack(ctx gocontext.Context, seriesContext ldhooks.TrackSeriesContext, fn func() error) error {
+	if len(h.hooks) == 0 {
+		return fn()
+	}
+	e := newSeriesExecution[ldhooks.TrackSeries](h.hooks,
+		fmt.Sprintf("tracking of event \"%s\"", seriesContext.Key()), &h.loggers)
+	e.executeStage(false, "BeforeTrack",
+		func(series ldhooks.TrackSeries, data ldhooks.SeriesData) (ldhooks.SeriesData, error) {
+			return series.BeforeTrack(ctx, seriesContext, data)
+		})
+	err := fn()
+	e.executeStage(true, "AfterTrack",
+		func(series ldhooks.TrackSeries, data ldhooks.SeriesData) (ldhooks.SeriesData, error) {
+			return series.AfterTrack(ctx, seriesContext, data)
+		})
+	return err
+}
+
+// RunIdentify runs the identify series surrounding the given function, which sends the identify event.
+func (h *Runner) RunIdentify(
+	ctx gocontext.Context,
+	seriesContext ldhooks.IdentifySeriesContext,
+	fn func() error,
+) error {
+	if len(h.hooks) == 0 {
+		return fn()
+	}
+	e := newSeriesExecution[ldhooks.IdentifySeries](h.hooks, "identify", &h.loggers)
+	e.executeStage(false, "BeforeIdentify",
+		func(series ldhooks.IdentifySeries, data ldhooks.SeriesData) (ldhooks.SeriesData, error) {
+			return series.BeforeIdentify(ctx, seriesContext, data)
+		})
+	err := fn()
+	e.executeStage(true, "AfterIdentify",
+		func(series ldhooks.IdentifySeries, data ldhooks.SeriesData) (ldhooks.SeriesData, error) {
+			return series.AfterIdentify(ctx, seriesContext, data)
+		})
+	return err
+}
+
+// RunMigration runs the migration series surrounding the given function, which performs the migration
+// operation and returns the stage that was used and the error, if any, from the authoritative origin.
+func (h *Runner) RunMigration(
+	ctx gocontext.Context,
+	seriesContext ldhooks.MigrationSeriesContext,
+	fn func() (ldmigration.Stage, error),
+) {
+	if len(h.hooks) == 0 {
+		_, _ = fn()
+		return
+	}
+	e := newSeriesExecution[ldhooks.MigrationSeries](h.hooks,
+		fmt.Sprintf("migration %s for flag \"%s\"", seriesContext.Operation(), seriesContext.FlagKey()), &h.loggers)
+	e.executeStage(false, "BeforeMigrationOp",
+		func(series ldhooks.MigrationSeries, data ldhooks.SeriesData) (ldhooks.SeriesData, error) {
+			return series.BeforeMigrationOp(ctx, seriesContext, data)
+		})
+	stage, err := fn()
+	e.executeStage(true, "AfterMigrationOp",
+		func(series ldhooks.MigrationSeries, data ldhooks.SeriesData) (ldhooks.SeriesData, error) {
+			return series.AfterMigrationOp(ctx, seriesContext, data, stage, err)
+		})
+}
+
 // PrepareEvaluationSeries creates an EvaluationExecution suitable for executing evaluation stages.
 func (h *Runner) prepareEvaluationSeries(
 	flagKey string,
@@ -0,0 +1,54 @@
+package hooks
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+)
+
+// seriesExecution represents the state of a running series of stages for one of the optional series, such as
+// ldhooks.TrackSeries. S is the series interface; hooks that do not implement it are skipped.
+type seriesExecution[S any] struct {
+	hooks       []ldhooks.Hook
+	data        []ldhooks.SeriesData
+	description string
+	loggers     *ldlog.Loggers
+}
+
+func newSeriesExecution[S any](hooks []ldhooks.Hook, description string, loggers *ldlog.Loggers) *seriesExecution[S] {
+	data := make([]ldhooks.SeriesData, len(hooks))
+	for i := range hooks {
+		data[i] = ldhooks.EmptySeriesData()
+	}
+	return &seriesExecution[S]{
+		hooks:       hooks,
+		data:        data,
+		description: description,
+		loggers:     loggers,
+	}
+}
+
+func (e *seriesExecution[S]) executeStage(
+	reverse bool,
+	stageName string,
+	fn func(series S, data ldhooks.SeriesData) (ldhooks.SeriesData, error),
+) {
+	iterator := newIterator(reverse, e.hooks)
+	for iterator.hasNext() {
+		i, hook := iterator.getNext()
+		series, ok := hook.(S)
+		if !ok {
+			continue
+		}
+		outData, err := fn(series, e.data[i])
+		if err != nil {
+			e.loggers.Errorf(
+				"During %s, an error was encountered in \"%s\" of the \"%s\" hook: %s",
+				e.description,
+				stageName,
+				hook.Metadata().Name(),
+				err.Error())
+			continue
+		}
+		e.data[i] = outData
+	}
+}
@@ -0,0 +1,125 @@
+package hooks
+
+import (
+	gocontext "context"
+	"errors"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-sdk-common/v3/ldlogtest"
+	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesStages(calls []sharedtest.SeriesHookCall) []string {
+	ret := make([]string, 0, len(calls))
+	for _, c := range calls {
+		ret = append(ret, c.Stage)
+	}
+	return ret
+}
+
+func TestRunTrack(t *testing.T) {
+	ldContext := ldcontext.New("test-context")
+	seriesContext := ldhooks.NewTrackSeriesContextWithMetric("event-key", ldContext, ldvalue.Int(1), 2.5, "method")
+	goContext := gocontext.WithValue(gocontext.Background(), "test-key", "test-value")
+
+	t.Run("with no hooks", func(t *testing.T) {
+		runner := NewRunner(sharedtest.NewTestLoggers(), nil)
+		called := false
+		err := runner.RunTrack(goContext, seriesContext, func() error { called = true; return nil })
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("runs stages in order and passes data", func(t *testing.T) {
+		hookA := sharedtest.NewSeriesTestHook("a")
+		hookB := sharedtest.NewSeriesTestHook("b")
+		runner := NewRunner(sharedtest.NewTestLoggers(), []ldhooks.Hook{hookA, hookB})
+		fnErr := errors.New("from fn")
+
+		err := runner.RunTrack(goContext, seriesContext, func() error {
+			assert.Len(t, hookA.Calls(), 1)
+			assert.Len(t, hookB.Calls(), 1)
+			assert.Equal(t, "BeforeTrack", hookB.Calls()[0].Stage)
+			return fnErr
+		})
+		assert.Equal(t, fnErr, err)
+
+		for _, hook := range []sharedtest.SeriesTestHook{hookA, hookB} {
+			calls := hook.Calls()
+			require.Equal(t, []string{"BeforeTrack", "AfterTrack"}, seriesStages(calls))
+			assert.Equal(t, goContext, calls[0].GoContext)
+			assert.Equal(t, seriesContext, calls[0].SeriesContext)
+			assert.Equal(t, ldhooks.EmptySeriesData(), calls[0].Data)
+			before, _ := calls[1].Data.Get("before")
+			assert.Equal(t, hook.Metadata().Name(), before)
+		}
+	})
+
+	t.Run("hooks that do not implement the series are skipped", func(t *testing.T) {
+		evalHook := sharedtest.NewTestHook("eval")
+		seriesHook := sharedtest.NewSeriesTestHook("series")
+		runner := NewRunner(sharedtest.NewTestLoggers(), []ldhooks.Hook{evalHook, seriesHook})
+
+		assert.NoError(t, runner.RunTrack(goContext, seriesContext, func() error { return nil }))
+
+		evalHook.VerifyNoCalls(t)
+		assert.Len(t, seriesHook.Calls(), 2)
+	})
+
+	t.Run("errors are logged and the previous data is kept", func(t *testing.T) {
+		mockLog := ldlogtest.NewMockLog()
+		hook := sharedtest.NewSeriesTestHook("failing")
+		hook.FailStages["BeforeTrack"] = true
+		runner := NewRunner(mockLog.Loggers, []ldhooks.Hook{hook})
+
+		assert.NoError(t, runner.RunTrack(goContext, seriesContext, func() error { return nil }))
+
+		calls := hook.Calls()
+		require.Len(t, calls, 2)
+		assert.Equal(t, ldhooks.EmptySeriesData(), calls[1].Data)
+		mockLog.AssertMessageMatch(t, true, ldlog.Error,
+			`During tracking of event "event-key", an error was encountered in "BeforeTrack" of the "failing" hook: sorry`)
+	})
+}
+
+func TestRunIdentify(t *testing.T) {
+	seriesContext := ldhooks.NewIdentifySeriesContext(ldcontext.New("test-context"), "method")
+	hook := sharedtest.NewSeriesTestHook("a")
+	runner := NewRunner(sharedtest.NewTestLoggers(), []ldhooks.Hook{hook})
+
+	assert.NoError(t, runner.RunIdentify(gocontext.Background(), seriesContext, func() error { return nil }))
+
+	calls := hook.Calls()
+	require.Equal(t, []string{"BeforeIdentify", "AfterIdentify"}, seriesStages(calls))
+	assert.Equal(t, seriesContext, calls[0].SeriesContext)
+	assert.Equal(t, seriesContext, calls[1].SeriesContext)
+}
+
+func TestRunMigration(t *testing.T) {
+	seriesContext := ldhooks.NewMigrationSeriesContext("flag-key", ldcontext.New("test-context"), ldmigration.Off,
+		ldmigration.Write, "method")
+	hookA := sharedtest.NewSeriesTestHook("a")
+	hookB := sharedtest.NewSeriesTestHook("b")
+	runner := NewRunner(sharedtest.NewTestLoggers(), []ldhooks.Hook{hookA, hookB})
+	opErr := errors.New("write failed")
+
+	runner.RunMigration(gocontext.Background(), seriesContext, func() (ldmigration.Stage, error) {
+		return ldmigration.Shadow, opErr
+	})
+
+	for _, hook := range []sharedtest.SeriesTestHook{hookA, hookB} {
+		calls := hook.Calls()
+		require.Equal(t, []string{"BeforeMigrationOp", "AfterMigrationOp"}, seriesStages(calls))
+		assert.Equal(t, seriesContext, calls[1].SeriesContext)
+		assert.Equal(t, ldmigration.Shadow, calls[1].MigrationStage)
+		assert.Equal(t, opErr, calls[1].Err)
+	}
+}
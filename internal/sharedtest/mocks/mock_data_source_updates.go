@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -124,3 +125,19 @@ func (m *mockDataStoreStatusProvider) AddStatusListener() <-chan interfaces.Data
 
 func (m *mockDataStoreStatusProvider) RemoveStatusListener(ch <-chan interfaces.DataStoreStatus) {
 }
+
+// The mock only supports one channel for status updates, so this does not receive anything.
+func (m *mockDataStoreStatusProvider) AddStatusListenerFunc(
+	context.Context,
+	func(interfaces.DataStoreStatus),
+	interfaces.ListenerOptions,
+) func() {
+	return func() {}
+}
+
+func (m *mockDataStoreStatusProvider) AddStatusListenerCtx(
+	context.Context,
+	interfaces.ListenerOptions,
+) <-chan interfaces.DataStoreStatus {
+	return m.statusCh
+}
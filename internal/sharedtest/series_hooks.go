@@ -0,0 +1,112 @@
+package sharedtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+)
+
+// SeriesHookCall is a call to one of the stages of the optional hook series, as recorded by SeriesTestHook.
+type SeriesHookCall struct {
+	// Stage is the name of the stage, such as "BeforeTrack".
+	Stage string
+	// GoContext is the context.Context that was passed to the stage.
+	GoContext context.Context
+	// SeriesContext is the TrackSeriesContext, IdentifySeriesContext, or MigrationSeriesContext.
+	SeriesContext any
+	// Data is the series data that was passed to the stage.
+	Data ldhooks.SeriesData
+	// MigrationStage is the stage passed to AfterMigrationOp.
+	MigrationStage ldmigration.Stage
+	// Err is the error passed to AfterMigrationOp.
+	Err error
+}
+
+// SeriesTestHook is a hook for testing the track, identify, and migration series, to be used only by the SDK tests.
+//
+// Each Before stage adds the hook name to the series data under the key "before", so tests can verify that
+// data is passed from the Before stage to the After stage. If FailStages contains a stage name, that stage
+// returns an error instead.
+type SeriesTestHook struct {
+	ldhooks.Unimplemented
+	name       string
+	calls      *[]SeriesHookCall
+	FailStages map[string]bool
+}
+
+// NewSeriesTestHook creates a new SeriesTestHook.
+func NewSeriesTestHook(name string) SeriesTestHook {
+	return SeriesTestHook{name: name, calls: &[]SeriesHookCall{}, FailStages: make(map[string]bool)}
+}
+
+// Calls returns the calls that have been recorded so far.
+func (h SeriesTestHook) Calls() []SeriesHookCall {
+	return append([]SeriesHookCall(nil), *h.calls...)
+}
+
+// Metadata gets the meta-data for the hook.
+func (h SeriesTestHook) Metadata() ldhooks.Metadata {
+	return ldhooks.NewMetadata(h.name)
+}
+
+// BeforeTrack records the call.
+func (h SeriesTestHook) BeforeTrack(
+	ctx context.Context, seriesContext ldhooks.TrackSeriesContext, data ldhooks.SeriesData,
+) (ldhooks.SeriesData, error) {
+	return h.record(SeriesHookCall{Stage: "BeforeTrack", GoContext: ctx, SeriesContext: seriesContext, Data: data})
+}
+
+// AfterTrack records the call.
+func (h SeriesTestHook) AfterTrack(
+	ctx context.Context, seriesContext ldhooks.TrackSeriesContext, data ldhooks.SeriesData,
+) (ldhooks.SeriesData, error) {
+	return h.record(SeriesHookCall{Stage: "AfterTrack", GoContext: ctx, SeriesContext: seriesContext, Data: data})
+}
+
+// BeforeIdentify records the call.
+func (h SeriesTestHook) BeforeIdentify(
+	ctx context.Context, seriesContext ldhooks.IdentifySeriesContext, data ldhooks.SeriesData,
+) (ldhooks.SeriesData, error) {
+	return h.record(SeriesHookCall{Stage: "BeforeIdentify", GoContext: ctx, SeriesContext: seriesContext, Data: data})
+}
+
+// AfterIdentify records the call.
+func (h SeriesTestHook) AfterIdentify(
+	ctx context.Context, seriesContext ldhooks.IdentifySeriesContext, data ldhooks.SeriesData,
+) (ldhooks.SeriesData, error) {
+	return h.record(SeriesHookCall{Stage: "AfterIdentify", GoContext: ctx, SeriesContext: seriesContext, Data: data})
+}
+
+// BeforeMigrationOp records the call.
+func (h SeriesTestHook) BeforeMigrationOp(
+	ctx context.Context, seriesContext ldhooks.MigrationSeriesContext, data ldhooks.SeriesData,
+) (ldhooks.SeriesData, error) {
+	return h.record(SeriesHookCall{Stage: "BeforeMigrationOp", GoContext: ctx, SeriesContext: seriesContext,
+		Data: data})
+}
+
+// AfterMigrationOp records the call.
+func (h SeriesTestHook) AfterMigrationOp(
+	ctx context.Context,
+	seriesContext ldhooks.MigrationSeriesContext,
+	data ldhooks.SeriesData,
+	stage ldmigration.Stage,
+	err error,
+) (ldhooks.SeriesData, error) {
+	return h.record(SeriesHookCall{Stage: "AfterMigrationOp", GoContext: ctx, SeriesContext: seriesContext,
+		Data: data, MigrationStage: stage, Err: err})
+}
+
+func (h SeriesTestHook) record(call SeriesHookCall) (ldhooks.SeriesData, error) {
+	*h.calls = append(*h.calls, call)
+	if h.FailStages[call.Stage] {
+		return ldhooks.EmptySeriesData(), errors.New("sorry")
+	}
+	if strings.HasPrefix(call.Stage, "Before") {
+		return ldhooks.NewEvaluationSeriesBuilder(call.Data).Set("before", h.name).Build(), nil
+	}
+	return call.Data, nil
+}
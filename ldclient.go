@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"time"
 
@@ -28,7 +29,9 @@ import (
 	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/hooks"
 	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
 )
 
 // Version is the SDK version.
@@ -65,6 +68,15 @@ const (
 
 	migrationVarFuncName   = "LDClient.MigrationVariation"
 	migrationVarExFuncName = "LDClient.MigrationVariationCtx"
+
+	identifyFuncName      = "LDClient.Identify"
+	identifyExFuncName    = "LDClient.IdentifyCtx"
+	trackEventFuncName    = "LDClient.TrackEvent"
+	trackEventExFuncName  = "LDClient.TrackEventCtx"
+	trackDataFuncName     = "LDClient.TrackData"
+	trackDataExFuncName   = "LDClient.TrackDataCtx"
+	trackMetricFuncName   = "LDClient.TrackMetric"
+	trackMetricExFuncName = "LDClient.TrackMetricCtx"
 )
 
 // dataSystem represents the requirements the client has for storing/retrieving/detecting changes related
@@ -92,6 +104,7 @@ type dataSystem interface {
 
 var (
 	_ dataSystem = &datasystem.FDv1{}
+	_ dataSystem = &datasystem.FDv2{}
 )
 
 // LDClient is the LaunchDarkly client.
@@ -119,6 +132,7 @@ type LDClient struct {
 	bigSegmentStoreStatusBroadcaster *internal.Broadcaster[interfaces.BigSegmentStoreStatus]
 	bigSegmentStoreStatusProvider    interfaces.BigSegmentStoreStatusProvider
 	bigSegmentStoreWrapper           *ldstoreimpl.BigSegmentStoreWrapper
+	evaluationCache                  *evaluationCacheManager
 	eventsDefault                    eventsScope
 	eventsWithReasons                eventsScope
 	withEventsDisabled               interfaces.LDClientInterface
@@ -210,6 +224,17 @@ func MakeClient(sdkKey string, waitFor time.Duration) (*LDClient, error) {
 // For more about the difference between an initialized and uninitialized client, and other ways to monitor
 // the client's status, see [LDClient.Initialized] and [LDClient.GetDataSourceStatusProvider].
 func MakeCustomClient(sdkKey string, config Config, waitFor time.Duration) (*LDClient, error) {
+	return makeCustomClient(sdkKey, config, waitFor, nil)
+}
+
+// makeCustomClient is the implementation of MakeCustomClient. If flushScheduler is not nil, the client's
+// events are flushed by it rather than by a timer of the client's own; this is used by ClientManager.
+func makeCustomClient(
+	sdkKey string,
+	config Config,
+	waitFor time.Duration,
+	flushScheduler *internal.EventFlushScheduler,
+) (*LDClient, error) {
 	// Ensure that any intermediate components we create will be disposed of if we return an error
 	client := &LDClient{sdkKey: sdkKey}
 	clientValid := false
@@ -227,6 +252,7 @@ func MakeCustomClient(sdkKey string, config Config, waitFor time.Duration) (*LDC
 	if err != nil {
 		return nil, err
 	}
+	clientContext.EventFlushScheduler = flushScheduler
 
 	// Do not create a diagnostics manager if diagnostics are disabled, or if we're not using the standard event processor.
 	if !config.DiagnosticOptOut {
@@ -243,11 +269,19 @@ func MakeCustomClient(sdkKey string, config Config, waitFor time.Duration) (*LDC
 
 	client.offline = config.Offline
 
-	system, err := datasystem.NewFDv1(config.Offline, config.DataStore, config.DataSource, clientContext)
-	if err != nil {
-		return nil, err
+	if config.DataSystem != nil {
+		system, err := datasystem.NewFDv2(config.Offline, config.DataSystem, clientContext)
+		if err != nil {
+			return nil, err
+		}
+		client.dataSystem = system
+	} else {
+		system, err := datasystem.NewFDv1(config.Offline, config.DataStore, config.DataSource, clientContext)
+		if err != nil {
+			return nil, err
+		}
+		client.dataSystem = system
 	}
-	client.dataSystem = system
 
 	bigSegments := config.BigSegments
 	if bigSegments == nil {
@@ -283,13 +317,15 @@ func MakeCustomClient(sdkKey string, config Config, waitFor time.Duration) (*LDC
 	}
 
 	dataProvider := ldstoreimpl.NewDataStoreEvaluatorDataProvider(client.dataSystem.Store(), loggers)
-	evalOptions := []ldeval.EvaluatorOption{
-		ldeval.EvaluatorOptionErrorLogger(client.loggers.ForLevel(ldlog.Error)),
-	}
-	if client.bigSegmentStoreWrapper != nil {
-		evalOptions = append(evalOptions, ldeval.EvaluatorOptionBigSegmentProvider(client.bigSegmentStoreWrapper))
+	client.evaluator = client.newEvaluator(dataProvider)
+
+	if config.EvaluationCache != nil {
+		cache, err := config.EvaluationCache.Build(clientContext)
+		if err != nil {
+			return nil, err
+		}
+		client.evaluationCache = newEvaluationCacheManager(cache, client.dataSystem.FlagChangeEventBroadcaster())
 	}
-	client.evaluator = ldeval.NewEvaluatorWithOptions(dataProvider, evalOptions...)
 
 	client.eventProcessor, err = eventProcessorFactory.Build(clientContext)
 	if err != nil {
@@ -312,6 +348,7 @@ func MakeCustomClient(sdkKey string, config Config, waitFor time.Duration) (*LDC
 			value, _ := client.JSONVariation(flagKey, context, defaultValue)
 			return value
 		},
+		client.evaluateFlagValuesWithoutEvents,
 	)
 
 	client.hookRunner = hooks.NewRunner(loggers, config.Hooks)
@@ -390,8 +427,8 @@ func (client *LDClient) migrationVariation(
 		defaultStageAsValue,
 		method,
 		func() (ldreason.EvaluationDetail, *ldmodel.FeatureFlag, error) {
-			detail, flag, err := client.variationAndFlag(key, context, defaultStageAsValue, true,
-				eventsScope)
+			detail, flag, err := client.variationAndFlag(client.evaluateInternal, key, context, defaultStageAsValue, true,
+				nil, eventsScope)
 
 			if err != nil {
 				// Detail will already contain the default.
@@ -420,18 +457,33 @@ func (client *LDClient) migrationVariation(
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/identify#go
 func (client *LDClient) Identify(context ldcontext.Context) error {
-	if client.eventsDefault.disabled {
-		return nil
-	}
-	if err := context.Err(); err != nil {
-		client.loggers.Warnf("Identify called with invalid context: %s", err)
-		return nil // Don't return an error value because we didn't in the past and it might confuse users
-	}
+	return client.identify(gocontext.TODO(), context, identifyFuncName)
+}
 
-	// Identify events should always sample
-	evt := client.eventsDefault.factory.NewIdentifyEventData(ldevents.Context(context), ldvalue.NewOptionalInt(1))
-	client.eventProcessor.RecordIdentifyEvent(evt)
-	return nil
+// IdentifyCtx is the same as [LDClient.Identify], but accepts a context.Context.
+//
+// The context.Context is used by hook implementations that implement [ldhooks.IdentifySeries].
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/identify#go
+func (client *LDClient) IdentifyCtx(ctx gocontext.Context, context ldcontext.Context) error {
+	return client.identify(ctx, context, identifyExFuncName)
+}
+
+func (client *LDClient) identify(ctx gocontext.Context, context ldcontext.Context, method string) error {
+	return client.hookRunner.RunIdentify(ctx, ldhooks.NewIdentifySeriesContext(context, method), func() error {
+		if client.eventsDefault.disabled {
+			return nil
+		}
+		if err := context.Err(); err != nil {
+			client.loggers.Warnf("Identify called with invalid context: %s", err)
+			return nil // Don't return an error value because we didn't in the past and it might confuse users
+		}
+
+		// Identify events should always sample
+		evt := client.eventsDefault.factory.NewIdentifyEventData(ldevents.Context(context), ldvalue.NewOptionalInt(1))
+		client.eventProcessor.RecordIdentifyEvent(evt)
+		return nil
+	})
 }
 
 // TrackEvent reports an event associated with an evaluation context.
@@ -443,7 +495,17 @@ func (client *LDClient) Identify(context ldcontext.Context) error {
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/events#go
 func (client *LDClient) TrackEvent(eventName string, context ldcontext.Context) error {
-	return client.TrackData(eventName, context, ldvalue.Null())
+	return client.track(gocontext.TODO(),
+		ldhooks.NewTrackSeriesContext(eventName, context, ldvalue.Null(), trackEventFuncName))
+}
+
+// TrackEventCtx is the same as [LDClient.TrackEvent], but accepts a context.Context.
+//
+// The context.Context is used by hook implementations that implement [ldhooks.TrackSeries].
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/events#go
+func (client *LDClient) TrackEventCtx(ctx gocontext.Context, eventName string, context ldcontext.Context) error {
+	return client.track(ctx, ldhooks.NewTrackSeriesContext(eventName, context, ldvalue.Null(), trackEventExFuncName))
 }
 
 // TrackData reports an event associated with an evaluation context, and adds custom data.
@@ -458,24 +520,21 @@ func (client *LDClient) TrackEvent(eventName string, context ldcontext.Context)
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/events#go
 func (client *LDClient) TrackData(eventName string, context ldcontext.Context, data ldvalue.Value) error {
-	if client.eventsDefault.disabled {
-		return nil
-	}
-	if err := context.Err(); err != nil {
-		client.loggers.Warnf("Track called with invalid context: %s", err)
-		return nil // Don't return an error value because we didn't in the past and it might confuse users
-	}
+	return client.track(gocontext.TODO(), ldhooks.NewTrackSeriesContext(eventName, context, data, trackDataFuncName))
+}
 
-	client.eventProcessor.RecordCustomEvent(
-		client.eventsDefault.factory.NewCustomEventData(
-			eventName,
-			ldevents.Context(context),
-			data,
-			false,
-			0,
-			ldvalue.NewOptionalInt(1),
-		))
-	return nil
+// TrackDataCtx is the same as [LDClient.TrackData], but accepts a context.Context.
+//
+// The context.Context is used by hook implementations that implement [ldhooks.TrackSeries].
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/events#go
+func (client *LDClient) TrackDataCtx(
+	ctx gocontext.Context,
+	eventName string,
+	context ldcontext.Context,
+	data ldvalue.Value,
+) error {
+	return client.track(ctx, ldhooks.NewTrackSeriesContext(eventName, context, data, trackDataExFuncName))
 }
 
 // TrackMetric reports an event associated with an evaluation context, and adds a numeric value.
@@ -496,23 +555,58 @@ func (client *LDClient) TrackMetric(
 	metricValue float64,
 	data ldvalue.Value,
 ) error {
-	if client.eventsDefault.disabled {
+	return client.track(gocontext.TODO(),
+		ldhooks.NewTrackSeriesContextWithMetric(eventName, context, data, metricValue, trackMetricFuncName))
+}
+
+// TrackMetricCtx is the same as [LDClient.TrackMetric], but accepts a context.Context.
+//
+// The context.Context is used by hook implementations that implement [ldhooks.TrackSeries].
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/events#go
+func (client *LDClient) TrackMetricCtx(
+	ctx gocontext.Context,
+	eventName string,
+	context ldcontext.Context,
+	metricValue float64,
+	data ldvalue.Value,
+) error {
+	return client.track(ctx,
+		ldhooks.NewTrackSeriesContextWithMetric(eventName, context, data, metricValue, trackMetricExFuncName))
+}
+
+func (client *LDClient) track(ctx gocontext.Context, seriesContext ldhooks.TrackSeriesContext) error {
+	return client.hookRunner.RunTrack(ctx, seriesContext, func() error {
+		if client.eventsDefault.disabled {
+			return nil
+		}
+		metricValue, hasMetric := seriesContext.MetricValue()
+		if err := seriesContext.Context().Err(); err != nil {
+			if hasMetric {
+				client.loggers.Warnf("TrackMetric called with invalid context: %s", err)
+			} else {
+				client.loggers.Warnf("Track called with invalid context: %s", err)
+			}
+			return nil // Don't return an error value because we didn't in the past and it might confuse users
+		}
+
+		client.eventProcessor.RecordCustomEvent(
+			client.eventsDefault.factory.NewCustomEventData(
+				seriesContext.Key(),
+				ldevents.Context(seriesContext.Context()),
+				seriesContext.Data(),
+				hasMetric,
+				metricValue,
+				ldvalue.NewOptionalInt(1),
+			))
 		return nil
-	}
-	if err := context.Err(); err != nil {
-		client.loggers.Warnf("TrackMetric called with invalid context: %s", err)
-		return nil // Don't return an error value because we didn't in the past and it might confuse users
-	}
-	client.eventProcessor.RecordCustomEvent(
-		client.eventsDefault.factory.NewCustomEventData(
-			eventName,
-			ldevents.Context(context),
-			data,
-			true,
-			metricValue,
-			ldvalue.NewOptionalInt(1),
-		))
-	return nil
+	})
+}
+
+// Returns the hook runner so that a Migrator created with this client can run the migration series of any
+// configured hooks. See migrationHooksProvider.
+func (client *LDClient) migrationHookRunner() *hooks.Runner {
+	return client.hookRunner
 }
 
 // TrackMigrationOp reports a migration operation event.
@@ -591,6 +685,9 @@ func (client *LDClient) Close() error {
 	if client.bigSegmentStoreWrapper != nil {
 		client.bigSegmentStoreWrapper.Close()
 	}
+	if client.evaluationCache != nil {
+		_ = client.evaluationCache.cache.Close()
+	}
 	return nil
 }
 
@@ -645,20 +742,7 @@ func (client *LDClient) Loggers() interfaces.LDLoggers {
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/all-flags#go
 func (client *LDClient) AllFlagsState(context ldcontext.Context, options ...flagstate.Option) flagstate.AllFlags {
-	valid := true
-	if client.IsOffline() {
-		client.loggers.Warn("Called AllFlagsState in offline mode. Returning empty state")
-		valid = false
-	} else if client.dataSystem.DataAvailability() != datasystem.Refreshed {
-		if client.dataSystem.DataAvailability() == datasystem.Cached {
-			client.loggers.Warn("Called AllFlagsState before client initialization; using last known values from data store")
-		} else {
-			client.loggers.Warn("Called AllFlagsState before client initialization. Data store not available; returning empty state") //nolint:lll
-			valid = false
-		}
-	}
-
-	if !valid {
+	if !client.canEvaluateAllFlags("AllFlagsState") {
 		return flagstate.AllFlags{}
 	}
 
@@ -711,6 +795,61 @@ func (client *LDClient) AllFlagsState(context ldcontext.Context, options ...flag
 	return state.Build()
 }
 
+// canEvaluateAllFlags checks whether there is any flag data that methods like AllFlagsState can use, logging a
+// warning if not, or if the data might be out of date.
+func (client *LDClient) canEvaluateAllFlags(methodName string) bool {
+	if client.IsOffline() {
+		client.loggers.Warnf("Called %s in offline mode. Returning empty state", methodName)
+		return false
+	}
+	if client.dataSystem.DataAvailability() != datasystem.Refreshed {
+		if client.dataSystem.DataAvailability() == datasystem.Cached {
+			client.loggers.Warnf("Called %s before client initialization; using last known values from data store", methodName)
+		} else {
+			client.loggers.Warnf("Called %s before client initialization. Data store not available; returning empty state",
+				methodName)
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateFlagValuesWithoutEvents evaluates the specified flags, or all flags if flagKeys is nil, for
+// FlagTracker.AddFlagValuesChangeListener. Unlike the Variation methods, it does not generate analytics
+// events (including prerequisite events) or run hooks, and unlike AllFlagsState, it does not log a warning
+// if the client has not been initialized.
+func (client *LDClient) evaluateFlagValuesWithoutEvents(
+	context ldcontext.Context,
+	flagKeys []string,
+) (map[string]ldvalue.Value, bool) {
+	if client.IsOffline() || context.Err() != nil || client.dataSystem.DataAvailability() == datasystem.Defaults {
+		return nil, false
+	}
+	store := client.dataSystem.Store()
+	var items []ldstoretypes.KeyedItemDescriptor
+	if flagKeys == nil {
+		var err error
+		if items, err = store.GetAll(datakinds.Features); err != nil {
+			return nil, false
+		}
+	} else {
+		for _, key := range flagKeys {
+			item, err := store.Get(datakinds.Features, key)
+			if err != nil {
+				return nil, false
+			}
+			items = append(items, ldstoretypes.KeyedItemDescriptor{Key: key, Item: item})
+		}
+	}
+	values := make(map[string]ldvalue.Value, len(items))
+	for _, item := range items {
+		if flag, ok := item.Item.Item.(*ldmodel.FeatureFlag); ok {
+			values[item.Key] = client.evaluator.Evaluate(flag, context, nil).Detail.Value
+		}
+	}
+	return values, true
+}
+
 // BoolVariation returns the value of a boolean feature flag for a given evaluation context.
 //
 // Returns defaultVal if there is an error, if the flag doesn't exist, or the feature is turned off and
@@ -718,8 +857,8 @@ func (client *LDClient) AllFlagsState(context ldcontext.Context, options ...flag
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluating#go
 func (client *LDClient) BoolVariation(key string, context ldcontext.Context, defaultVal bool) (bool, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Bool(defaultVal), true,
-		client.eventsDefault, boolVarFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.Bool(defaultVal), true, nil, client.eventsDefault, boolVarFuncName)
 	return detail.Value.BoolValue(), err
 }
 
@@ -732,8 +871,8 @@ func (client *LDClient) BoolVariationDetail(
 	context ldcontext.Context,
 	defaultVal bool,
 ) (bool, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Bool(defaultVal), true,
-		client.eventsWithReasons, boolVarDetailFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.Bool(defaultVal), true, nil, client.eventsWithReasons, boolVarDetailFuncName)
 	return detail.Value.BoolValue(), detail, err
 }
 
@@ -752,8 +891,8 @@ func (client *LDClient) BoolVariationCtx(
 	context ldcontext.Context,
 	defaultVal bool,
 ) (bool, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.Bool(defaultVal), true,
-		client.eventsDefault, boolVarExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.Bool(defaultVal), true,
+		nil, client.eventsDefault, boolVarExFuncName)
 	return detail.Value.BoolValue(), err
 }
 
@@ -770,8 +909,8 @@ func (client *LDClient) BoolVariationDetailCtx(
 	context ldcontext.Context,
 	defaultVal bool,
 ) (bool, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.Bool(defaultVal), true,
-		client.eventsWithReasons, boolVarDetailExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.Bool(defaultVal), true,
+		nil, client.eventsWithReasons, boolVarDetailExFuncName)
 	return detail.Value.BoolValue(), detail, err
 }
 
@@ -785,8 +924,8 @@ func (client *LDClient) BoolVariationDetailCtx(
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluating#go
 func (client *LDClient) IntVariation(key string, context ldcontext.Context, defaultVal int) (int, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Int(defaultVal), true,
-		client.eventsDefault, intVarFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.Int(defaultVal), true, nil, client.eventsDefault, intVarFuncName)
 	return detail.Value.IntValue(), err
 }
 
@@ -799,8 +938,8 @@ func (client *LDClient) IntVariationDetail(
 	context ldcontext.Context,
 	defaultVal int,
 ) (int, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Int(defaultVal), true,
-		client.eventsWithReasons, intVarDetailFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.Int(defaultVal), true, nil, client.eventsWithReasons, intVarDetailFuncName)
 	return detail.Value.IntValue(), detail, err
 }
 
@@ -821,8 +960,8 @@ func (client *LDClient) IntVariationCtx(
 	context ldcontext.Context,
 	defaultVal int,
 ) (int, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.Int(defaultVal), true,
-		client.eventsDefault, intVarExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.Int(defaultVal), true,
+		nil, client.eventsDefault, intVarExFuncName)
 	return detail.Value.IntValue(), err
 }
 
@@ -839,8 +978,8 @@ func (client *LDClient) IntVariationDetailCtx(
 	context ldcontext.Context,
 	defaultVal int,
 ) (int, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.Int(defaultVal), true,
-		client.eventsWithReasons, intVarDetailExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.Int(defaultVal), true,
+		nil, client.eventsWithReasons, intVarDetailExFuncName)
 	return detail.Value.IntValue(), detail, err
 }
 
@@ -852,8 +991,8 @@ func (client *LDClient) IntVariationDetailCtx(
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluating#go
 func (client *LDClient) Float64Variation(key string, context ldcontext.Context, defaultVal float64) (float64, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Float64(defaultVal),
-		true, client.eventsDefault, floatVarFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.Float64(defaultVal), true, nil, client.eventsDefault, floatVarFuncName)
 	return detail.Value.Float64Value(), err
 }
 
@@ -866,8 +1005,8 @@ func (client *LDClient) Float64VariationDetail(
 	context ldcontext.Context,
 	defaultVal float64,
 ) (float64, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Float64(defaultVal),
-		true, client.eventsWithReasons, floatVarDetailFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.Float64(defaultVal), true, nil, client.eventsWithReasons, floatVarDetailFuncName)
 	return detail.Value.Float64Value(), detail, err
 }
 
@@ -886,8 +1025,8 @@ func (client *LDClient) Float64VariationCtx(
 	context ldcontext.Context,
 	defaultVal float64,
 ) (float64, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.Float64(defaultVal), true,
-		client.eventsDefault, floatVarExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.Float64(defaultVal),
+		true, nil, client.eventsDefault, floatVarExFuncName)
 	return detail.Value.Float64Value(), err
 }
 
@@ -904,8 +1043,8 @@ func (client *LDClient) Float64VariationDetailCtx(
 	context ldcontext.Context,
 	defaultVal float64,
 ) (float64, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.Float64(defaultVal), true,
-		client.eventsWithReasons, floatVarDetailExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.Float64(defaultVal),
+		true, nil, client.eventsWithReasons, floatVarDetailExFuncName)
 	return detail.Value.Float64Value(), detail, err
 }
 
@@ -917,8 +1056,8 @@ func (client *LDClient) Float64VariationDetailCtx(
 //
 // For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluating#go
 func (client *LDClient) StringVariation(key string, context ldcontext.Context, defaultVal string) (string, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.String(defaultVal), true,
-		client.eventsDefault, stringVarFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.String(defaultVal), true, nil, client.eventsDefault, stringVarFuncName)
 	return detail.Value.StringValue(), err
 }
 
@@ -931,8 +1070,8 @@ func (client *LDClient) StringVariationDetail(
 	context ldcontext.Context,
 	defaultVal string,
 ) (string, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.String(defaultVal), true,
-		client.eventsWithReasons, stringVarDetailFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context,
+		ldvalue.String(defaultVal), true, nil, client.eventsWithReasons, stringVarDetailFuncName)
 	return detail.Value.StringValue(), detail, err
 }
 
@@ -951,8 +1090,8 @@ func (client *LDClient) StringVariationCtx(
 	context ldcontext.Context,
 	defaultVal string,
 ) (string, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.String(defaultVal), true,
-		client.eventsDefault, stringVarExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.String(defaultVal),
+		true, nil, client.eventsDefault, stringVarExFuncName)
 	return detail.Value.StringValue(), err
 }
 
@@ -969,8 +1108,8 @@ func (client *LDClient) StringVariationDetailCtx(
 	context ldcontext.Context,
 	defaultVal string,
 ) (string, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, ldvalue.String(defaultVal), true,
-		client.eventsWithReasons, stringVarDetailExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, ldvalue.String(defaultVal),
+		true, nil, client.eventsWithReasons, stringVarDetailExFuncName)
 	return detail.Value.StringValue(), detail, err
 }
 
@@ -1002,8 +1141,8 @@ func (client *LDClient) JSONVariation(
 	context ldcontext.Context,
 	defaultVal ldvalue.Value,
 ) (ldvalue.Value, error) {
-	detail, _, err := client.variationWithHooks(gocontext.TODO(), key, context, defaultVal, false, client.eventsDefault,
-		jsonVarFuncName)
+	detail, _, err := client.variationWithHooks(gocontext.TODO(), client.evaluateInternal, key, context, defaultVal, false,
+		nil, client.eventsDefault, jsonVarFuncName)
 	return detail.Value, err
 }
 
@@ -1018,10 +1157,12 @@ func (client *LDClient) JSONVariationDetail(
 ) (ldvalue.Value, ldreason.EvaluationDetail, error) {
 	detail, _, err := client.variationWithHooks(
 		gocontext.TODO(),
+		client.evaluateInternal,
 		key,
 		context,
 		defaultVal,
 		false,
+		nil,
 		client.eventsWithReasons,
 		jsonVarDetailFuncName,
 	)
@@ -1059,8 +1200,8 @@ func (client *LDClient) JSONVariationCtx(
 	context ldcontext.Context,
 	defaultVal ldvalue.Value,
 ) (ldvalue.Value, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, defaultVal, false, client.eventsDefault,
-		jsonVarExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, defaultVal, false, nil,
+		client.eventsDefault, jsonVarExFuncName)
 	return detail.Value, err
 }
 
@@ -1077,8 +1218,8 @@ func (client *LDClient) JSONVariationDetailCtx(
 	context ldcontext.Context,
 	defaultVal ldvalue.Value,
 ) (ldvalue.Value, ldreason.EvaluationDetail, error) {
-	detail, _, err := client.variationWithHooks(ctx, key, context, defaultVal, false, client.eventsWithReasons,
-		jsonVarDetailExFuncName)
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context, defaultVal, false, nil,
+		client.eventsWithReasons, jsonVarDetailExFuncName)
 	return detail.Value, detail, err
 }
 
@@ -1106,6 +1247,20 @@ func (client *LDClient) GetDataStoreStatusProvider() interfaces.DataStoreStatusP
 	return client.dataSystem.DataStoreStatusProvider()
 }
 
+// WriteSnapshot writes all of the flag and segment data that the client currently has to w, as a versioned,
+// checksummed snapshot.
+//
+// A snapshot that was written while the client was connected to LaunchDarkly can be used to start up other
+// clients with last-known-good data, by configuring them with [ldcomponents.BootstrapFromSnapshot] or
+// [ldcomponents.SnapshotInitializer]. This is useful if LaunchDarkly might be unreachable when an application
+// starts, for instance if the snapshot is included when the application is deployed.
+//
+// Returns an error if the client does not have any data yet, or if it cannot read the data store. See
+// [ldstoreimpl.WriteSnapshot] for details of the format.
+func (client *LDClient) WriteSnapshot(w io.Writer) error {
+	return ldstoreimpl.WriteSnapshot(w, client.dataSystem.Store())
+}
+
 // GetFlagTracker returns an interface for tracking changes in feature flag configurations.
 //
 // See [interfaces.FlagTracker] for more about this functionality.
@@ -1146,12 +1301,18 @@ func (client *LDClient) WithEventsDisabled(disabled bool) interfaces.LDClientInt
 	return client.withEventsDisabled
 }
 
+// variationWithHooks evaluates a flag with the specified function, such as evaluateInternal, and runs the
+// evaluation series of any hooks around it. If decode is non-nil, it is called with the flag value before the
+// evaluation event is recorded and before the hooks are notified; if it returns an error, the result is a
+// WRONG_TYPE error.
 func (client *LDClient) variationWithHooks(
 	context gocontext.Context,
+	evaluate flagEvaluationFunc,
 	key string,
 	evalContext ldcontext.Context,
 	defaultVal ldvalue.Value,
 	checkType bool,
+	decode func(ldvalue.Value) error,
 	eventsScope eventsScope,
 	method string,
 ) (ldreason.EvaluationDetail, *ldmodel.FeatureFlag, error) {
@@ -1162,19 +1323,29 @@ func (client *LDClient) variationWithHooks(
 		defaultVal,
 		method,
 		func() (ldreason.EvaluationDetail, *ldmodel.FeatureFlag, error) {
-			return client.variationAndFlag(key, evalContext, defaultVal, checkType, eventsScope)
+			return client.variationAndFlag(evaluate, key, evalContext, defaultVal, checkType, decode, eventsScope)
 		},
 	)
 	return detail, flag, err
 }
 
+// flagEvaluationFunc is the signature of evaluateInternal, which variationAndFlag uses to evaluate the flag.
+type flagEvaluationFunc func(
+	key string,
+	context ldcontext.Context,
+	defaultVal ldvalue.Value,
+	eventsScope eventsScope,
+) (ldeval.Result, *ldmodel.FeatureFlag, error)
+
 // Generic method for evaluating a feature flag for a given evaluation context,
 // returning both the result and the flag.
 func (client *LDClient) variationAndFlag(
+	evaluate flagEvaluationFunc,
 	key string,
 	context ldcontext.Context,
 	defaultVal ldvalue.Value,
 	checkType bool,
+	decode func(ldvalue.Value) error,
 	eventsScope eventsScope,
 ) (ldreason.EvaluationDetail, *ldmodel.FeatureFlag, error) {
 	if err := context.Err(); err != nil {
@@ -1184,46 +1355,63 @@ func (client *LDClient) variationAndFlag(
 	if client.IsOffline() {
 		return newEvaluationError(defaultVal, ldreason.EvalErrorClientNotReady), nil, nil
 	}
-	result, flag, err := client.evaluateInternal(key, context, defaultVal, eventsScope)
+	result, flag, err := evaluate(key, context, defaultVal, eventsScope)
 	if err != nil {
 		result.Detail.Value = defaultVal
 		result.Detail.VariationIndex = ldvalue.OptionalInt{}
 	} else if checkType && defaultVal.Type() != ldvalue.NullType && result.Detail.Value.Type() != defaultVal.Type() {
 		result.Detail = newEvaluationError(defaultVal, ldreason.EvalErrorWrongType)
+	} else if decode != nil && !result.Detail.IsDefaultValue() {
+		if decodeErr := decode(result.Detail.Value); decodeErr != nil {
+			result.Detail = newEvaluationError(defaultVal, ldreason.EvalErrorWrongType)
+			err = fmt.Errorf("unable to decode value of feature flag %q: %w", key, decodeErr)
+		}
 	}
 
 	if !eventsScope.disabled {
-		var eval ldevents.EvaluationData
-		if flag == nil {
-			eval = eventsScope.factory.NewUnknownFlagEvaluationData(
-				key,
-				ldevents.Context(context),
-				defaultVal,
-				result.Detail.Reason,
-			)
-		} else {
-			eval = eventsScope.factory.NewEvaluationData(
-				ldevents.FlagEventProperties{
-					Key:                  flag.Key,
-					Version:              flag.Version,
-					RequireFullEvent:     flag.TrackEvents,
-					DebugEventsUntilDate: flag.DebugEventsUntilDate,
-				},
-				ldevents.Context(context),
-				result.Detail,
-				result.IsExperiment,
-				defaultVal,
-				"",
-				flag.SamplingRatio,
-				flag.ExcludeFromSummaries,
-			)
-		}
-		client.eventProcessor.RecordEvaluation(eval)
+		client.recordEvaluation(key, context, defaultVal, result, flag, eventsScope)
 	}
 
 	return result.Detail, flag, err
 }
 
+// Sends the feature request event for an evaluation. The flag is nil if it was not found.
+func (client *LDClient) recordEvaluation(
+	key string,
+	context ldcontext.Context,
+	defaultVal ldvalue.Value,
+	result ldeval.Result,
+	flag *ldmodel.FeatureFlag,
+	eventsScope eventsScope,
+) {
+	var eval ldevents.EvaluationData
+	if flag == nil {
+		eval = eventsScope.factory.NewUnknownFlagEvaluationData(
+			key,
+			ldevents.Context(context),
+			defaultVal,
+			result.Detail.Reason,
+		)
+	} else {
+		eval = eventsScope.factory.NewEvaluationData(
+			ldevents.FlagEventProperties{
+				Key:                  flag.Key,
+				Version:              flag.Version,
+				RequireFullEvent:     flag.TrackEvents,
+				DebugEventsUntilDate: flag.DebugEventsUntilDate,
+			},
+			ldevents.Context(context),
+			result.Detail,
+			result.IsExperiment,
+			defaultVal,
+			"",
+			flag.SamplingRatio,
+			flag.ExcludeFromSummaries,
+		)
+	}
+	client.eventProcessor.RecordEvaluation(eval)
+}
+
 // Performs all the steps of evaluation except for sending the feature request event (the main one;
 // events for prerequisites will be sent).
 func (client *LDClient) evaluateInternal(
@@ -1231,6 +1419,21 @@ func (client *LDClient) evaluateInternal(
 	context ldcontext.Context,
 	defaultVal ldvalue.Value,
 	eventsScope eventsScope,
+) (ldeval.Result, *ldmodel.FeatureFlag, error) {
+	return client.evaluateFrom(client.dataSystem.Store(), client.evaluator, client.evaluationCache,
+		key, context, defaultVal, eventsScope)
+}
+
+// Same as evaluateInternal, but reads the flag from the specified store and evaluates it with the specified
+// evaluator, which should use the same store. The evaluation cache is optional.
+func (client *LDClient) evaluateFrom(
+	store subsystems.ReadOnlyStore,
+	evaluator ldeval.Evaluator,
+	evaluationCache *evaluationCacheManager,
+	key string,
+	context ldcontext.Context,
+	defaultVal ldvalue.Value,
+	eventsScope eventsScope,
 ) (ldeval.Result, *ldmodel.FeatureFlag, error) {
 	// THIS IS A HIGH-TRAFFIC CODE PATH so performance tuning is important. Please see CONTRIBUTING.md for guidelines
 	// to keep in mind during any changes to the evaluation logic.
@@ -1259,7 +1462,7 @@ func (client *LDClient) evaluateInternal(
 		}
 	}
 
-	itemDesc, storeErr := client.dataSystem.Store().Get(datakinds.Features, key)
+	itemDesc, storeErr := store.Get(datakinds.Features, key)
 
 	if storeErr != nil {
 		client.loggers.Errorf("Encountered error fetching feature from store: %+v", storeErr)
@@ -1282,7 +1485,12 @@ func (client *LDClient) evaluateInternal(
 			fmt.Errorf("unknown feature key: %s. Verify that this feature key exists. Returning default value", key))
 	}
 
-	result := client.evaluator.Evaluate(feature, context, eventsScope.prerequisiteEventRecorder)
+	var result ldeval.Result
+	if evaluationCache != nil {
+		result = evaluationCache.evaluate(evaluator, feature, context, eventsScope.prerequisiteEventRecorder)
+	} else {
+		result = evaluator.Evaluate(feature, context, eventsScope.prerequisiteEventRecorder)
+	}
 	if result.Detail.Reason.GetKind() == ldreason.EvalReasonError && client.logEvaluationErrors {
 		client.loggers.Warnf("Flag evaluation for %s failed with error %s, default value was returned",
 			key, result.Detail.Reason.GetErrorKind())
@@ -1293,6 +1501,17 @@ func (client *LDClient) evaluateInternal(
 	return result, feature, nil
 }
 
+// newEvaluator creates an evaluator with the client's configuration that uses the specified data.
+func (client *LDClient) newEvaluator(dataProvider ldeval.DataProvider) ldeval.Evaluator {
+	evalOptions := []ldeval.EvaluatorOption{
+		ldeval.EvaluatorOptionErrorLogger(client.loggers.ForLevel(ldlog.Error)),
+	}
+	if client.bigSegmentStoreWrapper != nil {
+		evalOptions = append(evalOptions, ldeval.EvaluatorOptionBigSegmentProvider(client.bigSegmentStoreWrapper))
+	}
+	return ldeval.NewEvaluatorWithOptions(dataProvider, evalOptions...)
+}
+
 func newEvaluationError(jsonValue ldvalue.Value, errorKind ldreason.EvalErrorKind) ldreason.EvaluationDetail {
 	return ldreason.EvaluationDetail{
 		Value:  jsonValue,
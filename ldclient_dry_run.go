@@ -0,0 +1,116 @@
+package ldclient
+
+import (
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldeval "github.com/launchdarkly/go-server-sdk-evaluation/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datasystem"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+)
+
+// EvaluateFlagDefinition evaluates a flag configuration supplied by the caller, rather than one that the SDK
+// has received from LaunchDarkly. This is a "dry run": it shows what the context would get if this version of
+// the flag were published, using exactly the same evaluation logic as the Variation methods.
+//
+// The flag is evaluated against the client's current data: any prerequisite flags and segments that it
+// references are read from the data store, just as for a normal evaluation. Each of the segmentOverrides
+// takes the place of the stored segment with the same key, or adds a segment if there is none. If a
+// prerequisite chain refers back to the flag's own key, the supplied flag is used there too.
+//
+// Nothing is stored, and the flag and segments passed in are not modified. No analytics events are sent,
+// hooks are not run, and the evaluation cache (if any) is not used.
+//
+// The returned error is non-nil only if the context is invalid, or if the client has no flag data yet
+// ([ErrClientNotInitialized]); in both cases the reason describes the error. Problems in the flag
+// configuration itself, such as a variation index that is out of range, or a flag or segment that cannot be
+// serialized, are reported in the reason as an error of kind [ldreason.EvalErrorMalformedFlag] with a nil
+// error, as they would be for [LDClient.JSONVariationDetail]. If the flag returns no variation, the value in
+// the result is a null value.
+func (client *LDClient) EvaluateFlagDefinition(
+	flag ldmodel.FeatureFlag,
+	context ldcontext.Context,
+	segmentOverrides ...ldmodel.Segment,
+) (ldreason.EvaluationDetail, error) {
+	if err := context.Err(); err != nil {
+		return ldreason.NewEvaluationDetailForError(ldreason.EvalErrorUserNotSpecified, ldvalue.Null()), err
+	}
+	if client.IsOffline() || client.dataSystem.DataAvailability() == datasystem.Defaults {
+		return ldreason.NewEvaluationDetailForError(ldreason.EvalErrorClientNotReady, ldvalue.Null()),
+			ErrClientNotInitialized
+	}
+
+	// The evaluator relies on the flag and segments having been preprocessed, which happens automatically
+	// if they were built with ldbuilders or parsed from JSON, but might not have happened otherwise. Since
+	// preprocessing modifies them in place, it is done on copies so that the caller's objects can be reused
+	// safely; a round trip through JSON does both.
+	serialization := ldmodel.NewJSONDataModelSerialization()
+	flagCopy, err := copyFlagDefinition(serialization, flag)
+	if err != nil {
+		client.loggers.Warnf("EvaluateFlagDefinition: invalid flag %q: %s", flag.Key, err)
+		return ldreason.NewEvaluationDetailForError(ldreason.EvalErrorMalformedFlag, ldvalue.Null()), nil
+	}
+	provider := &dryRunDataProvider{
+		DataProvider: ldstoreimpl.NewDataStoreEvaluatorDataProvider(client.dataSystem.Store(), client.loggers),
+		flag:         &flagCopy,
+	}
+	if len(segmentOverrides) != 0 {
+		provider.segments = make(map[string]*ldmodel.Segment, len(segmentOverrides))
+		for _, segment := range segmentOverrides {
+			segmentCopy, err := copySegmentDefinition(serialization, segment)
+			if err != nil {
+				client.loggers.Warnf("EvaluateFlagDefinition: invalid segment %q: %s", segment.Key, err)
+				return ldreason.NewEvaluationDetailForError(ldreason.EvalErrorMalformedFlag, ldvalue.Null()), nil
+			}
+			provider.segments[segmentCopy.Key] = &segmentCopy
+		}
+	}
+
+	evaluator := client.newEvaluator(provider)
+	return evaluator.Evaluate(provider.flag, context, nil).Detail, nil
+}
+
+func copyFlagDefinition(
+	serialization ldmodel.DataModelSerialization,
+	flag ldmodel.FeatureFlag,
+) (ldmodel.FeatureFlag, error) {
+	data, err := serialization.MarshalFeatureFlag(flag)
+	if err != nil {
+		return ldmodel.FeatureFlag{}, err
+	}
+	return serialization.UnmarshalFeatureFlag(data)
+}
+
+func copySegmentDefinition(
+	serialization ldmodel.DataModelSerialization,
+	segment ldmodel.Segment,
+) (ldmodel.Segment, error) {
+	data, err := serialization.MarshalSegment(segment)
+	if err != nil {
+		return ldmodel.Segment{}, err
+	}
+	return serialization.UnmarshalSegment(data)
+}
+
+// dryRunDataProvider is the ldeval.DataProvider for EvaluateFlagDefinition. It returns the flag being
+// evaluated and the segment overrides in place of the stored ones.
+type dryRunDataProvider struct {
+	ldeval.DataProvider
+	flag     *ldmodel.FeatureFlag
+	segments map[string]*ldmodel.Segment
+}
+
+func (p *dryRunDataProvider) GetFeatureFlag(key string) *ldmodel.FeatureFlag {
+	if key == p.flag.Key {
+		return p.flag
+	}
+	return p.DataProvider.GetFeatureFlag(key)
+}
+
+func (p *dryRunDataProvider) GetSegment(key string) *ldmodel.Segment {
+	if segment, ok := p.segments[key]; ok {
+		return segment
+	}
+	return p.DataProvider.GetSegment(key)
+}
@@ -0,0 +1,129 @@
+package ldclient
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withDryRunTestClient(
+	t *testing.T,
+	action func(*LDClient, *ldtestdata.TestDataSource, *mocks.CapturingEventProcessor),
+) {
+	testData := ldtestdata.DataSource()
+	events := &mocks.CapturingEventProcessor{}
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = testData
+		c.Events = mocks.SingleComponentConfigurer[ldevents.EventProcessor]{Instance: events}
+	})
+	defer client.Close()
+	action(client, testData, events)
+}
+
+func makeDryRunFlag() ldmodel.FeatureFlag {
+	return ldbuilders.NewFlagBuilder(evalFlagKey).Version(2).On(true).
+		Variations(ldvalue.String("a"), ldvalue.String("b")).FallthroughVariation(0).
+		AddRule(ldbuilders.NewRuleBuilder().ID("rule1").Variation(1).
+			Clauses(ldbuilders.SegmentMatchClause("segment1"))).
+		Build()
+}
+
+func TestEvaluateFlagDefinition(t *testing.T) {
+	t.Run("uses stored segments", func(t *testing.T) {
+		withDryRunTestClient(t, func(client *LDClient, testData *ldtestdata.TestDataSource,
+			_ *mocks.CapturingEventProcessor) {
+			testData.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segment1").
+				Included(evalTestUser.Key()).Build())
+
+			detail, err := client.EvaluateFlagDefinition(makeDryRunFlag(), evalTestUser)
+			require.NoError(t, err)
+			assert.Equal(t, ldvalue.String("b"), detail.Value)
+			assert.Equal(t, ldreason.NewEvalReasonRuleMatch(0, "rule1"), detail.Reason)
+		})
+	})
+
+	t.Run("segment overrides replace stored segments", func(t *testing.T) {
+		withDryRunTestClient(t, func(client *LDClient, testData *ldtestdata.TestDataSource,
+			_ *mocks.CapturingEventProcessor) {
+			testData.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segment1").
+				Included(evalTestUser.Key()).Build())
+
+			detail, err := client.EvaluateFlagDefinition(makeDryRunFlag(), evalTestUser,
+				ldbuilders.NewSegmentBuilder("segment1").Build())
+			require.NoError(t, err)
+			assert.Equal(t, ldvalue.String("a"), detail.Value)
+			assert.Equal(t, ldreason.NewEvalReasonFallthrough(), detail.Reason)
+
+			// The stored segment is unchanged.
+			value, _ := client.StringVariation(evalFlagKey, evalTestUser, "default")
+			assert.Equal(t, "default", value)
+			detail, err = client.EvaluateFlagDefinition(makeDryRunFlag(), evalTestUser)
+			require.NoError(t, err)
+			assert.Equal(t, ldvalue.String("b"), detail.Value)
+		})
+	})
+
+	t.Run("uses stored prerequisites", func(t *testing.T) {
+		withDryRunTestClient(t, func(client *LDClient, testData *ldtestdata.TestDataSource,
+			_ *mocks.CapturingEventProcessor) {
+			testData.Update(testData.Flag("prereq").VariationForAll(false))
+			flag := ldbuilders.NewFlagBuilder(evalFlagKey).On(true).
+				Variations(ldvalue.String("off"), ldvalue.String("on")).OffVariation(0).FallthroughVariation(1).
+				AddPrerequisite("prereq", 0).Build()
+
+			detail, err := client.EvaluateFlagDefinition(flag, evalTestUser)
+			require.NoError(t, err)
+			assert.Equal(t, ldvalue.String("off"), detail.Value)
+			assert.Equal(t, ldreason.NewEvalReasonPrerequisiteFailed("prereq"), detail.Reason)
+		})
+	})
+
+	t.Run("does not store the flag or send events", func(t *testing.T) {
+		withDryRunTestClient(t, func(client *LDClient, _ *ldtestdata.TestDataSource, events *mocks.CapturingEventProcessor) {
+			_, err := client.EvaluateFlagDefinition(makeDryRunFlag(), evalTestUser)
+			require.NoError(t, err)
+
+			assert.Len(t, events.Events, 0)
+			state := client.AllFlagsState(evalTestUser)
+			_, found := state.GetFlag(evalFlagKey)
+			assert.False(t, found)
+		})
+	})
+
+	t.Run("malformed flag", func(t *testing.T) {
+		withDryRunTestClient(t, func(client *LDClient, _ *ldtestdata.TestDataSource, _ *mocks.CapturingEventProcessor) {
+			flag := ldbuilders.NewFlagBuilder(evalFlagKey).On(true).FallthroughVariation(5).Build()
+
+			detail, err := client.EvaluateFlagDefinition(flag, evalTestUser)
+			require.NoError(t, err)
+			assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorMalformedFlag), detail.Reason)
+		})
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		withDryRunTestClient(t, func(client *LDClient, _ *ldtestdata.TestDataSource, _ *mocks.CapturingEventProcessor) {
+			detail, err := client.EvaluateFlagDefinition(makeDryRunFlag(), ldcontext.New(""))
+			assert.Error(t, err)
+			assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorUserNotSpecified), detail.Reason)
+		})
+	})
+
+	t.Run("offline client", func(t *testing.T) {
+		client, _ := MakeCustomClient(testSdkKey, Config{Offline: true}, 0)
+		defer client.Close()
+
+		detail, err := client.EvaluateFlagDefinition(makeDryRunFlag(), evalTestUser)
+		assert.Equal(t, ErrClientNotInitialized, err)
+		assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorClientNotReady), detail.Reason)
+	})
+}
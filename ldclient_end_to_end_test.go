@@ -198,6 +198,71 @@ func TestClientStartsInPollingMode(t *testing.T) {
 	})
 }
 
+func TestClientStartsWithFDv2Streaming(t *testing.T) {
+	data := ldservices.NewServerSDKData().Flags(&alwaysTrueFlag)
+	streamHandler, stream := ldservices.ServerSideFDv2StreamingServiceHandler(
+		ldservices.FDv2Events(data.ToFDv2Events("state1", 1)...))
+	defer stream.Close()
+	httphelpers.WithServer(streamHandler, func(streamServer *httptest.Server) {
+		logCapture := ldlogtest.NewMockLog()
+		defer logCapture.DumpIfTestFailed(t)
+
+		config := Config{
+			DataSystem:       ldcomponents.DataSystem(),
+			Events:           ldcomponents.NoEvents(),
+			Logging:          ldcomponents.Logging().Loggers(logCapture.Loggers),
+			ServiceEndpoints: interfaces.ServiceEndpoints{Streaming: streamServer.URL},
+		}
+
+		client, err := MakeCustomClient(testSdkKey, config, time.Second*5)
+		require.NoError(t, err)
+		defer client.Close()
+
+		assert.Equal(t, string(interfaces.DataSourceStateValid), string(client.GetDataSourceStatusProvider().GetStatus().State))
+
+		value, _ := client.BoolVariation(alwaysTrueFlag.Key, testUser, false)
+		assert.True(t, value)
+
+		ldservices.SendFDv2Events(stream, ldservices.FDv2Changes("state2", 2,
+			ldservices.FDv2DeleteObject(ldservices.FDv2FlagKind, alwaysTrueFlag.Key, alwaysTrueFlag.Version+1))...)
+
+		require.Eventually(t, func() bool {
+			value, _ := client.BoolVariation(alwaysTrueFlag.Key, testUser, false)
+			return !value
+		}, time.Second, time.Millisecond*10)
+	})
+}
+
+func TestClientStartsWithFDv2Polling(t *testing.T) {
+	data := ldservices.NewServerSDKData().Flags(&alwaysTrueFlag)
+	pollHandler, requestsCh := httphelpers.RecordingHandler(
+		ldservices.ServerSideFDv2PollingServiceHandler(ldservices.FDv2Events(data.ToFDv2Events("state1", 1)...)))
+	httphelpers.WithServer(pollHandler, func(pollServer *httptest.Server) {
+		logCapture := ldlogtest.NewMockLog()
+		defer logCapture.DumpIfTestFailed(t)
+
+		config := Config{
+			DataSystem:       ldcomponents.DataSystem().Synchronizers(ldcomponents.PollingDataSourceV2(), nil),
+			Events:           ldcomponents.NoEvents(),
+			Logging:          ldcomponents.Logging().Loggers(logCapture.Loggers),
+			ServiceEndpoints: interfaces.ServiceEndpoints{Polling: pollServer.URL},
+		}
+
+		client, err := MakeCustomClient(testSdkKey, config, time.Second*5)
+		require.NoError(t, err)
+		defer client.Close()
+
+		assert.Equal(t, string(interfaces.DataSourceStateValid), string(client.GetDataSourceStatusProvider().GetStatus().State))
+
+		value, _ := client.BoolVariation(alwaysTrueFlag.Key, testUser, false)
+		assert.True(t, value)
+
+		r := <-requestsCh
+		assert.Equal(t, testSdkKey, r.Request.Header.Get("Authorization"))
+		assert.Empty(t, r.Request.URL.Query().Get(ldservices.FDv2BasisParam))
+	})
+}
+
 func TestClientFailsToStartInPollingModeWith401Error(t *testing.T) {
 	handler, requestsCh := httphelpers.RecordingHandler(httphelpers.HandlerWithStatus(401))
 	httphelpers.WithServer(handler, func(pollServer *httptest.Server) {
@@ -0,0 +1,209 @@
+package ldclient
+
+import (
+	gocontext "context"
+	"errors"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-sdk-common/v3/ldlogtest"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datastore"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeAllFlagsDetailTestFlags() []ldmodel.FeatureFlag {
+	return []ldmodel.FeatureFlag{
+		ldbuilders.NewFlagBuilder("app-flag1").Version(100).On(true).FallthroughVariation(1).
+			Variations(ldvalue.String("x"), ldvalue.String("value1")).Build(),
+		ldbuilders.NewFlagBuilder("app-flag2").Version(200).On(false).OffVariation(0).
+			Variations(ldvalue.String("value2")).ClientSideUsingEnvironmentID(true).Build(),
+		ldbuilders.NewFlagBuilder("other-flag").Version(300).On(false).
+			Variations(ldvalue.String("value3")).ClientSideUsingEnvironmentID(true).Build(),
+	}
+}
+
+func TestAllFlagsDetailEvaluatesAllFlags(t *testing.T) {
+	withClientEvalTestParams(func(p clientEvalTestParams) {
+		for _, flag := range makeAllFlagsDetailTestFlags() {
+			p.data.UsePreconfiguredFlag(flag)
+		}
+
+		details := p.client.AllFlagsDetail(evalTestUser)
+
+		assert.Equal(t, map[string]ldreason.EvaluationDetail{
+			"app-flag1":  ldreason.NewEvaluationDetail(ldvalue.String("value1"), 1, ldreason.NewEvalReasonFallthrough()),
+			"app-flag2":  ldreason.NewEvaluationDetail(ldvalue.String("value2"), 0, ldreason.NewEvalReasonOff()),
+			"other-flag": {Value: ldvalue.Null(), Reason: ldreason.NewEvalReasonOff()},
+		}, details)
+		assert.Len(t, p.events.Events, 0)
+	})
+}
+
+func TestAllFlagsDetailCanFilterFlags(t *testing.T) {
+	withClientEvalTestParams(func(p clientEvalTestParams) {
+		for _, flag := range makeAllFlagsDetailTestFlags() {
+			p.data.UsePreconfiguredFlag(flag)
+		}
+		clientSide := AllFlagsDetailFilter(func(flag *ldmodel.FeatureFlag) bool {
+			return flag.ClientSideAvailability.UsingEnvironmentID
+		})
+
+		t.Run("keys", func(t *testing.T) {
+			details := p.client.AllFlagsDetail(evalTestUser, AllFlagsDetailKeys("app-flag1", "unknown-flag"))
+			assert.Equal(t, map[string]ldreason.EvaluationDetail{
+				"app-flag1":    ldreason.NewEvaluationDetail(ldvalue.String("value1"), 1, ldreason.NewEvalReasonFallthrough()),
+				"unknown-flag": ldreason.NewEvaluationDetailForError(ldreason.EvalErrorFlagNotFound, ldvalue.Null()),
+			}, details)
+		})
+
+		t.Run("key prefix", func(t *testing.T) {
+			details := p.client.AllFlagsDetail(evalTestUser, AllFlagsDetailKeyPrefix("app-"))
+			assert.ElementsMatch(t, []string{"app-flag1", "app-flag2"}, keysOf(details))
+		})
+
+		t.Run("predicate", func(t *testing.T) {
+			details := p.client.AllFlagsDetail(evalTestUser, clientSide)
+			assert.ElementsMatch(t, []string{"app-flag2", "other-flag"}, keysOf(details))
+		})
+
+		t.Run("filters are combined", func(t *testing.T) {
+			details := p.client.AllFlagsDetail(evalTestUser, AllFlagsDetailKeyPrefix("app-"), clientSide)
+			assert.ElementsMatch(t, []string{"app-flag2"}, keysOf(details))
+		})
+	})
+}
+
+func TestAllFlagsDetailCanGenerateEvents(t *testing.T) {
+	flag := ldbuilders.NewFlagBuilder(evalFlagKey).Version(expectedFlagVersion).On(true).
+		FallthroughVariation(expectedVariationForSingleValueFlag).
+		Variations(ldvalue.String("a"), ldvalue.String("b"), fallthroughValue).Build()
+
+	withClientEvalTestParams(func(p clientEvalTestParams) {
+		p.data.UsePreconfiguredFlag(flag)
+
+		details := p.client.AllFlagsDetail(evalTestUser, AllFlagsDetailWithEvents(),
+			AllFlagsDetailKeys(evalFlagKey, "unknown-flag"))
+		require.Len(t, details, 2)
+
+		require.Len(t, p.events.Events, 2)
+		var flagEvent, unknownFlagEvent ldevents.EvaluationData
+		for _, e := range p.events.Events {
+			if e.(ldevents.EvaluationData).Key == evalFlagKey {
+				flagEvent = e.(ldevents.EvaluationData)
+			} else {
+				unknownFlagEvent = e.(ldevents.EvaluationData)
+			}
+		}
+		assertEvalEvent(t, flagEvent, evalFlagKey, expectedFlagVersion, evalTestUser, fallthroughValue,
+			expectedVariationForSingleValueFlag, ldvalue.Null(), ldreason.NewEvalReasonFallthrough())
+		assert.Equal(t, "unknown-flag", unknownFlagEvent.Key)
+		assert.Equal(t, ldvalue.Null(), unknownFlagEvent.Value)
+	})
+}
+
+func TestAllFlagsDetailCanRunHooks(t *testing.T) {
+	flag := ldbuilders.NewFlagBuilder(evalFlagKey).On(false).OffVariation(0).Variations(offValue).Build()
+	store := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+	_ = store.Init(nil)
+	_, _ = store.Upsert(datakinds.Features, flag.Key, sharedtest.FlagDescriptor(flag))
+	testGoContext := gocontext.WithValue(gocontext.TODO(), "test-key", "test-value")
+
+	for _, withHooks := range []bool{false, true} {
+		hook := sharedtest.NewTestHook("test-hook")
+		client := makeTestClientWithConfig(func(c *Config) {
+			c.DataSource = mocks.DataSourceThatIsAlwaysInitialized()
+			c.DataStore = mocks.SingleComponentConfigurer[subsystems.DataStore]{Instance: store}
+			c.Hooks = []ldhooks.Hook{hook}
+		})
+
+		var options []AllFlagsDetailOption
+		if withHooks {
+			options = append(options, AllFlagsDetailWithHooks())
+		}
+		_ = client.AllFlagsDetailCtx(testGoContext, evalTestUser, options...)
+		client.Close()
+
+		if !withHooks {
+			hook.VerifyNoCalls(t)
+			continue
+		}
+		seriesContext := ldhooks.NewEvaluationSeriesContext(evalFlagKey, evalTestUser, ldvalue.Null(),
+			"LDClient.AllFlagsDetailCtx")
+		hook.Verify(t,
+			sharedtest.HookExpectedCall{
+				HookStage: sharedtest.HookStageBeforeEvaluation,
+				EvalCapture: sharedtest.HookEvalCapture{
+					EvaluationSeriesContext: seriesContext,
+					EvaluationSeriesData:    ldhooks.EmptyEvaluationSeriesData(),
+					GoContext:               testGoContext,
+				},
+			},
+			sharedtest.HookExpectedCall{
+				HookStage: sharedtest.HookStageAfterEvaluation,
+				EvalCapture: sharedtest.HookEvalCapture{
+					EvaluationSeriesContext: seriesContext,
+					EvaluationSeriesData:    ldhooks.EmptyEvaluationSeriesData(),
+					Detail:                  ldreason.NewEvaluationDetail(offValue, 0, ldreason.NewEvalReasonOff()),
+					GoContext:               testGoContext,
+				},
+			},
+		)
+	}
+}
+
+func TestAllFlagsDetailReturnsErrorsForRequestedKeysIfClientAndStoreAreNotInitialized(t *testing.T) {
+	mockLoggers := ldlogtest.NewMockLog()
+
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = mocks.DataSourceThatNeverInitializes()
+		c.Logging = ldcomponents.Logging().Loggers(mockLoggers.Loggers)
+	})
+	defer client.Close()
+
+	assert.Len(t, client.AllFlagsDetail(evalTestUser), 0)
+	assert.Equal(t, map[string]ldreason.EvaluationDetail{
+		evalFlagKey: ldreason.NewEvaluationDetailForError(ldreason.EvalErrorClientNotReady, ldvalue.Null()),
+	}, client.AllFlagsDetail(evalTestUser, AllFlagsDetailKeys(evalFlagKey)))
+	assert.Contains(t, mockLoggers.GetOutput(ldlog.Warn)[0], "Called AllFlagsDetail before client initialization")
+}
+
+func TestAllFlagsDetailReturnsErrorsForRequestedKeysIfStoreReturnsError(t *testing.T) {
+	store := mocks.NewCapturingDataStore(datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers()))
+	_ = store.Init(nil)
+	store.SetFakeError(errors.New("sorry"))
+	mockLoggers := ldlogtest.NewMockLog()
+
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = mocks.DataSourceThatIsAlwaysInitialized()
+		c.DataStore = mocks.SingleComponentConfigurer[subsystems.DataStore]{Instance: store}
+		c.Logging = ldcomponents.Logging().Loggers(mockLoggers.Loggers)
+	})
+	defer client.Close()
+
+	assert.Len(t, client.AllFlagsDetail(evalTestUser), 0)
+	assert.Equal(t, map[string]ldreason.EvaluationDetail{
+		evalFlagKey: ldreason.NewEvaluationDetailForError(ldreason.EvalErrorException, ldvalue.Null()),
+	}, client.AllFlagsDetail(evalTestUser, AllFlagsDetailKeys(evalFlagKey)))
+	assert.Contains(t, mockLoggers.GetOutput(ldlog.Warn)[0], "Unable to fetch flags")
+}
+
+func keysOf(details map[string]ldreason.EvaluationDetail) []string {
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+	return keys
+}
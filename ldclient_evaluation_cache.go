@@ -0,0 +1,145 @@
+package ldclient
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldeval "github.com/launchdarkly/go-server-sdk-evaluation/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// evaluationCacheManager connects a subsystems.EvaluationCache to the client: it evaluates flags through the
+// cache, and invalidates cached results when it receives flag change events.
+type evaluationCacheManager struct {
+	// generation is incremented whenever cached results are invalidated. It is first in the struct so that it
+	// is 64-bit aligned for atomic operations on 32-bit platforms.
+	generation uint64
+	cache      subsystems.EvaluationCache
+}
+
+func newEvaluationCacheManager(
+	cache subsystems.EvaluationCache,
+	flagChangeEventBroadcaster *internal.Broadcaster[interfaces.FlagChangeEvent],
+) *evaluationCacheManager {
+	m := &evaluationCacheManager{cache: cache}
+	// This uses a channel-based listener, rather than one of the non-blocking ones, because an invalidation
+	// must never be discarded. The cache operations are fast, so it will not hold up the SDK.
+	go m.invalidateOnFlagChanges(flagChangeEventBroadcaster.AddListener())
+	return m
+}
+
+// invalidateOnFlagChanges runs until the channel is closed, which happens when the client is closed. Flag
+// change events are sent for flags that depend on a changed prerequisite or segment, as well as for the
+// changed flag itself, so this is all that is needed to keep the cache consistent with the flag data.
+func (m *evaluationCacheManager) invalidateOnFlagChanges(ch <-chan interfaces.FlagChangeEvent) {
+	for event := range ch {
+		m.invalidate(event.Key)
+	}
+}
+
+func (m *evaluationCacheManager) invalidate(flagKey string) {
+	atomic.AddUint64(&m.generation, 1)
+	m.cache.InvalidateFlag(flagKey)
+}
+
+// evaluate returns the cached result for the flag and context if there is one, or else evaluates the flag
+// and caches the result. In either case, the recorder (if not nil) is called for every prerequisite flag
+// evaluation, just as it would be by the evaluator.
+func (m *evaluationCacheManager) evaluate(
+	evaluator ldeval.Evaluator,
+	flag *ldmodel.FeatureFlag,
+	context ldcontext.Context,
+	recorder ldeval.PrerequisiteFlagEventRecorder,
+) ldeval.Result {
+	key := subsystems.EvaluationCacheKey{
+		FlagKey:            flag.Key,
+		FlagVersion:        flag.Version,
+		ContextFingerprint: contextFingerprint(context),
+	}
+	if entry, ok := m.cache.Get(key); ok {
+		if recorder != nil {
+			for _, event := range entry.PrerequisiteEvents {
+				event.Context = context
+				recorder(event)
+			}
+		}
+		return entry.Result
+	}
+
+	// If the data changes while the flag is being evaluated, the result might be based on the old data and
+	// could still be cached after the invalidation, so in that case it is not cached. The data can also change
+	// after the generation is checked but before the result is stored, in which case the invalidation may
+	// already have happened, so the generation is checked again afterward and the result removed if needed.
+	generation := atomic.LoadUint64(&m.generation)
+	var prerequisiteEvents []ldeval.PrerequisiteFlagEvent
+	result := evaluator.Evaluate(flag, context, func(event ldeval.PrerequisiteFlagEvent) {
+		prerequisiteEvents = append(prerequisiteEvents, event)
+		if recorder != nil {
+			recorder(event)
+		}
+	})
+	// Big Segment membership is not part of the flag data, so there would be no way to know when the
+	// result is out of date. The evaluator sets the Big Segments status in the reason if any Big Segment was
+	// referenced, including by a prerequisite.
+	if result.Detail.Reason.GetBigSegmentsStatus() == "" && atomic.LoadUint64(&m.generation) == generation {
+		m.cache.Set(key, subsystems.EvaluationCacheEntry{Result: result, PrerequisiteEvents: prerequisiteEvents})
+		if atomic.LoadUint64(&m.generation) != generation {
+			m.cache.InvalidateFlag(key.FlagKey)
+		}
+	}
+	return result
+}
+
+// contextFingerprint returns a string that is the same for any two contexts with the same kinds, keys, and
+// attributes. The JSON representation of a context can't be used for this, because its attributes and the
+// properties of object values are not written in any particular order.
+func contextFingerprint(context ldcontext.Context) string {
+	contexts := context.GetAllIndividualContexts(nil)
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].Kind() < contexts[j].Kind() })
+	var b strings.Builder
+	for _, c := range contexts {
+		b.WriteString(strconv.Quote(string(c.Kind())))
+		b.WriteString(strconv.Quote(c.Key()))
+		b.WriteString(strconv.FormatBool(c.Anonymous()))
+		names := c.GetOptionalAttributeNames(nil)
+		sort.Strings(names)
+		for _, name := range names {
+			b.WriteString(strconv.Quote(name))
+			writeValueFingerprint(&b, c.GetValue(name))
+		}
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+func writeValueFingerprint(b *strings.Builder, value ldvalue.Value) {
+	switch value.Type() {
+	case ldvalue.ArrayType:
+		b.WriteByte('[')
+		for i := 0; i < value.Count(); i++ {
+			writeValueFingerprint(b, value.GetByIndex(i))
+			b.WriteByte(',')
+		}
+		b.WriteByte(']')
+	case ldvalue.ObjectType:
+		keys := value.Keys(nil)
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for _, key := range keys {
+			b.WriteString(strconv.Quote(key))
+			b.WriteByte(':')
+			writeValueFingerprint(b, value.GetByKey(key))
+			b.WriteByte(',')
+		}
+		b.WriteByte('}')
+	default:
+		b.WriteString(value.JSONString())
+	}
+}
@@ -0,0 +1,232 @@
+package ldclient
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEvaluationCache wraps the in-memory cache to count how often it is used.
+type recordingEvaluationCache struct {
+	subsystems.EvaluationCache
+	hits      int
+	sets      int
+	beforeSet func(subsystems.EvaluationCacheKey)
+	lock      sync.Mutex
+}
+
+func (c *recordingEvaluationCache) Get(key subsystems.EvaluationCacheKey) (subsystems.EvaluationCacheEntry, bool) {
+	entry, ok := c.EvaluationCache.Get(key)
+	if ok {
+		c.lock.Lock()
+		c.hits++
+		c.lock.Unlock()
+	}
+	return entry, ok
+}
+
+func (c *recordingEvaluationCache) Set(key subsystems.EvaluationCacheKey, entry subsystems.EvaluationCacheEntry) {
+	c.lock.Lock()
+	c.sets++
+	beforeSet := c.beforeSet
+	c.lock.Unlock()
+	if beforeSet != nil {
+		beforeSet(key)
+	}
+	c.EvaluationCache.Set(key, entry)
+}
+
+func (c *recordingEvaluationCache) counts() (int, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.hits, c.sets
+}
+
+func withEvaluationCacheTestClient(
+	t *testing.T,
+	modConfig func(*Config),
+	action func(*LDClient, *ldtestdata.TestDataSource, *recordingEvaluationCache, *mocks.CapturingEventProcessor),
+) {
+	inMemoryCache, err := ldcomponents.InMemoryEvaluationCache().Build(nil)
+	require.NoError(t, err)
+	cache := &recordingEvaluationCache{EvaluationCache: inMemoryCache}
+	testData := ldtestdata.DataSource()
+	events := &mocks.CapturingEventProcessor{}
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = testData
+		c.Events = mocks.SingleComponentConfigurer[ldevents.EventProcessor]{Instance: events}
+		c.EvaluationCache = mocks.SingleComponentConfigurer[subsystems.EvaluationCache]{Instance: cache}
+		if modConfig != nil {
+			modConfig(c)
+		}
+	})
+	defer client.Close()
+	action(client, testData, cache, events)
+}
+
+func TestEvaluationCache(t *testing.T) {
+	t.Run("reuses result and still generates events", func(t *testing.T) {
+		withEvaluationCacheTestClient(t, nil,
+			func(client *LDClient, testData *ldtestdata.TestDataSource, cache *recordingEvaluationCache,
+				events *mocks.CapturingEventProcessor) {
+				testData.Update(testData.Flag("prereq").VariationForAll(true))
+				testData.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(true).
+					Variations(ldvalue.String("off"), ldvalue.String("on")).OffVariation(0).FallthroughVariation(1).
+					AddPrerequisite("prereq", 0).Build())
+
+				for i := 0; i < 2; i++ {
+					value, err := client.StringVariation(evalFlagKey, evalTestUser, "default")
+					require.NoError(t, err)
+					assert.Equal(t, "on", value)
+				}
+
+				hits, sets := cache.counts()
+				assert.Equal(t, 1, hits)
+				assert.Equal(t, 1, sets)
+				// Each evaluation produces an event for the prerequisite and one for the flag itself.
+				assert.Len(t, events.Events, 4)
+			})
+	})
+
+	t.Run("reuses result for context with several attributes", func(t *testing.T) {
+		withEvaluationCacheTestClient(t, nil,
+			func(client *LDClient, testData *ldtestdata.TestDataSource, cache *recordingEvaluationCache,
+				_ *mocks.CapturingEventProcessor) {
+				testData.Update(testData.Flag(evalFlagKey).VariationForAll(true))
+				context := ldcontext.NewBuilder("user-key").Name("name").
+					SetString("a", "1").SetString("b", "2").SetString("c", "3").SetString("d", "4").
+					SetValue("e", ldvalue.ObjectBuild().Set("x", ldvalue.Int(1)).Set("y", ldvalue.Int(2)).Build()).
+					Build()
+
+				for i := 0; i < 20; i++ {
+					value, err := client.BoolVariation(evalFlagKey, context, false)
+					require.NoError(t, err)
+					assert.True(t, value)
+				}
+
+				hits, sets := cache.counts()
+				assert.Equal(t, 19, hits)
+				assert.Equal(t, 1, sets)
+			})
+	})
+
+	t.Run("default value from each call is used with a cached result", func(t *testing.T) {
+		withEvaluationCacheTestClient(t, nil,
+			func(client *LDClient, testData *ldtestdata.TestDataSource, cache *recordingEvaluationCache,
+				_ *mocks.CapturingEventProcessor) {
+				testData.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(false).Build())
+
+				value, _ := client.StringVariation(evalFlagKey, evalTestUser, "a")
+				assert.Equal(t, "a", value)
+				value, _ = client.StringVariation(evalFlagKey, evalTestUser, "b")
+				assert.Equal(t, "b", value)
+				hits, _ := cache.counts()
+				assert.Equal(t, 1, hits)
+			})
+	})
+
+	t.Run("result is not kept if data changes just before it is cached", func(t *testing.T) {
+		withEvaluationCacheTestClient(t, nil,
+			func(client *LDClient, testData *ldtestdata.TestDataSource, cache *recordingEvaluationCache,
+				_ *mocks.CapturingEventProcessor) {
+				testData.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(false).Build())
+
+				// This simulates a flag change event being processed after the evaluation has checked that the
+				// data has not changed, but before it has stored the result.
+				var once sync.Once
+				cache.lock.Lock()
+				cache.beforeSet = func(key subsystems.EvaluationCacheKey) {
+					once.Do(func() { client.evaluationCache.invalidate(key.FlagKey) })
+				}
+				cache.lock.Unlock()
+
+				_, _ = client.BoolVariation(evalFlagKey, evalTestUser, false)
+				_, _ = client.BoolVariation(evalFlagKey, evalTestUser, false)
+
+				hits, sets := cache.counts()
+				assert.Equal(t, 0, hits)
+				assert.Equal(t, 2, sets)
+			})
+	})
+
+	t.Run("result is invalidated when a segment changes", func(t *testing.T) {
+		withEvaluationCacheTestClient(t, nil,
+			func(client *LDClient, testData *ldtestdata.TestDataSource, _ *recordingEvaluationCache,
+				_ *mocks.CapturingEventProcessor) {
+				testData.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segment1").Version(1).Build())
+				testData.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(true).
+					Variations(ldvalue.Bool(false), ldvalue.Bool(true)).FallthroughVariation(0).
+					AddRule(ldbuilders.NewRuleBuilder().Variation(1).
+						Clauses(ldbuilders.SegmentMatchClause("segment1"))).
+					Build())
+
+				value, _ := client.BoolVariation(evalFlagKey, evalTestUser, false)
+				assert.False(t, value)
+
+				testData.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segment1").Version(2).
+					Included(evalTestUser.Key()).Build())
+
+				assert.Eventually(t, func() bool {
+					value, _ := client.BoolVariation(evalFlagKey, evalTestUser, false)
+					return value
+				}, time.Second, time.Millisecond*10)
+			})
+	})
+
+	t.Run("results that use Big Segments are not cached", func(t *testing.T) {
+		bsStore := &mocks.MockBigSegmentStore{}
+		bsStore.TestSetMetadataToCurrentTime()
+		withEvaluationCacheTestClient(t,
+			func(c *Config) {
+				c.BigSegments = ldcomponents.BigSegments(
+					mocks.SingleComponentConfigurer[subsystems.BigSegmentStore]{Instance: bsStore},
+				)
+			},
+			func(client *LDClient, testData *ldtestdata.TestDataSource, cache *recordingEvaluationCache,
+				_ *mocks.CapturingEventProcessor) {
+				addBigSegmentAndFlag(testData)
+
+				for i := 0; i < 2; i++ {
+					_, _ = client.BoolVariation(evalFlagKey, evalTestUser, false)
+				}
+
+				hits, sets := cache.counts()
+				assert.Equal(t, 0, hits)
+				assert.Equal(t, 0, sets)
+			})
+	})
+}
+
+func TestContextFingerprint(t *testing.T) {
+	object1 := ldvalue.ObjectBuild().Set("x", ldvalue.Int(1)).Set("y", ldvalue.String("2")).Build()
+	object2 := ldvalue.ObjectBuild().Set("y", ldvalue.String("2")).Set("x", ldvalue.Int(1)).Build()
+	user1 := ldcontext.NewBuilder("key").Name("name").SetString("a", "1").SetValue("b", object1).Build()
+	user2 := ldcontext.NewBuilder("key").SetValue("b", object2).SetString("a", "1").Name("name").Build()
+	org := ldcontext.NewBuilder("org-key").Kind("org").SetBool("c", true).Build()
+
+	assert.Equal(t, contextFingerprint(user1), contextFingerprint(user2))
+	assert.Equal(t, contextFingerprint(ldcontext.NewMulti(user1, org)), contextFingerprint(ldcontext.NewMulti(org, user2)))
+
+	for _, different := range []ldcontext.Context{
+		ldcontext.NewBuilder("key").Name("name").SetString("a", "1").Build(),
+		ldcontext.NewBuilder("key").Name("name").SetString("a", "1").SetValue("b", ldvalue.String("2")).Build(),
+		ldcontext.NewBuilder("key").Name("name").SetString("a", "2").SetValue("b", object1).Build(),
+		ldcontext.NewBuilder("key").Kind("org").Name("name").SetString("a", "1").SetValue("b", object1).Build(),
+		ldcontext.NewBuilder("key").Anonymous(true).Name("name").SetString("a", "1").SetValue("b", object1).Build(),
+		ldcontext.NewMulti(user1, org),
+	} {
+		assert.NotEqual(t, contextFingerprint(user1), contextFingerprint(different), different.String())
+	}
+}
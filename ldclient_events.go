@@ -106,15 +106,15 @@ func (c *clientEventsDisabledDecorator) BoolVariation(
 	context ldcontext.Context,
 	defaultVal bool,
 ) (bool, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Bool(defaultVal),
-		true, c.scope, boolVarFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.Bool(defaultVal), true, nil, c.scope, boolVarFuncName)
 	return detail.Value.BoolValue(), err
 }
 
 func (c *clientEventsDisabledDecorator) BoolVariationDetail(key string, context ldcontext.Context, defaultVal bool) (
 	bool, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Bool(defaultVal),
-		true, c.scope, boolVarDetailFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.Bool(defaultVal), true, nil, c.scope, boolVarDetailFuncName)
 	return detail.Value.BoolValue(), detail, err
 }
 
@@ -124,8 +124,8 @@ func (c *clientEventsDisabledDecorator) BoolVariationCtx(
 	context ldcontext.Context,
 	defaultVal bool,
 ) (bool, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.Bool(defaultVal),
-		true, c.scope, boolVarExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context, ldvalue.Bool(defaultVal),
+		true, nil, c.scope, boolVarExFuncName)
 	return detail.Value.BoolValue(), err
 }
 
@@ -136,8 +136,8 @@ func (c *clientEventsDisabledDecorator) BoolVariationDetailCtx(
 	defaultVal bool,
 ) (
 	bool, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.Bool(defaultVal),
-		true, c.scope, boolVarDetailExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context, ldvalue.Bool(defaultVal),
+		true, nil, c.scope, boolVarDetailExFuncName)
 	return detail.Value.BoolValue(), detail, err
 }
 
@@ -146,15 +146,15 @@ func (c *clientEventsDisabledDecorator) IntVariation(
 	context ldcontext.Context,
 	defaultVal int,
 ) (int, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Int(defaultVal),
-		true, c.scope, intVarFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.Int(defaultVal), true, nil, c.scope, intVarFuncName)
 	return detail.Value.IntValue(), err
 }
 
 func (c *clientEventsDisabledDecorator) IntVariationDetail(key string, context ldcontext.Context, defaultVal int) (
 	int, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Int(defaultVal),
-		true, c.scope, intVarDetailFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.Int(defaultVal), true, nil, c.scope, intVarDetailFuncName)
 	return detail.Value.IntValue(), detail, err
 }
 
@@ -164,8 +164,8 @@ func (c *clientEventsDisabledDecorator) IntVariationCtx(
 	context ldcontext.Context,
 	defaultVal int,
 ) (int, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.Int(defaultVal),
-		true, c.scope, intVarExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context, ldvalue.Int(defaultVal),
+		true, nil, c.scope, intVarExFuncName)
 	return detail.Value.IntValue(), err
 }
 
@@ -176,15 +176,15 @@ func (c *clientEventsDisabledDecorator) IntVariationDetailCtx(
 	defaultVal int,
 ) (
 	int, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.Int(defaultVal),
-		true, c.scope, intVarDetailExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context, ldvalue.Int(defaultVal),
+		true, nil, c.scope, intVarDetailExFuncName)
 	return detail.Value.IntValue(), detail, err
 }
 
 func (c *clientEventsDisabledDecorator) Float64Variation(key string, context ldcontext.Context, defaultVal float64) (
 	float64, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Float64(defaultVal),
-		true, c.scope, floatVarFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.Float64(defaultVal), true, nil, c.scope, floatVarFuncName)
 	return detail.Value.Float64Value(), err
 }
 
@@ -194,8 +194,8 @@ func (c *clientEventsDisabledDecorator) Float64VariationDetail(
 	defaultVal float64,
 ) (
 	float64, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.Float64(defaultVal),
-		true, c.scope, floatVarDetailFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.Float64(defaultVal), true, nil, c.scope, floatVarDetailFuncName)
 	return detail.Value.Float64Value(), detail, err
 }
 
@@ -206,8 +206,8 @@ func (c *clientEventsDisabledDecorator) Float64VariationCtx(
 	defaultVal float64,
 ) (
 	float64, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.Float64(defaultVal),
-		true, c.scope, floatVarExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context,
+		ldvalue.Float64(defaultVal), true, nil, c.scope, floatVarExFuncName)
 	return detail.Value.Float64Value(), err
 }
 
@@ -218,15 +218,15 @@ func (c *clientEventsDisabledDecorator) Float64VariationDetailCtx(
 	defaultVal float64,
 ) (
 	float64, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.Float64(defaultVal),
-		true, c.scope, floatVarDetailExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context,
+		ldvalue.Float64(defaultVal), true, nil, c.scope, floatVarDetailExFuncName)
 	return detail.Value.Float64Value(), detail, err
 }
 
 func (c *clientEventsDisabledDecorator) StringVariation(key string, context ldcontext.Context, defaultVal string) (
 	string, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.String(defaultVal),
-		true, c.scope, stringVarExFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.String(defaultVal), true, nil, c.scope, stringVarExFuncName)
 	return detail.Value.StringValue(), err
 }
 
@@ -236,8 +236,8 @@ func (c *clientEventsDisabledDecorator) StringVariationDetail(
 	defaultVal string,
 ) (
 	string, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), key, context, ldvalue.String(defaultVal),
-		true, c.scope, stringVarDetailFuncName)
+	detail, _, err := c.client.variationWithHooks(gocontext.TODO(), c.client.evaluateInternal, key, context,
+		ldvalue.String(defaultVal), true, nil, c.scope, stringVarDetailFuncName)
 	return detail.Value.StringValue(), detail, err
 }
 
@@ -248,8 +248,8 @@ func (c *clientEventsDisabledDecorator) StringVariationCtx(
 	defaultVal string,
 ) (
 	string, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.String(defaultVal), true, c.scope,
-		stringVarExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context, ldvalue.String(defaultVal),
+		true, nil, c.scope, stringVarExFuncName)
 	return detail.Value.StringValue(), err
 }
 
@@ -260,8 +260,8 @@ func (c *clientEventsDisabledDecorator) StringVariationDetailCtx(
 	defaultVal string,
 ) (
 	string, ldreason.EvaluationDetail, error) {
-	detail, _, err := c.client.variationWithHooks(ctx, key, context, ldvalue.String(defaultVal), true, c.scope,
-		stringVarDetailExFuncName)
+	detail, _, err := c.client.variationWithHooks(ctx, c.client.evaluateInternal, key, context, ldvalue.String(defaultVal),
+		true, nil, c.scope, stringVarDetailExFuncName)
 	return detail.Value.StringValue(), detail, err
 }
 
@@ -284,10 +284,12 @@ func (c *clientEventsDisabledDecorator) JSONVariation(key string, context ldcont
 	ldvalue.Value, error) {
 	detail, _, err := c.client.variationWithHooks(
 		gocontext.TODO(),
+		c.client.evaluateInternal,
 		key,
 		context,
 		defaultVal,
 		true,
+		nil,
 		c.scope,
 		jsonVarFuncName,
 	)
@@ -302,10 +304,12 @@ func (c *clientEventsDisabledDecorator) JSONVariationDetail(
 	ldvalue.Value, ldreason.EvaluationDetail, error) {
 	detail, _, err := c.client.variationWithHooks(
 		gocontext.TODO(),
+		c.client.evaluateInternal,
 		key,
 		context,
 		defaultVal,
 		true,
+		nil,
 		c.scope,
 		jsonVarDetailFuncName,
 	)
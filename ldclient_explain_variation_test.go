@@ -0,0 +1,203 @@
+package ldclient
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/bigsegments"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainVariation(t *testing.T) {
+	t.Run("rules, clauses, and segments", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segment1").
+				Included(evalTestUser.Key()).Build())
+			p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).Version(2).On(true).
+				Variations(ldvalue.String("a"), ldvalue.String("b"), ldvalue.String("c")).FallthroughVariation(0).
+				AddRule(ldbuilders.NewRuleBuilder().ID("rule0").Variation(1).Clauses(
+					ldbuilders.Clause(ldattr.KeyAttr, ldmodel.OperatorIn, ldvalue.String(evalTestUser.Key())),
+					ldbuilders.Clause("name", ldmodel.OperatorIn, ldvalue.String("Lucy")),
+				)).
+				AddRule(ldbuilders.NewRuleBuilder().ID("rule1").Variation(2).Clauses(
+					ldbuilders.SegmentMatchClause("segment1", "segment2"),
+				)).
+				AddRule(ldbuilders.NewRuleBuilder().ID("rule2").Variation(0).Clauses(
+					ldbuilders.Negate(ldbuilders.Clause(ldattr.KeyAttr, ldmodel.OperatorIn, ldvalue.String("x"))),
+				)).
+				Build())
+
+			explanation, err := p.client.ExplainVariation(evalFlagKey, evalTestUser, ldvalue.String("default"))
+			require.NoError(t, err)
+
+			assert.Equal(t, evalFlagKey, explanation.FlagKey)
+			assert.Equal(t, ldvalue.NewOptionalInt(2), explanation.FlagVersion)
+			assert.True(t, explanation.FlagOn)
+			assert.Equal(t, ldreason.NewEvaluationDetail(ldvalue.String("c"), 2,
+				ldreason.NewEvalReasonRuleMatch(1, "rule1")), explanation.Detail)
+			assert.Len(t, explanation.Prerequisites, 0)
+
+			require.Len(t, explanation.Rules, 3)
+
+			rule0 := explanation.Rules[0]
+			assert.Equal(t, 0, rule0.Index)
+			assert.Equal(t, "rule0", rule0.ID)
+			assert.True(t, rule0.Reached)
+			assert.False(t, rule0.Matched)
+			require.Len(t, rule0.Clauses, 2)
+			assert.Equal(t, ClauseExplanation{
+				ContextKind: ldcontext.DefaultKind,
+				Attribute:   ldattr.KeyAttr,
+				Op:          ldmodel.OperatorIn,
+				Values:      []ldvalue.Value{ldvalue.String(evalTestUser.Key())},
+				Matched:     true,
+			}, rule0.Clauses[0])
+			assert.False(t, rule0.Clauses[1].Matched)
+
+			rule1 := explanation.Rules[1]
+			assert.True(t, rule1.Reached)
+			assert.True(t, rule1.Matched)
+			require.Len(t, rule1.Clauses, 1)
+			assert.True(t, rule1.Clauses[0].Matched)
+			assert.Equal(t, []SegmentExplanation{
+				{Key: "segment1", Found: true, Version: 1, Matched: true},
+				{Key: "segment2"},
+			}, rule1.Clauses[0].Segments)
+
+			rule2 := explanation.Rules[2]
+			assert.False(t, rule2.Reached)
+			assert.True(t, rule2.Matched)
+			assert.True(t, rule2.Clauses[0].Negate)
+		})
+	})
+
+	t.Run("prerequisites", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.Update(p.data.Flag("prereq2").VariationForAll(false))
+			p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder("prereq1").Version(5).On(true).
+				Variations(ldvalue.Bool(false), ldvalue.Bool(true)).OffVariation(0).FallthroughVariation(1).
+				AddPrerequisite("prereq2", 1).Build())
+			p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(true).
+				Variations(ldvalue.String("off"), ldvalue.String("on")).OffVariation(0).FallthroughVariation(1).
+				AddPrerequisite("prereq1", 0).
+				AddRule(ldbuilders.NewRuleBuilder().ID("rule0").Variation(1).Clauses(
+					ldbuilders.Clause(ldattr.KeyAttr, ldmodel.OperatorIn, ldvalue.String(evalTestUser.Key())),
+				)).
+				Build())
+
+			explanation, err := p.client.ExplainVariation(evalFlagKey, evalTestUser, ldvalue.String("default"))
+			require.NoError(t, err)
+
+			assert.Equal(t, ldreason.NewEvaluationDetail(ldvalue.String("off"), 0,
+				ldreason.NewEvalReasonPrerequisiteFailed("prereq1")), explanation.Detail)
+			require.Len(t, explanation.Prerequisites, 2)
+
+			nested := explanation.Prerequisites[0]
+			assert.Equal(t, "prereq2", nested.FlagKey)
+			assert.Equal(t, "prereq1", nested.PrerequisiteOf)
+			assert.True(t, nested.FlagOn)
+			assert.Equal(t, 1, nested.RequiredVariation)
+			assert.Equal(t, ldvalue.NewOptionalInt(1), nested.Detail.VariationIndex)
+			assert.True(t, nested.Met)
+
+			direct := explanation.Prerequisites[1]
+			assert.Equal(t, "prereq1", direct.FlagKey)
+			assert.Equal(t, evalFlagKey, direct.PrerequisiteOf)
+			assert.Equal(t, 5, direct.FlagVersion)
+			assert.Equal(t, 0, direct.RequiredVariation)
+			assert.Equal(t, ldreason.NewEvalReasonFallthrough(), direct.Detail.Reason)
+			assert.False(t, direct.Met)
+
+			require.Len(t, explanation.Rules, 1)
+			assert.False(t, explanation.Rules[0].Reached)
+			assert.True(t, explanation.Rules[0].Matched)
+		})
+	})
+
+	t.Run("rules are reached after nested prerequisites when the result is an error", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.Update(p.data.Flag("prereq2").VariationForAll(true))
+			p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder("prereq1").On(true).
+				Variations(ldvalue.Bool(false), ldvalue.Bool(true)).OffVariation(0).FallthroughVariation(1).
+				AddPrerequisite("prereq2", 0).Build())
+			p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(true).
+				Variations(ldvalue.String("off"), ldvalue.String("on")).OffVariation(0).FallthroughVariation(1).
+				AddPrerequisite("prereq1", 1).
+				AddRule(ldbuilders.NewRuleBuilder().ID("rule0").Variation(5).Clauses(
+					ldbuilders.Clause(ldattr.KeyAttr, ldmodel.OperatorIn, ldvalue.String(evalTestUser.Key())),
+				)).
+				Build())
+
+			explanation, err := p.client.ExplainVariation(evalFlagKey, evalTestUser, ldvalue.String("default"))
+			require.NoError(t, err)
+
+			assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorMalformedFlag), explanation.Detail.Reason)
+			require.Len(t, explanation.Prerequisites, 2)
+			require.Len(t, explanation.Rules, 1)
+			assert.True(t, explanation.Rules[0].Reached)
+			assert.True(t, explanation.Rules[0].Matched)
+		})
+	})
+
+	t.Run("Big Segment status", func(t *testing.T) {
+		doBigSegmentsTest(t, func(client *LDClient, bsStore *mocks.MockBigSegmentStore) {
+			membership := ldstoreimpl.NewBigSegmentMembershipFromSegmentRefs(
+				[]string{makeBigSegmentRef(bigSegmentKey, 1)}, nil)
+			bsStore.TestSetMembership(bigsegments.HashForContextKey(evalTestUser.Key()), membership)
+
+			explanation, err := client.ExplainVariation(evalFlagKey, evalTestUser, ldvalue.Bool(false))
+			require.NoError(t, err)
+
+			assert.Equal(t, ldvalue.Bool(true), explanation.Detail.Value)
+			require.Len(t, explanation.Rules, 1)
+			assert.Equal(t, []SegmentExplanation{{
+				Key:               bigSegmentKey,
+				Found:             true,
+				Version:           1,
+				Unbounded:         true,
+				Matched:           true,
+				BigSegmentsStatus: ldreason.BigSegmentsHealthy,
+			}}, explanation.Rules[0].Clauses[0].Segments)
+		})
+	})
+
+	t.Run("does not generate events", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.Update(p.data.Flag("prereq").VariationForAll(true))
+			p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(true).
+				Variations(ldvalue.Bool(false), ldvalue.Bool(true)).FallthroughVariation(1).
+				AddPrerequisite("prereq", 0).Build())
+
+			_, err := p.client.ExplainVariation(evalFlagKey, evalTestUser, ldvalue.Bool(false))
+			require.NoError(t, err)
+			assert.Len(t, p.events.Events, 0)
+		})
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			explanation, err := p.client.ExplainVariation("unknown", evalTestUser, ldvalue.String("default"))
+			assert.Error(t, err)
+			assert.Equal(t, ldvalue.OptionalInt{}, explanation.FlagVersion)
+			assert.Equal(t, newEvaluationError(ldvalue.String("default"), ldreason.EvalErrorFlagNotFound),
+				explanation.Detail)
+		})
+	})
+
+	t.Run("invalid context", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			explanation, err := p.client.ExplainVariation(evalFlagKey, ldcontext.New(""), ldvalue.String("default"))
+			assert.Error(t, err)
+			assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorUserNotSpecified), explanation.Detail.Reason)
+		})
+	})
+}
@@ -1,7 +1,9 @@
 package ldclient
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
 
@@ -11,12 +13,15 @@ import (
 	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/datastore"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datasystem"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
 	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type clientExternalUpdatesTestParams struct {
@@ -75,3 +80,52 @@ func TestClientExternalUpdatesMode(t *testing.T) {
 		})
 	})
 }
+
+func TestClientExternalUpdatesModeWithPreloadedCache(t *testing.T) {
+	flag := ldbuilders.NewFlagBuilder("flagkey").SingleVariation(ldvalue.Bool(true)).Build()
+
+	makeClient := func(core *mocks.MockPersistentDataStore, waitFor time.Duration) (*LDClient, error) {
+		config := Config{
+			DataSource: ldcomponents.ExternalUpdatesOnly(),
+			DataStore: ldcomponents.PersistentDataStore(
+				mocks.SingleComponentConfigurer[subsystems.PersistentDataStore]{Instance: core},
+			).PreloadCache(true),
+			Logging: ldcomponents.Logging().Loggers(sharedtest.NewTestLoggers()),
+		}
+		return MakeCustomClient("sdk_key", config, waitFor)
+	}
+
+	t.Run("waits for cache to be loaded", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		require.NoError(t, core.Init([]ldstoretypes.SerializedCollection{{
+			Kind: ldstoreimpl.Features(),
+			Items: []ldstoretypes.KeyedSerializedItemDescriptor{{Key: flag.Key, Item: ldstoretypes.SerializedItemDescriptor{
+				Version:        flag.Version,
+				SerializedItem: ldstoreimpl.Features().Serialize(sharedtest.FlagDescriptor(flag)),
+			}}},
+		}}))
+
+		client, err := makeClient(core, time.Second)
+		require.NoError(t, err)
+		defer client.Close()
+
+		assert.True(t, client.Initialized())
+		assert.Equal(t, datasystem.Refreshed, client.dataSystem.DataAvailability())
+		require.NoError(t, core.Init(nil)) // evaluations should use the preloaded cache
+		value, err := client.BoolVariation(flag.Key, evalTestUser, false)
+		assert.NoError(t, err)
+		assert.True(t, value)
+	})
+
+	t.Run("is not fully initialized if store is unavailable", func(t *testing.T) {
+		core := mocks.NewMockPersistentDataStore()
+		core.SetAvailable(false)
+		core.SetFakeError(errors.New("sorry"))
+
+		client, err := makeClient(core, time.Millisecond*100)
+		assert.Equal(t, ErrInitializationTimeout, err)
+		defer client.Close()
+
+		assert.NotEqual(t, datasystem.Refreshed, client.dataSystem.DataAvailability())
+	})
+}
@@ -2,6 +2,7 @@ package ldclient
 
 import (
 	gocontext "context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -9,8 +10,14 @@ import (
 	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
 	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
 	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // The execution of hooks is mostly tested in hook_runner_test. The tests here are to test the usage of the hook runner
@@ -268,3 +275,208 @@ func TestHooksAreExecutedForAllVariationMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestTrackSeriesIsExecutedForAllTrackMethods(t *testing.T) {
+	testContext := ldcontext.New("test-context")
+	testGoContext := gocontext.WithValue(gocontext.TODO(), "test-key", "test-value")
+	data := ldvalue.String("data")
+
+	testCases := []struct {
+		call          func(client *LDClient)
+		seriesContext ldhooks.TrackSeriesContext
+		goContext     gocontext.Context
+	}{
+		{
+			func(client *LDClient) { _ = client.TrackEvent("event", testContext) },
+			ldhooks.NewTrackSeriesContext("event", testContext, ldvalue.Null(), "LDClient.TrackEvent"),
+			gocontext.TODO(),
+		},
+		{
+			func(client *LDClient) { _ = client.TrackEventCtx(testGoContext, "event", testContext) },
+			ldhooks.NewTrackSeriesContext("event", testContext, ldvalue.Null(), "LDClient.TrackEventCtx"),
+			testGoContext,
+		},
+		{
+			func(client *LDClient) { _ = client.TrackData("event", testContext, data) },
+			ldhooks.NewTrackSeriesContext("event", testContext, data, "LDClient.TrackData"),
+			gocontext.TODO(),
+		},
+		{
+			func(client *LDClient) { _ = client.TrackDataCtx(testGoContext, "event", testContext, data) },
+			ldhooks.NewTrackSeriesContext("event", testContext, data, "LDClient.TrackDataCtx"),
+			testGoContext,
+		},
+		{
+			func(client *LDClient) { _ = client.TrackMetric("event", testContext, 2.5, data) },
+			ldhooks.NewTrackSeriesContextWithMetric("event", testContext, data, 2.5, "LDClient.TrackMetric"),
+			gocontext.TODO(),
+		},
+		{
+			func(client *LDClient) { _ = client.TrackMetricCtx(testGoContext, "event", testContext, 2.5, data) },
+			ldhooks.NewTrackSeriesContextWithMetric("event", testContext, data, 2.5, "LDClient.TrackMetricCtx"),
+			testGoContext,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("for method %v", testCase.seriesContext.Method()), func(t *testing.T) {
+			hook := sharedtest.NewSeriesTestHook("test-hook")
+			events := &mocks.CapturingEventProcessor{}
+			client := makeTestClientWithConfig(func(c *Config) {
+				c.Events = mocks.SingleComponentConfigurer[ldevents.EventProcessor]{Instance: events}
+				c.Hooks = []ldhooks.Hook{hook}
+			})
+			defer client.Close()
+
+			testCase.call(client)
+
+			calls := hook.Calls()
+			require.Len(t, calls, 2)
+			for i, stage := range []string{"BeforeTrack", "AfterTrack"} {
+				assert.Equal(t, stage, calls[i].Stage)
+				assert.Equal(t, testCase.seriesContext, calls[i].SeriesContext)
+				assert.Equal(t, testCase.goContext, calls[i].GoContext)
+			}
+			assert.Len(t, events.Events, 1)
+		})
+	}
+}
+
+func TestTrackSeriesIsExecutedWhenEventsAreDisabled(t *testing.T) {
+	hook := sharedtest.NewSeriesTestHook("test-hook")
+	client, _ := MakeCustomClient("", Config{Offline: true, Hooks: []ldhooks.Hook{hook}}, 0)
+	defer client.Close()
+
+	_ = client.TrackEvent("event", ldcontext.New("test-context"))
+
+	assert.Len(t, hook.Calls(), 2)
+}
+
+func TestIdentifySeriesIsExecutedForIdentifyMethods(t *testing.T) {
+	testContext := ldcontext.New("test-context")
+	testGoContext := gocontext.WithValue(gocontext.TODO(), "test-key", "test-value")
+	hook := sharedtest.NewSeriesTestHook("test-hook")
+	events := &mocks.CapturingEventProcessor{}
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.Events = mocks.SingleComponentConfigurer[ldevents.EventProcessor]{Instance: events}
+		c.Hooks = []ldhooks.Hook{hook}
+	})
+	defer client.Close()
+
+	_ = client.Identify(testContext)
+	_ = client.IdentifyCtx(testGoContext, testContext)
+
+	calls := hook.Calls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, ldhooks.NewIdentifySeriesContext(testContext, "LDClient.Identify"), calls[0].SeriesContext)
+	assert.Equal(t, gocontext.TODO(), calls[0].GoContext)
+	assert.Equal(t, "AfterIdentify", calls[1].Stage)
+	assert.Equal(t, ldhooks.NewIdentifySeriesContext(testContext, "LDClient.IdentifyCtx"), calls[2].SeriesContext)
+	assert.Equal(t, testGoContext, calls[2].GoContext)
+	assert.Len(t, events.Events, 2)
+}
+
+func TestMigrationSeriesIsExecutedForMigratorOperations(t *testing.T) {
+	testContext := ldcontext.New("test-context")
+	hook := sharedtest.NewSeriesTestHook("test-hook")
+	data := ldtestdata.DataSource()
+	data.UsePreconfiguredFlag(makeMigrationFlag("key", "shadow"))
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = data
+		c.Hooks = []ldhooks.Hook{hook}
+	})
+	defer client.Close()
+	writeErr := errors.New("write failed")
+	migrator, err := defaultMigrator(client).
+		Write(
+			func(interface{}) (interface{}, error) { return nil, writeErr },
+			func(interface{}) (interface{}, error) { return true, nil },
+		).
+		Build()
+	require.NoError(t, err)
+
+	_ = migrator.Read("key", testContext, ldmigration.Off, nil)
+	_ = migrator.Write("key", testContext, ldmigration.Off, nil)
+
+	calls := hook.Calls()
+	require.Len(t, calls, 4)
+	readContext := ldhooks.NewMigrationSeriesContext("key", testContext, ldmigration.Off, ldmigration.Read,
+		"Migrator.Read")
+	writeContext := ldhooks.NewMigrationSeriesContext("key", testContext, ldmigration.Off, ldmigration.Write,
+		"Migrator.Write")
+	assert.Equal(t, "BeforeMigrationOp", calls[0].Stage)
+	assert.Equal(t, readContext, calls[0].SeriesContext)
+	assert.Equal(t, "AfterMigrationOp", calls[1].Stage)
+	assert.Equal(t, ldmigration.Shadow, calls[1].MigrationStage)
+	assert.NoError(t, calls[1].Err)
+	assert.Equal(t, writeContext, calls[2].SeriesContext)
+	assert.Equal(t, ldmigration.Shadow, calls[3].MigrationStage)
+	assert.Equal(t, writeErr, calls[3].Err)
+}
+
+func TestMigrationSeriesReceivesGoContextOfMigratorCtxOperations(t *testing.T) {
+	testContext := ldcontext.New("test-context")
+	testGoContext := gocontext.WithValue(gocontext.TODO(), "test-key", "test-value")
+	hook := sharedtest.NewSeriesTestHook("test-hook")
+	data := ldtestdata.DataSource()
+	data.UsePreconfiguredFlag(makeMigrationFlag("key", "shadow"))
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = data
+		c.Hooks = []ldhooks.Hook{hook}
+	})
+	defer client.Close()
+	migrator, err := defaultMigrator(client).Build()
+	require.NoError(t, err)
+
+	_ = migrator.Read("key", testContext, ldmigration.Off, nil)
+	_ = migrator.ReadCtx(testGoContext, "key", testContext, ldmigration.Off, nil)
+	_ = migrator.WriteCtx(testGoContext, "key", testContext, ldmigration.Off, nil)
+
+	calls := hook.Calls()
+	require.Len(t, calls, 6)
+	assert.Equal(t, gocontext.TODO(), calls[0].GoContext)
+	assert.Equal(t, ldhooks.NewMigrationSeriesContext("key", testContext, ldmigration.Off, ldmigration.Read,
+		"Migrator.ReadCtx"), calls[2].SeriesContext)
+	assert.Equal(t, testGoContext, calls[2].GoContext)
+	assert.Equal(t, testGoContext, calls[3].GoContext)
+	assert.Equal(t, ldhooks.NewMigrationSeriesContext("key", testContext, ldmigration.Off, ldmigration.Write,
+		"Migrator.WriteCtx"), calls[4].SeriesContext)
+	assert.Equal(t, testGoContext, calls[4].GoContext)
+	assert.Equal(t, testGoContext, calls[5].GoContext)
+}
+
+func TestEvaluationSeriesReceivesGoContextOfMigratorCtxOperations(t *testing.T) {
+	testContext := ldcontext.New("test-context")
+	testGoContext := gocontext.WithValue(gocontext.TODO(), "test-key", "test-value")
+	hook := sharedtest.NewTestHook("test-hook")
+	client, _ := MakeCustomClient("", Config{Offline: true, Hooks: []ldhooks.Hook{hook}}, 0)
+	defer client.Close()
+	migrator, err := defaultMigrator(client).Build()
+	require.NoError(t, err)
+
+	_ = migrator.ReadCtx(testGoContext, hookTestFlag, testContext, ldmigration.Off, nil)
+
+	hook.Verify(
+		t,
+		sharedtest.HookExpectedCall{
+			HookStage: sharedtest.HookStageBeforeEvaluation,
+			EvalCapture: sharedtest.HookEvalCapture{
+				GoContext: testGoContext,
+				EvaluationSeriesContext: ldhooks.NewEvaluationSeriesContext(hookTestFlag, testContext,
+					ldvalue.String(string(ldmigration.Off)), "LDClient.MigrationVariationCtx"),
+				EvaluationSeriesData: ldhooks.EmptyEvaluationSeriesData(),
+			},
+		},
+		sharedtest.HookExpectedCall{
+			HookStage: sharedtest.HookStageAfterEvaluation,
+			EvalCapture: sharedtest.HookEvalCapture{
+				GoContext: testGoContext,
+				EvaluationSeriesContext: ldhooks.NewEvaluationSeriesContext(hookTestFlag, testContext,
+					ldvalue.String(string(ldmigration.Off)), "LDClient.MigrationVariationCtx"),
+				EvaluationSeriesData: ldhooks.EmptyEvaluationSeriesData(),
+				Detail: ldreason.NewEvaluationDetailForError(ldreason.EvalErrorClientNotReady,
+					ldvalue.String(string(ldmigration.Off))),
+			},
+		},
+	)
+}
@@ -1,14 +1,17 @@
 package ldclient
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
 
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
 	"github.com/launchdarkly/go-sdk-common/v3/lduser"
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
 	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
@@ -88,6 +91,26 @@ func TestFlagTracker(t *testing.T) {
 		})
 	})
 
+	t.Run("sends flag change events to callback and context-scoped channel", func(t *testing.T) {
+		clientListenersTest(func(p clientListenersTestParams) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ch1 := make(chan interfaces.FlagChangeEvent, 10)
+			unsubscribe := p.client.GetFlagTracker().AddFlagChangeListenerFunc(ctx,
+				func(event interfaces.FlagChangeEvent) { ch1 <- event }, interfaces.ListenerOptions{})
+			defer unsubscribe()
+			ch2 := p.client.GetFlagTracker().AddFlagChangeListenerCtx(ctx, interfaces.ListenerOptions{})
+
+			p.testData.Update(p.testData.Flag(flagKey))
+
+			sharedtest.ExpectFlagChangeEvents(t, ch1, flagKey)
+			sharedtest.ExpectFlagChangeEvents(t, ch2, flagKey)
+
+			cancel()
+			th.AssertChannelClosed(t, ch2, time.Second)
+		})
+	})
+
 	t.Run("sends flag value change events", func(t *testing.T) {
 		flagKey := "important-flag"
 		user := lduser.NewUser("important-user")
@@ -119,6 +142,42 @@ func TestFlagTracker(t *testing.T) {
 			th.AssertNoMoreValues(t, ch3, timeout)
 		})
 	})
+
+	t.Run("sends batched flag value change events for multiple contexts", func(t *testing.T) {
+		user := lduser.NewUser("important-user")
+		otherUser := lduser.NewUser("unimportant-user")
+		events := &mocks.CapturingEventProcessor{}
+
+		clientListenersTestWithConfig(func(c *Config) {
+			c.Events = mocks.SingleComponentConfigurer[ldevents.EventProcessor]{Instance: events}
+		}, func(p clientListenersTestParams) {
+			p.testData.Update(p.testData.Flag(flagKey).VariationForAll(false))
+			p.testData.Update(p.testData.Flag("other-flag").VariationForAll(false))
+
+			ch := p.client.GetFlagTracker().AddFlagValuesChangeListener(interfaces.FlagValuesChangeListenerConfig{
+				Contexts: []ldcontext.Context{user, otherUser},
+			})
+			th.AssertNoMoreValues(t, ch, timeout)
+
+			p.testData.Update(p.testData.Flag(flagKey).VariationForUser(user.Key(), true))
+			batch := th.RequireValue(t, ch, time.Second)
+			assert.Equal(t, []interfaces.ContextFlagValueChangeEvent{
+				{Context: user, Key: flagKey, OldValue: ldvalue.Bool(false), NewValue: ldvalue.Bool(true)},
+			}, batch.Changes)
+
+			p.testData.Update(p.testData.Flag("new-flag").VariationForAll(true))
+			batch = th.RequireValue(t, ch, time.Second)
+			assert.Equal(t, []interfaces.ContextFlagValueChangeEvent{
+				{Context: user, Key: "new-flag", OldValue: ldvalue.Null(), NewValue: ldvalue.Bool(true)},
+				{Context: otherUser, Key: "new-flag", OldValue: ldvalue.Null(), NewValue: ldvalue.Bool(true)},
+			}, batch.Changes)
+
+			p.client.GetFlagTracker().RemoveFlagValuesChangeListener(ch)
+			th.AssertChannelClosed(t, ch, time.Second)
+
+			assert.Len(t, events.Events, 0)
+		})
+	})
 }
 
 func TestDataSourceStatusProvider(t *testing.T) {
@@ -161,6 +220,37 @@ func TestDataSourceStatusProvider(t *testing.T) {
 			assert.Equal(t, errorInfo, newStatus.LastError)
 		})
 	})
+
+	t.Run("sends status updates to callback", func(t *testing.T) {
+		clientListenersTest(func(p clientListenersTestParams) {
+			statusCh := make(chan interfaces.DataSourceStatus, 10)
+			unsubscribe := p.client.GetDataSourceStatusProvider().AddStatusListenerFunc(nil,
+				func(status interfaces.DataSourceStatus) { statusCh <- status },
+				interfaces.ListenerOptions{OverflowPolicy: interfaces.ListenerOverflowKeepLatest})
+
+			p.testData.UpdateStatus(interfaces.DataSourceStateInterrupted, interfaces.DataSourceErrorInfo{})
+			newStatus := th.RequireValue(t, statusCh, time.Second)
+			assert.Equal(t, interfaces.DataSourceStateInterrupted, newStatus.State)
+
+			unsubscribe()
+			p.testData.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+			th.AssertNoMoreValues(t, statusCh, time.Millisecond*100)
+		})
+	})
+
+	t.Run("sends status updates to channel until context is done", func(t *testing.T) {
+		clientListenersTest(func(p clientListenersTestParams) {
+			ctx, cancel := context.WithCancel(context.Background())
+			statusCh := p.client.GetDataSourceStatusProvider().AddStatusListenerCtx(ctx, interfaces.ListenerOptions{})
+
+			p.testData.UpdateStatus(interfaces.DataSourceStateInterrupted, interfaces.DataSourceErrorInfo{})
+			newStatus := th.RequireValue(t, statusCh, time.Second)
+			assert.Equal(t, interfaces.DataSourceStateInterrupted, newStatus.State)
+
+			cancel()
+			th.AssertChannelClosed(t, statusCh, time.Second)
+		})
+	})
 }
 
 func TestDataStoreStatusProvider(t *testing.T) {
@@ -188,6 +278,21 @@ func TestDataStoreStatusProvider(t *testing.T) {
 			assert.Equal(t, newStatus, s)
 		})
 	})
+
+	t.Run("sends status updates to callback", func(t *testing.T) {
+		clientListenersTest(func(p clientListenersTestParams) {
+			newStatus := interfaces.DataStoreStatus{Available: false}
+			statusCh := make(chan interfaces.DataStoreStatus, 10)
+			unsubscribe := p.client.GetDataStoreStatusProvider().AddStatusListenerFunc(nil,
+				func(status interfaces.DataStoreStatus) { statusCh <- status }, interfaces.ListenerOptions{})
+			defer unsubscribe()
+
+			p.dataStoreUpdates.UpdateStatus(newStatus)
+
+			s := th.RequireValue(t, statusCh, time.Second*2, "timed out waiting for new status")
+			assert.Equal(t, newStatus, s)
+		})
+	})
 }
 
 func TestBigSegmentsStoreStatusProvider(t *testing.T) {
@@ -0,0 +1,281 @@
+package ldclient
+
+import (
+	gocontext "context"
+	"sync"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldeval "github.com/launchdarkly/go-server-sdk-evaluation/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+)
+
+// EvaluationScope evaluates feature flags for one evaluation context, with a consistent view of the flag
+// data, over a short unit of work such as the handling of a single HTTP request. It is created by
+// [LDClient.Scope].
+//
+// The first time the scope reads a flag or segment, it keeps that version for the rest of its lifetime,
+// even if the SDK receives an update in the meantime; and the result of evaluating each flag is reused for
+// the rest of its lifetime. So evaluating a flag several times within a scope always gives the same result,
+// and so do any two flags that depend on the same data.
+//
+// Every call to a Variation method runs the evaluation series of any configured hooks, just as for the
+// LDClient's Ctx methods, but an analytics event is only generated the first time that each flag is
+// evaluated in the scope. An EvaluationScope is safe for concurrent use, but it should not be kept for
+// longer than the unit of work: it never sees any changes to the flags that it has already read.
+type EvaluationScope struct {
+	client      *LDClient
+	ctx         gocontext.Context
+	evalContext ldcontext.Context
+	evaluator   ldeval.Evaluator
+	store       *pinnedStore
+	results     map[string]scopedEvaluation
+	evaluated   map[string]struct{}
+	lock        sync.Mutex
+}
+
+type scopedEvaluation struct {
+	result ldeval.Result
+	flag   *ldmodel.FeatureFlag
+}
+
+// Scope creates an [EvaluationScope] for evaluating flags for the specified evaluation context, with a
+// consistent view of the flag data. The context.Context is passed to hooks for each evaluation, in the
+// same way as for the LDClient's Ctx methods, such as [LDClient.BoolVariationCtx].
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    scope := client.Scope(r.Context(), contextFromRequest(r))
+//	    if enabled, _ := scope.BoolVariation("new-checkout", false); enabled {
+//	        // ...
+//	    }
+//	}
+//
+// Creating a scope does not read any data, so it is cheap to create one per request.
+func (client *LDClient) Scope(ctx gocontext.Context, evalContext ldcontext.Context) *EvaluationScope {
+	store := &pinnedStore{store: client.dataSystem.Store()}
+	return &EvaluationScope{
+		client:      client,
+		ctx:         ctx,
+		evalContext: evalContext,
+		evaluator:   client.newEvaluator(ldstoreimpl.NewDataStoreEvaluatorDataProvider(store, client.loggers)),
+		store:       store,
+	}
+}
+
+// Context returns the evaluation context that the scope evaluates flags for.
+func (s *EvaluationScope) Context() ldcontext.Context {
+	return s.evalContext
+}
+
+// BoolVariation is the same as [LDClient.BoolVariationCtx], using the scope's view of the flag data.
+func (s *EvaluationScope) BoolVariation(key string, defaultVal bool) (bool, error) {
+	detail, err := s.variation(key, ldvalue.Bool(defaultVal), true, s.client.eventsDefault, boolVarExFuncName)
+	return detail.Value.BoolValue(), err
+}
+
+// BoolVariationDetail is the same as [LDClient.BoolVariationDetailCtx], using the scope's view of the flag
+// data.
+func (s *EvaluationScope) BoolVariationDetail(key string, defaultVal bool) (bool, ldreason.EvaluationDetail, error) {
+	detail, err := s.variation(key, ldvalue.Bool(defaultVal), true, s.client.eventsWithReasons,
+		boolVarDetailExFuncName)
+	return detail.Value.BoolValue(), detail, err
+}
+
+// IntVariation is the same as [LDClient.IntVariationCtx], using the scope's view of the flag data.
+func (s *EvaluationScope) IntVariation(key string, defaultVal int) (int, error) {
+	detail, err := s.variation(key, ldvalue.Int(defaultVal), true, s.client.eventsDefault, intVarExFuncName)
+	return detail.Value.IntValue(), err
+}
+
+// IntVariationDetail is the same as [LDClient.IntVariationDetailCtx], using the scope's view of the flag
+// data.
+func (s *EvaluationScope) IntVariationDetail(key string, defaultVal int) (int, ldreason.EvaluationDetail, error) {
+	detail, err := s.variation(key, ldvalue.Int(defaultVal), true, s.client.eventsWithReasons,
+		intVarDetailExFuncName)
+	return detail.Value.IntValue(), detail, err
+}
+
+// Float64Variation is the same as [LDClient.Float64VariationCtx], using the scope's view of the flag data.
+func (s *EvaluationScope) Float64Variation(key string, defaultVal float64) (float64, error) {
+	detail, err := s.variation(key, ldvalue.Float64(defaultVal), true, s.client.eventsDefault, floatVarExFuncName)
+	return detail.Value.Float64Value(), err
+}
+
+// Float64VariationDetail is the same as [LDClient.Float64VariationDetailCtx], using the scope's view of the
+// flag data.
+func (s *EvaluationScope) Float64VariationDetail(
+	key string,
+	defaultVal float64,
+) (float64, ldreason.EvaluationDetail, error) {
+	detail, err := s.variation(key, ldvalue.Float64(defaultVal), true, s.client.eventsWithReasons,
+		floatVarDetailExFuncName)
+	return detail.Value.Float64Value(), detail, err
+}
+
+// StringVariation is the same as [LDClient.StringVariationCtx], using the scope's view of the flag data.
+func (s *EvaluationScope) StringVariation(key string, defaultVal string) (string, error) {
+	detail, err := s.variation(key, ldvalue.String(defaultVal), true, s.client.eventsDefault, stringVarExFuncName)
+	return detail.Value.StringValue(), err
+}
+
+// StringVariationDetail is the same as [LDClient.StringVariationDetailCtx], using the scope's view of the
+// flag data.
+func (s *EvaluationScope) StringVariationDetail(
+	key string,
+	defaultVal string,
+) (string, ldreason.EvaluationDetail, error) {
+	detail, err := s.variation(key, ldvalue.String(defaultVal), true, s.client.eventsWithReasons,
+		stringVarDetailExFuncName)
+	return detail.Value.StringValue(), detail, err
+}
+
+// JSONVariation is the same as [LDClient.JSONVariationCtx], using the scope's view of the flag data.
+func (s *EvaluationScope) JSONVariation(key string, defaultVal ldvalue.Value) (ldvalue.Value, error) {
+	detail, err := s.variation(key, defaultVal, false, s.client.eventsDefault, jsonVarExFuncName)
+	return detail.Value, err
+}
+
+// JSONVariationDetail is the same as [LDClient.JSONVariationDetailCtx], using the scope's view of the flag
+// data.
+func (s *EvaluationScope) JSONVariationDetail(
+	key string,
+	defaultVal ldvalue.Value,
+) (ldvalue.Value, ldreason.EvaluationDetail, error) {
+	detail, err := s.variation(key, defaultVal, false, s.client.eventsWithReasons, jsonVarDetailExFuncName)
+	return detail.Value, detail, err
+}
+
+func (s *EvaluationScope) variation(
+	key string,
+	defaultVal ldvalue.Value,
+	checkType bool,
+	eventsScope eventsScope,
+	method string,
+) (ldreason.EvaluationDetail, error) {
+	s.lock.Lock()
+	if _, ok := s.evaluated[key]; ok {
+		eventsScope = newDisabledEventsScope()
+	} else {
+		if s.evaluated == nil {
+			s.evaluated = make(map[string]struct{})
+		}
+		s.evaluated[key] = struct{}{}
+	}
+	s.lock.Unlock()
+
+	detail, _, err := s.client.variationWithHooks(s.ctx, s.evaluate, key, s.evalContext, defaultVal, checkType, nil,
+		eventsScope, method)
+	return detail, err
+}
+
+// evaluate is the flagEvaluationFunc for the scope. It reuses the result of an earlier successful evaluation
+// of the same flag if there is one; only the default value can be different.
+func (s *EvaluationScope) evaluate(
+	key string,
+	context ldcontext.Context,
+	defaultVal ldvalue.Value,
+	eventsScope eventsScope,
+) (ldeval.Result, *ldmodel.FeatureFlag, error) {
+	s.lock.Lock()
+	previous, ok := s.results[key]
+	s.lock.Unlock()
+	if ok {
+		result := previous.result
+		if result.Detail.IsDefaultValue() {
+			result.Detail.Value = defaultVal
+		}
+		return result, previous.flag, nil
+	}
+
+	// The evaluation cache is not used, because its results might be based on newer data than the scope has.
+	result, flag, err := s.client.evaluateFrom(s.store, s.evaluator, nil, key, context, defaultVal, eventsScope)
+	if err == nil {
+		s.lock.Lock()
+		if previous, ok := s.results[key]; ok {
+			// Another goroutine evaluated the same flag at the same time. The results are normally the same,
+			// since they are based on the same pinned data, but Big Segment membership could differ.
+			result, flag = previous.result, previous.flag
+			if result.Detail.IsDefaultValue() {
+				result.Detail.Value = defaultVal
+			}
+		} else {
+			if s.results == nil {
+				s.results = make(map[string]scopedEvaluation)
+			}
+			s.results[key] = scopedEvaluation{result: result, flag: flag}
+		}
+		s.lock.Unlock()
+	}
+	return result, flag, err
+}
+
+// pinnedStore is a subsystems.ReadOnlyStore that remembers each item the first time it is read from the
+// underlying store, and returns the same item every time after that. Errors are not remembered.
+type pinnedStore struct {
+	store subsystems.ReadOnlyStore
+	items map[pinnedStoreKey]ldstoretypes.ItemDescriptor
+	lock  sync.Mutex
+}
+
+type pinnedStoreKey struct {
+	kind string
+	key  string
+}
+
+func (p *pinnedStore) Get( //nolint:revive // no doc comment for standard method
+	kind ldstoretypes.DataKind,
+	key string,
+) (ldstoretypes.ItemDescriptor, error) {
+	p.lock.Lock()
+	item, ok := p.items[pinnedStoreKey{kind.GetName(), key}]
+	p.lock.Unlock()
+	if ok {
+		return item, nil
+	}
+	item, err := p.store.Get(kind, key)
+	if err != nil {
+		return item, err
+	}
+	return p.pin(kind, key, item), nil
+}
+
+func (p *pinnedStore) GetAll( //nolint:revive // no doc comment for standard method
+	kind ldstoretypes.DataKind,
+) ([]ldstoretypes.KeyedItemDescriptor, error) {
+	items, err := p.store.GetAll(kind)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]ldstoretypes.KeyedItemDescriptor, 0, len(items))
+	for _, item := range items {
+		ret = append(ret, ldstoretypes.KeyedItemDescriptor{Key: item.Key, Item: p.pin(kind, item.Key, item.Item)})
+	}
+	return ret, nil
+}
+
+// pin stores the item if there is not already one for the same key, and returns whichever one is stored.
+func (p *pinnedStore) pin(
+	kind ldstoretypes.DataKind,
+	key string,
+	item ldstoretypes.ItemDescriptor,
+) ldstoretypes.ItemDescriptor {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	storeKey := pinnedStoreKey{kind.GetName(), key}
+	if existing, ok := p.items[storeKey]; ok {
+		return existing
+	}
+	if p.items == nil {
+		p.items = make(map[pinnedStoreKey]ldstoretypes.ItemDescriptor)
+	}
+	p.items[storeKey] = item
+	return item
+}
+
+func (p *pinnedStore) IsInitialized() bool { //nolint:revive // no doc comment for standard method
+	return p.store.IsInitialized()
+}
@@ -0,0 +1,147 @@
+package ldclient
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluationScope(t *testing.T) {
+	t.Run("keeps the first version of a flag", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.Update(p.data.Flag(evalFlagKey).VariationForAll(true))
+			scope := p.client.Scope(gocontext.Background(), evalTestUser)
+
+			value, err := scope.BoolVariation(evalFlagKey, false)
+			require.NoError(t, err)
+			assert.True(t, value)
+
+			p.data.Update(p.data.Flag(evalFlagKey).VariationForAll(false))
+
+			value, err = scope.BoolVariation(evalFlagKey, false)
+			require.NoError(t, err)
+			assert.True(t, value)
+
+			value, _ = p.client.BoolVariation(evalFlagKey, evalTestUser, false)
+			assert.False(t, value)
+			value, _ = p.client.Scope(gocontext.Background(), evalTestUser).BoolVariation(evalFlagKey, true)
+			assert.False(t, value)
+		})
+	})
+
+	t.Run("keeps the first version of a segment", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segment1").
+				Included(evalTestUser.Key()).Build())
+			for _, key := range []string{"flag1", "flag2"} {
+				p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(key).On(true).
+					Variations(ldvalue.Bool(false), ldvalue.Bool(true)).FallthroughVariation(0).
+					AddRule(ldbuilders.NewRuleBuilder().Variation(1).
+						Clauses(ldbuilders.SegmentMatchClause("segment1"))).
+					Build())
+			}
+			scope := p.client.Scope(gocontext.Background(), evalTestUser)
+
+			value, _ := scope.BoolVariation("flag1", false)
+			assert.True(t, value)
+
+			p.data.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segment1").Build())
+
+			// flag2 had not been evaluated yet, but it uses the same version of the segment as flag1
+			value, _ = scope.BoolVariation("flag2", false)
+			assert.True(t, value)
+			value, _ = p.client.BoolVariation("flag2", evalTestUser, false)
+			assert.False(t, value)
+		})
+	})
+
+	t.Run("keeps a flag that was not found", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			scope := p.client.Scope(gocontext.Background(), evalTestUser)
+
+			value, detail, err := scope.StringVariationDetail(evalFlagKey, "default")
+			assert.Error(t, err)
+			assert.Equal(t, "default", value)
+			assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorFlagNotFound), detail.Reason)
+
+			p.data.Update(p.data.Flag(evalFlagKey).ValueForAll(ldvalue.String("value")))
+
+			value, _ = scope.StringVariation(evalFlagKey, "default")
+			assert.Equal(t, "default", value)
+		})
+	})
+
+	t.Run("uses the default value of each call", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(false).Build())
+			scope := p.client.Scope(gocontext.Background(), evalTestUser)
+
+			value, _ := scope.IntVariation(evalFlagKey, 1)
+			assert.Equal(t, 1, value)
+			value, _ = scope.IntVariation(evalFlagKey, 2)
+			assert.Equal(t, 2, value)
+		})
+	})
+
+	t.Run("checks the type in each call", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.Update(p.data.Flag(evalFlagKey).ValueForAll(ldvalue.String("value")))
+			scope := p.client.Scope(gocontext.Background(), evalTestUser)
+
+			value, _ := scope.StringVariation(evalFlagKey, "default")
+			assert.Equal(t, "value", value)
+			_, detail, _ := scope.BoolVariationDetail(evalFlagKey, false)
+			assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorWrongType), detail.Reason)
+		})
+	})
+
+	t.Run("sends one event per flag and runs hooks for every call", func(t *testing.T) {
+		hookCalls := 0
+		hook := sharedtest.NewTestHook("test-hook")
+		hook.AfterInject = func(
+			ctx gocontext.Context,
+			context ldhooks.EvaluationSeriesContext,
+			data ldhooks.EvaluationSeriesData,
+			detail ldreason.EvaluationDetail,
+		) (ldhooks.EvaluationSeriesData, error) {
+			hookCalls++
+			return data, nil
+		}
+		testData := ldtestdata.DataSource()
+		events := &mocks.CapturingEventProcessor{}
+		client := makeTestClientWithConfig(func(c *Config) {
+			c.DataSource = testData
+			c.Events = mocks.SingleComponentConfigurer[ldevents.EventProcessor]{Instance: events}
+			c.Hooks = []ldhooks.Hook{hook}
+		})
+		defer client.Close()
+
+		testData.Update(testData.Flag("prereq").VariationForAll(true))
+		testData.UsePreconfiguredFlag(ldbuilders.NewFlagBuilder(evalFlagKey).On(true).
+			Variations(ldvalue.Bool(false), ldvalue.Bool(true)).FallthroughVariation(1).
+			AddPrerequisite("prereq", 0).Build())
+		scope := client.Scope(gocontext.Background(), evalTestUser)
+
+		for i := 0; i < 3; i++ {
+			value, _ := scope.BoolVariation(evalFlagKey, false)
+			assert.True(t, value)
+		}
+
+		assert.Equal(t, 3, hookCalls)
+		// one event for the flag and one for its prerequisite
+		require.Len(t, events.Events, 2)
+		assert.Equal(t, "prereq", events.Events[0].(ldevents.EvaluationData).Key)
+		assert.Equal(t, evalFlagKey, events.Events[1].(ldevents.EvaluationData).Key)
+	})
+}
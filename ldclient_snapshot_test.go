@@ -0,0 +1,86 @@
+package ldclient
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datasystem"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeClientSnapshotFile(t *testing.T) string {
+	data := ldtestdata.DataSource()
+	data.Update(data.Flag(evalFlagKey).VariationForAll(true))
+	data.Update(data.Flag("string-flag").Variations(ldvalue.String("a"), ldvalue.String("b")).
+		FallthroughVariationIndex(1))
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = data
+	})
+	defer client.Close()
+
+	filePath := filepath.Join(t.TempDir(), "snapshot.json")
+	file, err := os.Create(filePath)
+	require.NoError(t, err)
+	require.NoError(t, client.WriteSnapshot(file))
+	require.NoError(t, file.Close())
+	return filePath
+}
+
+func TestWriteSnapshotReturnsErrorIfClientHasNoData(t *testing.T) {
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = mocks.DataSourceThatNeverInitializes()
+	})
+	defer client.Close()
+
+	var buf bytes.Buffer
+	assert.Error(t, client.WriteSnapshot(&buf))
+}
+
+func TestClientCanBootstrapFromSnapshot(t *testing.T) {
+	filePath := writeClientSnapshotFile(t)
+
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataStore = nil
+		c.DataSource = ldcomponents.BootstrapFromSnapshot(filePath, mocks.DataSourceThatNeverInitializes())
+	})
+	defer client.Close()
+
+	boolValue, err := client.BoolVariation(evalFlagKey, evalTestUser, false)
+	assert.NoError(t, err)
+	assert.True(t, boolValue)
+	stringValue, err := client.StringVariation("string-flag", evalTestUser, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "b", stringValue)
+}
+
+func TestClientCanUseSnapshotInitializer(t *testing.T) {
+	filePath := writeClientSnapshotFile(t)
+
+	client, err := MakeCustomClient(testSdkKey, Config{
+		DataSystem: ldcomponents.DataSystem().
+			Initializers(ldcomponents.SnapshotInitializer(filePath)).
+			Synchronizers(nil, nil),
+		Events:  ldcomponents.NoEvents(),
+		Logging: ldcomponents.Logging().Loggers(sharedtest.NewTestLoggers()),
+	}, time.Second)
+	// Without a synchronizer to confirm it, the snapshot data is only considered cached.
+	assert.Equal(t, ErrInitializationFailed, err)
+	require.NotNil(t, client)
+	defer client.Close()
+
+	assert.True(t, client.Initialized())
+	assert.Equal(t, datasystem.Cached, client.dataSystem.DataAvailability())
+	boolValue, err := client.BoolVariation(evalFlagKey, evalTestUser, false)
+	assert.NoError(t, err)
+	assert.True(t, boolValue)
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
 
 	"github.com/launchdarkly/go-sdk-common/v3/lduser"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
 	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
 	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
 	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
@@ -37,6 +39,29 @@ func TestErrorFromComponentFactoryStopsClientCreation(t *testing.T) {
 	doTest("DataStore", Config{DataStore: mocks.ComponentConfigurerThatReturnsError[subsystems.DataStore]{Err: fakeError}}, fakeError)
 	doTest("Events", Config{Events: mocks.ComponentConfigurerThatReturnsError[ldevents.EventProcessor]{Err: fakeError}}, fakeError)
 	doTest("HTTP", Config{HTTP: ldcomponents.HTTPConfiguration().CACert([]byte{1})}, errors.New("invalid CA certificate data"))
+	doTest("DataSystem", Config{DataSystem: mocks.ComponentConfigurerThatReturnsError[subsystems.DataSystemConfiguration]{Err: fakeError}}, fakeError)
+}
+
+func TestDataSystemReplacesDataSourceAndDataStore(t *testing.T) {
+	flag := ldbuilders.NewFlagBuilder("flag").Version(1).On(false).OffVariation(0).Variations(ldvalue.Bool(true)).Build()
+	store := populateStore(func(store subsystems.DataStore) {
+		_ = store.Init(sharedtest.NewDataSetBuilder().Flags(flag).Build())
+	})
+
+	client, err := MakeCustomClient(testSdkKey, Config{
+		Logging:    ldcomponents.Logging().Loggers(sharedtest.NewTestLoggers()),
+		DataSource: mocks.DataSourceThatNeverInitializes(),
+		DataSystem: ldcomponents.DataSystem().
+			DataStore(store, subsystems.DataStoreModeRead).
+			Synchronizers(nil, nil),
+		Events: ldcomponents.NoEvents(),
+	}, time.Second)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.True(t, client.Initialized())
+	value, _ := client.BoolVariation("flag", lduser.NewUser("key"), false)
+	assert.True(t, value)
 }
 
 func TestSecureModeHash(t *testing.T) {
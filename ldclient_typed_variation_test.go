@@ -0,0 +1,145 @@
+package ldclient
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedVariationTestValue struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+}
+
+var typedVariationTestDefault = typedVariationTestValue{Name: "default"}
+
+func TestTypedVariation(t *testing.T) {
+	expected := typedVariationTestValue{Name: "a", Count: 2, Tags: []string{"x", "y"}}
+	flagValue := ldvalue.Parse([]byte(`{"name":"a","count":2,"tags":["x","y"]}`))
+
+	t.Run("simple", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.setupSingleValueFlag(evalFlagKey, flagValue)
+
+			actual, err := Variation(p.client, evalFlagKey, evalTestUser, typedVariationTestDefault)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual)
+
+			p.expectSingleEvaluationEvent(t, evalFlagKey, flagValue, ldvalue.CopyArbitraryValue(typedVariationTestDefault),
+				noReason)
+		})
+	})
+
+	t.Run("detail", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.setupSingleValueFlag(evalFlagKey, flagValue)
+
+			actual, detail, err := VariationDetail(p.client, evalFlagKey, evalTestUser, typedVariationTestDefault)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual)
+			assert.Equal(t, ldreason.NewEvaluationDetail(flagValue, expectedVariationForSingleValueFlag,
+				expectedReasonForSingleValueFlag), detail)
+
+			p.expectSingleEvaluationEvent(t, evalFlagKey, flagValue, ldvalue.CopyArbitraryValue(typedVariationTestDefault),
+				expectedReasonForSingleValueFlag)
+		})
+	})
+
+	t.Run("non-struct type", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.setupSingleValueFlag(evalFlagKey, ldvalue.ArrayOf(ldvalue.Int(1), ldvalue.Int(2)))
+
+			actual, err := Variation(p.client, evalFlagKey, evalTestUser, []int{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, []int{1, 2}, actual)
+		})
+	})
+
+	t.Run("unknown flag returns default", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			actual, detail, err := VariationDetail(p.client, "unknown-flag", evalTestUser, typedVariationTestDefault)
+
+			assert.Error(t, err)
+			assert.Equal(t, typedVariationTestDefault, actual)
+			assert.Equal(t, ldreason.EvalErrorFlagNotFound, detail.Reason.GetErrorKind())
+		})
+	})
+
+	t.Run("off flag with no off variation returns default", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.data.Update(p.data.Flag(evalFlagKey).On(false).Variations(flagValue))
+
+			actual, err := Variation(p.client, evalFlagKey, evalTestUser, typedVariationTestDefault)
+
+			assert.NoError(t, err)
+			assert.Equal(t, typedVariationTestDefault, actual)
+		})
+	})
+
+	t.Run("value that cannot be decoded returns default with wrong type error", func(t *testing.T) {
+		withClientEvalTestParams(func(p clientEvalTestParams) {
+			p.setupSingleValueFlag(evalFlagKey, ldvalue.String("not an object"))
+
+			actual, detail, err := VariationDetail(p.client, evalFlagKey, evalTestUser, typedVariationTestDefault)
+
+			assert.Error(t, err)
+			assert.Equal(t, typedVariationTestDefault, actual)
+			defaultValue := ldvalue.CopyArbitraryValue(typedVariationTestDefault)
+			assert.Equal(t, ldreason.NewEvaluationDetailForError(ldreason.EvalErrorWrongType, defaultValue), detail)
+
+			e := p.requireSingleEvent(t)
+			assert.Equal(t, defaultValue, e.Value)
+			assert.Equal(t, ldreason.NewEvalReasonError(ldreason.EvalErrorWrongType), e.Reason)
+		})
+	})
+}
+
+func TestTypedVariationReportsDecodingFailureToHooks(t *testing.T) {
+	hook := sharedtest.NewTestHook("test-hook")
+	data := ldtestdata.DataSource()
+	data.Update(data.Flag(evalFlagKey).VariationForAll(true))
+	client := makeTestClientWithConfig(func(c *Config) {
+		c.DataSource = data
+		c.Hooks = []ldhooks.Hook{hook}
+	})
+	defer client.Close()
+	testGoContext := gocontext.WithValue(gocontext.TODO(), "test-key", "test-value")
+
+	_, _, err := VariationDetailCtx(testGoContext, client, evalFlagKey, evalTestUser, typedVariationTestDefault)
+	require.Error(t, err)
+
+	defaultValue := ldvalue.CopyArbitraryValue(typedVariationTestDefault)
+	seriesContext := ldhooks.NewEvaluationSeriesContext(evalFlagKey, evalTestUser, defaultValue,
+		"ldclient.VariationDetailCtx")
+	hook.Verify(t,
+		sharedtest.HookExpectedCall{
+			HookStage: sharedtest.HookStageBeforeEvaluation,
+			EvalCapture: sharedtest.HookEvalCapture{
+				EvaluationSeriesContext: seriesContext,
+				EvaluationSeriesData:    ldhooks.EmptyEvaluationSeriesData(),
+				GoContext:               testGoContext,
+			},
+		},
+		sharedtest.HookExpectedCall{
+			HookStage: sharedtest.HookStageAfterEvaluation,
+			EvalCapture: sharedtest.HookEvalCapture{
+				EvaluationSeriesContext: seriesContext,
+				EvaluationSeriesData:    ldhooks.EmptyEvaluationSeriesData(),
+				Detail:                  ldreason.NewEvaluationDetailForError(ldreason.EvalErrorWrongType, defaultValue),
+				GoContext:               testGoContext,
+			},
+		},
+	)
+}
@@ -0,0 +1,121 @@
+package ldcomponents
+
+import (
+	"time"
+
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// DefaultSynchronizerFallbackDelay is the default value for
+// [DataSystemConfigurationBuilder.FallbackDelay].
+const DefaultSynchronizerFallbackDelay = 2 * time.Minute
+
+// DataSystemConfigurationBuilder provides methods for configuring the FDv2 data system.
+//
+// This builder is not stable, and not subject to any backwards
+// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+type DataSystemConfigurationBuilder struct {
+	storeBuilder     subsystems.ComponentConfigurer[subsystems.DataStore]
+	storeMode        subsystems.DataStoreMode
+	initializers     []subsystems.ComponentConfigurer[subsystems.DataInitializer]
+	primaryBuilder   subsystems.ComponentConfigurer[subsystems.DataSource]
+	secondaryBuilder subsystems.ComponentConfigurer[subsystems.DataSource]
+	fallbackDelay    time.Duration
+}
+
+// DataSystem returns a configuration builder for the FDv2 data system. Store the result in
+// [github.com/launchdarkly/go-server-sdk/v7.Config.DataSystem] to enable it.
+//
+// This builder is not stable, and not subject to any backwards
+// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+//
+// By default, the data system has no initializers, uses [StreamingDataSourceV2] as its only synchronizer,
+// and holds data only in memory.
+//
+//	// example: poll once at startup, then stream, falling back to polling if streaming is interrupted
+//	// for five minutes
+//	config.DataSystem = ldcomponents.DataSystem().
+//	    Initializers(ldcomponents.PollingDataSourceV2().AsInitializer()).
+//	    Synchronizers(ldcomponents.StreamingDataSourceV2(), ldcomponents.PollingDataSourceV2()).
+//	    FallbackDelay(5 * time.Minute)
+func DataSystem() *DataSystemConfigurationBuilder {
+	return &DataSystemConfigurationBuilder{
+		primaryBuilder: StreamingDataSourceV2(),
+		fallbackDelay:  DefaultSynchronizerFallbackDelay,
+	}
+}
+
+// DataStore configures a persistent store, such as one created with [PersistentDataStore].
+//
+// In [subsystems.DataStoreModeRead], the store is only read from until an initializer or synchronizer
+// obtains data; this is equivalent to "daemon mode". In [subsystems.DataStoreModeReadWrite], data obtained
+// from LaunchDarkly is also written to the store.
+func (b *DataSystemConfigurationBuilder) DataStore(
+	store subsystems.ComponentConfigurer[subsystems.DataStore],
+	mode subsystems.DataStoreMode,
+) *DataSystemConfigurationBuilder {
+	b.storeBuilder = store
+	b.storeMode = mode
+	return b
+}
+
+// Initializers configures the initializers that are run, in order, when the SDK starts. The first one to
+// obtain data wins, and the rest are skipped. Initializers run before the synchronizers are started.
+func (b *DataSystemConfigurationBuilder) Initializers(
+	initializers ...subsystems.ComponentConfigurer[subsystems.DataInitializer],
+) *DataSystemConfigurationBuilder {
+	b.initializers = initializers
+	return b
+}
+
+// Synchronizers configures the data sources that keep the SDK's data up to date, such as
+// [StreamingDataSourceV2] or [PollingDataSourceV2].
+//
+// The primary synchronizer is always running. If its status stays
+// [github.com/launchdarkly/go-server-sdk/v7/interfaces.DataSourceStateInterrupted] for longer than
+// the FallbackDelay, or it stops permanently, the secondary synchronizer is started. As soon as the
+// primary recovers, the secondary is stopped again.
+//
+// The secondary may be nil. If primary is nil, the secondary is ignored and the SDK only uses data from the
+// initializers and the DataStore.
+func (b *DataSystemConfigurationBuilder) Synchronizers(
+	primary, secondary subsystems.ComponentConfigurer[subsystems.DataSource],
+) *DataSystemConfigurationBuilder {
+	b.primaryBuilder = primary
+	b.secondaryBuilder = secondary
+	return b
+}
+
+// FallbackDelay sets how long the primary synchronizer may be interrupted before the data system switches
+// to the secondary synchronizer.
+//
+// The default value is [DefaultSynchronizerFallbackDelay]. Values less than or equal to zero will be set
+// to the default.
+func (b *DataSystemConfigurationBuilder) FallbackDelay(fallbackDelay time.Duration) *DataSystemConfigurationBuilder {
+	if fallbackDelay <= 0 {
+		b.fallbackDelay = DefaultSynchronizerFallbackDelay
+	} else {
+		b.fallbackDelay = fallbackDelay
+	}
+	return b
+}
+
+// Build is called internally by the SDK.
+func (b *DataSystemConfigurationBuilder) Build(
+	context subsystems.ClientContext,
+) (subsystems.DataSystemConfiguration, error) {
+	return subsystems.DataSystemConfiguration{
+		Store:                 b.storeBuilder,
+		StoreMode:             b.storeMode,
+		Initializers:          b.initializers,
+		PrimarySynchronizer:   b.primaryBuilder,
+		SecondarySynchronizer: b.secondaryBuilder,
+		FallbackDelay:         b.fallbackDelay,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package ldcomponents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datasourcev2"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSystemConfigurationBuilder(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := DataSystem().Build(basicClientContext())
+		require.NoError(t, err)
+		assert.Nil(t, cfg.Store)
+		assert.Equal(t, subsystems.DataStoreMode(subsystems.DataStoreModeRead), cfg.StoreMode)
+		assert.Len(t, cfg.Initializers, 0)
+		assert.IsType(t, &StreamingDataSourceBuilderV2{}, cfg.PrimarySynchronizer)
+		assert.Nil(t, cfg.SecondarySynchronizer)
+		assert.Equal(t, DefaultSynchronizerFallbackDelay, cfg.FallbackDelay)
+	})
+
+	t.Run("DataStore", func(t *testing.T) {
+		store := InMemoryDataStore()
+		cfg, err := DataSystem().DataStore(store, subsystems.DataStoreModeReadWrite).Build(basicClientContext())
+		require.NoError(t, err)
+		assert.Equal(t, store, cfg.Store)
+		assert.Equal(t, subsystems.DataStoreMode(subsystems.DataStoreModeReadWrite), cfg.StoreMode)
+	})
+
+	t.Run("Initializers", func(t *testing.T) {
+		initializer := PollingDataSourceV2().AsInitializer()
+		cfg, err := DataSystem().Initializers(initializer).Build(basicClientContext())
+		require.NoError(t, err)
+		assert.Equal(t, []subsystems.ComponentConfigurer[subsystems.DataInitializer]{initializer}, cfg.Initializers)
+	})
+
+	t.Run("Synchronizers", func(t *testing.T) {
+		streaming := StreamingDataSourceV2()
+		polling := PollingDataSourceV2()
+		cfg, err := DataSystem().Synchronizers(streaming, polling).Build(basicClientContext())
+		require.NoError(t, err)
+		assert.Equal(t, streaming, cfg.PrimarySynchronizer)
+		assert.Equal(t, polling, cfg.SecondarySynchronizer)
+
+		cfg, err = DataSystem().Synchronizers(nil, nil).Build(basicClientContext())
+		require.NoError(t, err)
+		assert.Nil(t, cfg.PrimarySynchronizer)
+		assert.Nil(t, cfg.SecondarySynchronizer)
+	})
+
+	t.Run("FallbackDelay", func(t *testing.T) {
+		b := DataSystem()
+
+		b.FallbackDelay(time.Minute)
+		cfg, _ := b.Build(basicClientContext())
+		assert.Equal(t, time.Minute, cfg.FallbackDelay)
+
+		b.FallbackDelay(0)
+		cfg, _ = b.Build(basicClientContext())
+		assert.Equal(t, DefaultSynchronizerFallbackDelay, cfg.FallbackDelay)
+	})
+}
+
+func TestPollingDataSourceV2AsInitializer(t *testing.T) {
+	initializer, err := PollingDataSourceV2().PayloadFilter("microservice-1").AsInitializer().
+		Build(makeTestContextWithBaseURIs("base"))
+	require.NoError(t, err)
+	pp := initializer.(*datasourcev2.PollingProcessor)
+	assert.Equal(t, "base", pp.GetBaseURI())
+	assert.Equal(t, "microservice-1", pp.GetFilterKey())
+
+	_, err = PollingDataSourceV2().PayloadFilter("").AsInitializer().Build(basicClientContext())
+	assert.Error(t, err)
+}
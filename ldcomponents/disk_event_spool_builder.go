@@ -0,0 +1,115 @@
+package ldcomponents
+
+import (
+	"errors"
+	"time"
+
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/eventspool"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// DefaultEventSpoolMaxBytes is the default value for [DiskEventSpoolBuilder.MaxBytes].
+const DefaultEventSpoolMaxBytes = 100 * 1024 * 1024
+
+// DefaultEventSpoolMaxAge is the default value for [DiskEventSpoolBuilder.MaxAge].
+const DefaultEventSpoolMaxAge = 24 * time.Hour
+
+// DiskEventSpoolBuilder contains methods for configuring a disk-backed buffer for analytics events.
+//
+// See [DiskEventSpool] for usage.
+type DiskEventSpoolBuilder struct {
+	directory   string
+	maxBytes    int64
+	maxAge      time.Duration
+	destination subsystems.ComponentConfigurer[ldevents.EventSender]
+	onDropped   func(int)
+}
+
+// DiskEventSpool returns a configuration builder for an event sender that keeps analytics event payloads in
+// a directory until they have been delivered, for use with [EventProcessorBuilder.EventSender].
+//
+// By default, if the SDK cannot deliver a payload of analytics events, it retries once and then discards
+// the events. With a disk spool, each payload is written to the directory before it is sent, and is only
+// deleted once it has been delivered. Payloads that could not be delivered are sent again after the next
+// successful delivery, or when the SDK is next started with the same directory, such as after the process
+// restarts. A payload that keeps failing while other payloads are delivered, because the destination does
+// not accept it, is skipped so that it does not hold up the others, and is discarded after a few attempts.
+//
+//	config := ld.Config{
+//	    Events: ldcomponents.SendEvents().EventSender(
+//	        ldcomponents.DiskEventSpool("/var/lib/myapp/ld-events").MaxAge(6 * time.Hour),
+//	    ),
+//	}
+//
+// Delivery is at-least-once: if the process stops after a payload was delivered but before its file was
+// deleted, it is delivered again. Only payloads that have been flushed are spooled, so events that are
+// still in the SDK's in-memory buffer are lost if the process stops without calling
+// [github.com/launchdarkly/go-server-sdk/v7.LDClient.Close]. Diagnostic events are not spooled.
+//
+// The directory must not be shared by more than one SDK instance at a time.
+func DiskEventSpool(directory string) *DiskEventSpoolBuilder {
+	return &DiskEventSpoolBuilder{
+		directory: directory,
+		maxBytes:  DefaultEventSpoolMaxBytes,
+		maxAge:    DefaultEventSpoolMaxAge,
+	}
+}
+
+// MaxBytes sets the maximum total size of the payloads in the spool. If it is exceeded, the oldest
+// payloads are discarded. The default value is [DefaultEventSpoolMaxBytes].
+func (b *DiskEventSpoolBuilder) MaxBytes(maxBytes int64) *DiskEventSpoolBuilder {
+	if maxBytes <= 0 {
+		maxBytes = DefaultEventSpoolMaxBytes
+	}
+	b.maxBytes = maxBytes
+	return b
+}
+
+// MaxAge sets the maximum length of time that a payload will be kept in the spool. Older payloads are
+// discarded. The default value is [DefaultEventSpoolMaxAge].
+func (b *DiskEventSpoolBuilder) MaxAge(maxAge time.Duration) *DiskEventSpoolBuilder {
+	if maxAge <= 0 {
+		maxAge = DefaultEventSpoolMaxAge
+	}
+	b.maxAge = maxAge
+	return b
+}
+
+// Destination sets the event sender that spooled payloads are delivered to. The default is
+// [LaunchDarklyEventSender].
+func (b *DiskEventSpoolBuilder) Destination(
+	destination subsystems.ComponentConfigurer[ldevents.EventSender],
+) *DiskEventSpoolBuilder {
+	b.destination = destination
+	return b
+}
+
+// OnEventsDropped sets a function that is called with the number of events in any payload that is
+// discarded from the spool because of the [DiskEventSpoolBuilder.MaxBytes] or [DiskEventSpoolBuilder.MaxAge]
+// limits, or because the destination repeatedly rejected it. The SDK also logs a warning when this happens.
+func (b *DiskEventSpoolBuilder) OnEventsDropped(onDropped func(eventCount int)) *DiskEventSpoolBuilder {
+	b.onDropped = onDropped
+	return b
+}
+
+// Build is called internally by the SDK.
+func (b *DiskEventSpoolBuilder) Build(context subsystems.ClientContext) (ldevents.EventSender, error) {
+	if b.directory == "" {
+		return nil, errors.New("event spool directory must not be empty")
+	}
+	destination := b.destination
+	if destination == nil {
+		destination = LaunchDarklyEventSender()
+	}
+	target, err := destination.Build(context)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := eventspool.NewDiskSpoolSender(target, b.directory, b.maxBytes, b.maxAge, b.onDropped,
+		context.GetLogging().Loggers)
+	if err != nil {
+		return nil, err
+	}
+	return sender, nil
+}
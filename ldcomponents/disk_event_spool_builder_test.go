@@ -0,0 +1,65 @@
+package ldcomponents
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+
+	th "github.com/launchdarkly/go-test-helpers/v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskEventSpoolBuilder(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		b := DiskEventSpool("dir")
+		assert.Equal(t, "dir", b.directory)
+		assert.Equal(t, int64(DefaultEventSpoolMaxBytes), b.maxBytes)
+		assert.Equal(t, DefaultEventSpoolMaxAge, b.maxAge)
+		assert.Nil(t, b.destination)
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		assert.Equal(t, int64(1000), DiskEventSpool("dir").MaxBytes(1000).maxBytes)
+		assert.Equal(t, int64(DefaultEventSpoolMaxBytes), DiskEventSpool("dir").MaxBytes(0).maxBytes)
+	})
+
+	t.Run("MaxAge", func(t *testing.T) {
+		assert.Equal(t, time.Hour, DiskEventSpool("dir").MaxAge(time.Hour).maxAge)
+		assert.Equal(t, DefaultEventSpoolMaxAge, DiskEventSpool("dir").MaxAge(-1).maxAge)
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		dir := t.TempDir()
+		destination := newCapturingEventSender(ldevents.EventSenderResult{Success: true})
+		sender, err := DiskEventSpool(dir).Destination(destination.configurer()).Build(basicClientContext())
+		require.NoError(t, err)
+
+		result := sender.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`[]`), 0)
+		assert.True(t, result.Success)
+		assert.Equal(t, eventPayload{ldevents.AnalyticsEventDataKind, []byte(`[]`)},
+			th.RequireValue(t, destination.payloads, time.Second))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 0)
+	})
+
+	t.Run("Build with empty directory", func(t *testing.T) {
+		_, err := DiskEventSpool("").Build(basicClientContext())
+		assert.Error(t, err)
+	})
+
+	t.Run("Build with destination error", func(t *testing.T) {
+		fakeError := errors.New("sorry")
+		_, err := DiskEventSpool(t.TempDir()).
+			Destination(mocks.ComponentConfigurerThatReturnsError[ldevents.EventSender]{Err: fakeError}).
+			Build(basicClientContext())
+		assert.Equal(t, fakeError, err)
+	})
+}
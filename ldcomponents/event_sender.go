@@ -0,0 +1,132 @@
+package ldcomponents
+
+import (
+	"io"
+	"net/http"
+
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/endpoints"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// LaunchDarklyEventSenderBuilder provides methods for configuring delivery of analytics events to
+// LaunchDarkly.
+//
+// See [LaunchDarklyEventSender] for usage.
+type LaunchDarklyEventSenderBuilder struct {
+	enableGzip bool
+}
+
+// LaunchDarklyEventSender returns a configuration builder for the component that delivers analytics event
+// payloads to LaunchDarkly.
+//
+// This is what the SDK uses by default, so you only need it if you are combining LaunchDarkly with another
+// destination for events, as described in [EventProcessorBuilder.EventSender]:
+//
+//	config := ld.Config{
+//	    Events: ldcomponents.SendEvents().EventSender(
+//	        ldcomponents.TeeEventSender(ldcomponents.LaunchDarklyEventSender(), myWarehouseSender),
+//	    ),
+//	}
+//
+// Events are sent to the events service in the SDK's service endpoints configuration.
+func LaunchDarklyEventSender() *LaunchDarklyEventSenderBuilder {
+	return &LaunchDarklyEventSenderBuilder{}
+}
+
+// EnableGzip sets whether event payloads should be compressed prior to being sent to LaunchDarkly.
+//
+// The default value is false. When this builder is used with [EventProcessorBuilder.EventSender], the
+// [EventProcessorBuilder.EnableGzip] setting does not apply to it.
+func (b *LaunchDarklyEventSenderBuilder) EnableGzip(enableGzip bool) *LaunchDarklyEventSenderBuilder {
+	b.enableGzip = enableGzip
+	return b
+}
+
+// Build is called internally by the SDK.
+func (b *LaunchDarklyEventSenderBuilder) Build(context subsystems.ClientContext) (ldevents.EventSender, error) {
+	loggers := context.GetLogging().Loggers
+
+	configuredBaseURI := endpoints.SelectBaseURI(
+		context.GetServiceEndpoints(),
+		endpoints.EventsService,
+		loggers,
+	)
+
+	headers := context.GetHTTP().DefaultHeaders
+	return ldevents.NewServerSideEventSender(
+		ldevents.EventSenderConfiguration{
+			Client:            context.GetHTTP().CreateHTTPClient(),
+			BaseURI:           configuredBaseURI,
+			BaseHeaders:       func() http.Header { return headers },
+			Loggers:           loggers,
+			EnableCompression: b.enableGzip,
+		},
+		context.GetSDKKey(),
+	), nil
+}
+
+type teeEventSenderFactory struct {
+	primary     subsystems.ComponentConfigurer[ldevents.EventSender]
+	secondaries []subsystems.ComponentConfigurer[ldevents.EventSender]
+}
+
+type teeEventSender struct {
+	primary     ldevents.EventSender
+	secondaries []ldevents.EventSender
+}
+
+// TeeEventSender returns a configuration object for an event sender that delivers each payload to several
+// other event senders, for use with [EventProcessorBuilder.EventSender].
+//
+// Each payload is passed to the primary sender and then to each of the secondary senders, in order. Only
+// the result from the primary sender is reported to the SDK, so it determines whether the SDK considers
+// delivery to have succeeded, and whether it stops sending events because the SDK key is invalid; the
+// secondary senders are responsible for handling their own errors.
+func TeeEventSender(
+	primary subsystems.ComponentConfigurer[ldevents.EventSender],
+	secondaries ...subsystems.ComponentConfigurer[ldevents.EventSender],
+) subsystems.ComponentConfigurer[ldevents.EventSender] {
+	return teeEventSenderFactory{primary: primary, secondaries: secondaries}
+}
+
+func (f teeEventSenderFactory) Build(context subsystems.ClientContext) (ldevents.EventSender, error) {
+	primary, err := f.primary.Build(context)
+	if err != nil {
+		return nil, err
+	}
+	sender := &teeEventSender{primary: primary}
+	for _, s := range f.secondaries {
+		secondary, err := s.Build(context)
+		if err != nil {
+			return nil, err
+		}
+		sender.secondaries = append(sender.secondaries, secondary)
+	}
+	return sender, nil
+}
+
+func (s *teeEventSender) SendEventData( //nolint:revive // no doc comment for standard method
+	kind ldevents.EventDataKind,
+	data []byte,
+	eventCount int,
+) ldevents.EventSenderResult {
+	result := s.primary.SendEventData(kind, data, eventCount)
+	for _, secondary := range s.secondaries {
+		_ = secondary.SendEventData(kind, data, eventCount)
+	}
+	return result
+}
+
+// Close closes any of the senders that implement io.Closer, such as a disk event spool.
+func (s *teeEventSender) Close() error {
+	var firstErr error
+	for _, sender := range append([]ldevents.EventSender{s.primary}, s.secondaries...) {
+		if closer, ok := sender.(io.Closer); ok {
+			if err := closer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,158 @@
+package ldcomponents
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/lduser"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldservices"
+
+	th "github.com/launchdarkly/go-test-helpers/v3"
+	"github.com/launchdarkly/go-test-helpers/v3/httphelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type eventPayload struct {
+	kind ldevents.EventDataKind
+	data []byte
+}
+
+type capturingEventSender struct {
+	payloads chan eventPayload
+	result   ldevents.EventSenderResult
+}
+
+func newCapturingEventSender(result ldevents.EventSenderResult) *capturingEventSender {
+	return &capturingEventSender{payloads: make(chan eventPayload, 10), result: result}
+}
+
+func (s *capturingEventSender) SendEventData(
+	kind ldevents.EventDataKind,
+	data []byte,
+	eventCount int,
+) ldevents.EventSenderResult {
+	s.payloads <- eventPayload{kind, data}
+	return s.result
+}
+
+func (s *capturingEventSender) configurer() subsystems.ComponentConfigurer[ldevents.EventSender] {
+	return mocks.SingleComponentConfigurer[ldevents.EventSender]{Instance: s}
+}
+
+// closableEventSender records whether it was closed, and how many payloads it had received by then.
+type closableEventSender struct {
+	*capturingEventSender
+	closed chan int
+}
+
+func (s *closableEventSender) Close() error {
+	s.closed <- len(s.payloads)
+	return nil
+}
+
+func TestLaunchDarklyEventSender(t *testing.T) {
+	t.Run("EnableGzip", func(t *testing.T) {
+		b := LaunchDarklyEventSender()
+		assert.False(t, b.enableGzip)
+
+		b.EnableGzip(true)
+		assert.True(t, b.enableGzip)
+	})
+
+	t.Run("sends to events service", func(t *testing.T) {
+		eventsHandler, requestsCh := httphelpers.RecordingHandler(ldservices.ServerSideEventsServiceHandler())
+		httphelpers.WithServer(eventsHandler, func(server *httptest.Server) {
+			sender, err := LaunchDarklyEventSender().Build(makeTestContextWithBaseURIs(server.URL))
+			require.NoError(t, err)
+
+			result := sender.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`[]`), 0)
+			assert.True(t, result.Success)
+
+			r := <-requestsCh
+			assert.Equal(t, "/bulk", r.Request.URL.Path)
+			assert.Equal(t, testSdkKey, r.Request.Header.Get("Authorization"))
+		})
+	})
+}
+
+func TestTeeEventSender(t *testing.T) {
+	t.Run("sends to all senders and returns primary result", func(t *testing.T) {
+		primary := newCapturingEventSender(ldevents.EventSenderResult{Success: true})
+		secondary1 := newCapturingEventSender(ldevents.EventSenderResult{MustShutDown: true})
+		secondary2 := newCapturingEventSender(ldevents.EventSenderResult{})
+
+		sender, err := TeeEventSender(primary.configurer(), secondary1.configurer(), secondary2.configurer()).
+			Build(basicClientContext())
+		require.NoError(t, err)
+
+		result := sender.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`[]`), 0)
+		assert.Equal(t, ldevents.EventSenderResult{Success: true}, result)
+
+		for _, s := range []*capturingEventSender{primary, secondary1, secondary2} {
+			assert.Equal(t, eventPayload{ldevents.AnalyticsEventDataKind, []byte(`[]`)},
+				th.RequireValue(t, s.payloads, time.Second))
+		}
+	})
+
+	t.Run("build error", func(t *testing.T) {
+		fakeError := errors.New("sorry")
+		primary := newCapturingEventSender(ldevents.EventSenderResult{Success: true})
+
+		_, err := TeeEventSender(primary.configurer(),
+			mocks.ComponentConfigurerThatReturnsError[ldevents.EventSender]{Err: fakeError}).
+			Build(basicClientContext())
+		assert.Equal(t, fakeError, err)
+	})
+}
+
+func TestEventsCustomEventSender(t *testing.T) {
+	sender := newCapturingEventSender(ldevents.EventSenderResult{Success: true})
+	ep, err := SendEvents().
+		EventSender(sender.configurer()).
+		Build(basicClientContext())
+	require.NoError(t, err)
+	defer ep.Close()
+
+	ef := ldevents.NewEventFactory(false, nil)
+	ce := ef.NewCustomEventData("event-key", ldevents.Context(lduser.NewUser("key")), ldvalue.Null(), true,
+		2.5, ldvalue.OptionalInt{})
+	ep.RecordCustomEvent(ce)
+	ep.Flush()
+
+	payload := th.RequireValue(t, sender.payloads, time.Second)
+	assert.Equal(t, ldevents.AnalyticsEventDataKind, payload.kind)
+	var jsonData ldvalue.Value
+	require.NoError(t, json.Unmarshal(payload.data, &jsonData))
+	assert.Equal(t, 2, jsonData.Count())
+	assert.Equal(t, ldvalue.String("index"), jsonData.GetByIndex(0).GetByKey("kind"))
+	assert.Equal(t, ldvalue.String("custom"), jsonData.GetByIndex(1).GetByKey("kind"))
+	assert.Equal(t, ldvalue.Float64(2.5), jsonData.GetByIndex(1).GetByKey("metricValue"))
+}
+
+func TestEventSenderIsClosedAfterEventProcessor(t *testing.T) {
+	sender := &closableEventSender{
+		capturingEventSender: newCapturingEventSender(ldevents.EventSenderResult{Success: true}),
+		closed:               make(chan int, 1),
+	}
+	ep, err := SendEvents().
+		EventSender(TeeEventSender(mocks.SingleComponentConfigurer[ldevents.EventSender]{Instance: sender})).
+		Build(basicClientContext())
+	require.NoError(t, err)
+
+	ef := ldevents.NewEventFactory(false, nil)
+	ep.RecordCustomEvent(ef.NewCustomEventData("event-key", ldevents.Context(lduser.NewUser("key")), ldvalue.Null(),
+		false, 0, ldvalue.OptionalInt{}))
+	require.NoError(t, ep.Close())
+
+	// The events that were flushed when the event processor closed were delivered before the sender was closed.
+	assert.Equal(t, 1, th.RequireValue(t, sender.closed, time.Second))
+}
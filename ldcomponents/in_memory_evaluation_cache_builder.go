@@ -0,0 +1,74 @@
+package ldcomponents
+
+import (
+	"time"
+
+	"github.com/launchdarkly/go-server-sdk/v7/internal/evalcache"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// DefaultEvaluationCacheCapacity is the default value for [InMemoryEvaluationCacheBuilder.Capacity].
+const DefaultEvaluationCacheCapacity = 10000
+
+// DefaultEvaluationCacheTTL is the default value for [InMemoryEvaluationCacheBuilder.TTL].
+const DefaultEvaluationCacheTTL = time.Minute
+
+// InMemoryEvaluationCacheBuilder contains methods for configuring the SDK's in-memory evaluation cache.
+//
+// See [InMemoryEvaluationCache] for more details.
+type InMemoryEvaluationCacheBuilder struct {
+	capacity int
+	ttl      time.Duration
+}
+
+// InMemoryEvaluationCache returns a configuration builder for an in-memory cache of flag evaluation
+// results.
+//
+// By default, the SDK evaluates a flag's rules every time the flag is evaluated. For flags with many rules or
+// large segments, this can be expensive if the same contexts are evaluated often. With a cache, the SDK
+// reuses the previous result if the same version of the flag is evaluated for an identical context.
+// Analytics events and hooks are still generated for every evaluation.
+//
+// Cached results are discarded whenever the flag, or a prerequisite flag or segment that it depends on,
+// changes. Results that depended on a Big Segment are never cached.
+//
+// To use it, store it in the EvaluationCache field of [github.com/launchdarkly/go-server-sdk/v7.Config]:
+//
+//	config := ld.Config{
+//	    EvaluationCache: ldcomponents.InMemoryEvaluationCache().Capacity(50000),
+//	}
+func InMemoryEvaluationCache() *InMemoryEvaluationCacheBuilder {
+	return &InMemoryEvaluationCacheBuilder{
+		capacity: DefaultEvaluationCacheCapacity,
+		ttl:      DefaultEvaluationCacheTTL,
+	}
+}
+
+// Capacity sets the maximum number of results that will be cached at any given time. If the cache is full,
+// the least recently used result is dropped. The default value is [DefaultEvaluationCacheCapacity].
+func (b *InMemoryEvaluationCacheBuilder) Capacity(capacity int) *InMemoryEvaluationCacheBuilder {
+	if capacity <= 0 {
+		capacity = DefaultEvaluationCacheCapacity
+	}
+	b.capacity = capacity
+	return b
+}
+
+// TTL sets the maximum length of time that a result will be cached. The default value is
+// [DefaultEvaluationCacheTTL].
+//
+// Since results are discarded as soon as the SDK receives a change to the relevant flags or segments, this
+// mainly matters if the SDK is not receiving changes from LaunchDarkly, such as when it is only reading
+// from a persistent data store (see [ExternalUpdatesOnly]).
+func (b *InMemoryEvaluationCacheBuilder) TTL(ttl time.Duration) *InMemoryEvaluationCacheBuilder {
+	if ttl <= 0 {
+		ttl = DefaultEvaluationCacheTTL
+	}
+	b.ttl = ttl
+	return b
+}
+
+// Build is called internally by the SDK.
+func (b *InMemoryEvaluationCacheBuilder) Build(context subsystems.ClientContext) (subsystems.EvaluationCache, error) {
+	return evalcache.NewInMemoryEvaluationCache(b.capacity, b.ttl), nil
+}
@@ -0,0 +1,34 @@
+package ldcomponents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryEvaluationCacheBuilder(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		b := InMemoryEvaluationCache()
+		assert.Equal(t, DefaultEvaluationCacheCapacity, b.capacity)
+		assert.Equal(t, DefaultEvaluationCacheTTL, b.ttl)
+	})
+
+	t.Run("Capacity", func(t *testing.T) {
+		assert.Equal(t, 50, InMemoryEvaluationCache().Capacity(50).capacity)
+		assert.Equal(t, DefaultEvaluationCacheCapacity, InMemoryEvaluationCache().Capacity(0).capacity)
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		assert.Equal(t, time.Hour, InMemoryEvaluationCache().TTL(time.Hour).ttl)
+		assert.Equal(t, DefaultEvaluationCacheTTL, InMemoryEvaluationCache().TTL(-1).ttl)
+	})
+
+	t.Run("Build", func(t *testing.T) {
+		cache, err := InMemoryEvaluationCache().Build(basicClientContext())
+		require.NoError(t, err)
+		require.NotNil(t, cache)
+		assert.NoError(t, cache.Close())
+	})
+}
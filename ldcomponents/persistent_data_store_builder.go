@@ -41,7 +41,7 @@ func PersistentDataStore(
 ) *PersistentDataStoreBuilder {
 	return &PersistentDataStoreBuilder{
 		persistentDataStoreFactory: persistentDataStoreFactory,
-		cacheTTL:                   PersistentDataStoreDefaultCacheTime,
+		cacheConfig:                datastore.PersistentDataStoreCacheConfig{TTL: PersistentDataStoreDefaultCacheTime},
 	}
 }
 
@@ -60,7 +60,7 @@ func PersistentDataStore(
 //	}
 type PersistentDataStoreBuilder struct {
 	persistentDataStoreFactory subsystems.ComponentConfigurer[subsystems.PersistentDataStore]
-	cacheTTL                   time.Duration
+	cacheConfig                datastore.PersistentDataStoreCacheConfig
 }
 
 // CacheTime specifies the cache TTL. Items will be evicted from the cache after this amount of time
@@ -70,7 +70,7 @@ type PersistentDataStoreBuilder struct {
 //
 // If the value is negative, data is cached forever (equivalent to [PersistentDataStoreBuilder.CacheForever]).
 func (b *PersistentDataStoreBuilder) CacheTime(cacheTime time.Duration) *PersistentDataStoreBuilder {
-	b.cacheTTL = cacheTime
+	b.cacheConfig.TTL = cacheTime
 	return b
 }
 
@@ -97,13 +97,89 @@ func (b *PersistentDataStoreBuilder) NoCaching() *PersistentDataStoreBuilder {
 	return b.CacheTime(0)
 }
 
+// NotFoundCacheTime specifies a separate cache TTL for flags and segments that do not exist in the
+// persistent data store, or that have been deleted. A short TTL lets the SDK notice sooner when such an
+// item is created by another process, and a long one avoids repeated queries for keys that do not exist.
+//
+// If the value is zero or negative, these items are cached for the same time as other items, which is
+// the default. This setting has no effect if caching is disabled or if [PersistentDataStoreBuilder.CacheForever]
+// is used.
+func (b *PersistentDataStoreBuilder) NotFoundCacheTime(notFoundCacheTime time.Duration) *PersistentDataStoreBuilder {
+	b.cacheConfig.NotFoundTTL = notFoundCacheTime
+	return b
+}
+
+// CacheMaxEntries specifies the maximum number of entries in the in-memory cache. When the cache is full,
+// the least recently used entries are discarded. Each flag or segment is one entry, and so is the full set
+// of flags or segments that is read when evaluating all flags.
+//
+// If the value is zero or negative, there is no limit, which is the default. The limit is not applied if
+// [PersistentDataStoreBuilder.CacheForever] is used, because then the cache must contain all of the data.
+func (b *PersistentDataStoreBuilder) CacheMaxEntries(maxEntries int) *PersistentDataStoreBuilder {
+	b.cacheConfig.MaxEntries = maxEntries
+	return b
+}
+
+// StaleWhileRevalidate specifies whether the SDK should keep using cached items after their cache TTL has
+// expired, while it reloads them from the persistent data store in the background.
+//
+// By default, when a cached item has expired, the next evaluation that needs it waits for it to be read
+// from the data store, so any slowness of the data store adds to the evaluation time at the end of each TTL
+// period. In this mode, that evaluation uses the expired item instead, and later evaluations see the new
+// one as soon as it has been read. This also means that if the data store becomes unavailable, the SDK
+// continues to use the last items that it read.
+//
+// Expired items stay in the cache until they are replaced, so consider also setting
+// [PersistentDataStoreBuilder.CacheMaxEntries]. This setting has no effect if caching is disabled or if
+// [PersistentDataStoreBuilder.CacheForever] is used.
+func (b *PersistentDataStoreBuilder) StaleWhileRevalidate(staleWhileRevalidate bool) *PersistentDataStoreBuilder {
+	b.cacheConfig.StaleWhileRevalidate = staleWhileRevalidate
+	return b
+}
+
+// PreloadCache specifies whether the SDK should read all flags and segments from the persistent data store
+// into the in-memory cache as soon as it starts, rather than reading each item the first time it is
+// needed.
+//
+// This is mainly useful with [ExternalUpdatesOnly], where otherwise the cache is empty when the SDK starts,
+// so the first evaluations after a deployment all have to query the data store. When this is enabled, the
+// SDK does not report that it has finished initializing until the cache has been loaded:
+// [github.com/launchdarkly/go-server-sdk/v7.MakeClient] waits for it, and until then the client's data is
+// considered cached rather than up to date. If the data store cannot be read, the SDK tries again when it
+// becomes available.
+//
+// This setting has no effect if caching is disabled. To keep the cache warm after it has been loaded, use
+// [PersistentDataStoreBuilder.PreloadRefreshInterval], [PersistentDataStoreBuilder.CacheForever], or
+// [PersistentDataStoreBuilder.StaleWhileRevalidate].
+func (b *PersistentDataStoreBuilder) PreloadCache(preload bool) *PersistentDataStoreBuilder {
+	b.cacheConfig.Preload = preload
+	return b
+}
+
+// PreloadRefreshInterval specifies how often the SDK should read all flags and segments into the cache
+// again, if [PersistentDataStoreBuilder.PreloadCache] is enabled.
+//
+// If the value is zero or negative, the cache is only loaded once, which is the default. Setting an
+// interval shorter than the cache TTL means that cached items are replaced before they expire, so
+// evaluations do not have to wait for the data store. If the cache never expires (see
+// [PersistentDataStoreBuilder.CacheForever]), this is how the SDK sees changes that another process has
+// made to the data store.
+//
+// A cached item is only replaced if the data store has a newer version of it. If the cache never expires,
+// items that are missing from the data store are also kept, since the cache may have updates that could
+// not be written to the data store while it was unavailable.
+func (b *PersistentDataStoreBuilder) PreloadRefreshInterval(interval time.Duration) *PersistentDataStoreBuilder {
+	b.cacheConfig.PreloadRefreshInterval = interval
+	return b
+}
+
 // Build is called internally by the SDK.
 func (b *PersistentDataStoreBuilder) Build(clientContext subsystems.ClientContext) (subsystems.DataStore, error) {
 	core, err := b.persistentDataStoreFactory.Build(clientContext)
 	if err != nil {
 		return nil, err
 	}
-	return datastore.NewPersistentDataStoreWrapper(core, clientContext.GetDataStoreUpdateSink(), b.cacheTTL,
+	return datastore.NewPersistentDataStoreWrapper(core, clientContext.GetDataStoreUpdateSink(), b.cacheConfig,
 		clientContext.GetLogging().Loggers), nil
 }
 
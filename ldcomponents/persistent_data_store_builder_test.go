@@ -55,7 +55,7 @@ func TestPersistentDataStoreBuilder(t *testing.T) {
 		f := PersistentDataStore(pdsf)
 
 		f.CacheTime(time.Hour)
-		assert.Equal(t, time.Hour, f.cacheTTL)
+		assert.Equal(t, time.Hour, f.cacheConfig.TTL)
 	})
 
 	t.Run("CacheSeconds", func(t *testing.T) {
@@ -63,7 +63,7 @@ func TestPersistentDataStoreBuilder(t *testing.T) {
 		f := PersistentDataStore(pdsf)
 
 		f.CacheSeconds(44)
-		assert.Equal(t, 44*time.Second, f.cacheTTL)
+		assert.Equal(t, 44*time.Second, f.cacheConfig.TTL)
 	})
 
 	t.Run("CacheForever", func(t *testing.T) {
@@ -71,7 +71,7 @@ func TestPersistentDataStoreBuilder(t *testing.T) {
 		f := PersistentDataStore(pdsf)
 
 		f.CacheForever()
-		assert.Equal(t, -1*time.Millisecond, f.cacheTTL)
+		assert.Equal(t, -1*time.Millisecond, f.cacheConfig.TTL)
 	})
 
 	t.Run("NoCaching", func(t *testing.T) {
@@ -79,7 +79,52 @@ func TestPersistentDataStoreBuilder(t *testing.T) {
 		f := PersistentDataStore(pdsf)
 
 		f.NoCaching()
-		assert.Equal(t, time.Duration(0), f.cacheTTL)
+		assert.Equal(t, time.Duration(0), f.cacheConfig.TTL)
+	})
+
+	t.Run("NotFoundCacheTime", func(t *testing.T) {
+		pdsf := &mockPersistentDataStoreFactory{}
+		f := PersistentDataStore(pdsf)
+		assert.Equal(t, time.Duration(0), f.cacheConfig.NotFoundTTL)
+
+		f.NotFoundCacheTime(time.Second)
+		assert.Equal(t, time.Second, f.cacheConfig.NotFoundTTL)
+	})
+
+	t.Run("CacheMaxEntries", func(t *testing.T) {
+		pdsf := &mockPersistentDataStoreFactory{}
+		f := PersistentDataStore(pdsf)
+		assert.Equal(t, 0, f.cacheConfig.MaxEntries)
+
+		f.CacheMaxEntries(1000)
+		assert.Equal(t, 1000, f.cacheConfig.MaxEntries)
+	})
+
+	t.Run("StaleWhileRevalidate", func(t *testing.T) {
+		pdsf := &mockPersistentDataStoreFactory{}
+		f := PersistentDataStore(pdsf)
+		assert.False(t, f.cacheConfig.StaleWhileRevalidate)
+
+		f.StaleWhileRevalidate(true)
+		assert.True(t, f.cacheConfig.StaleWhileRevalidate)
+	})
+
+	t.Run("PreloadCache", func(t *testing.T) {
+		pdsf := &mockPersistentDataStoreFactory{}
+		f := PersistentDataStore(pdsf)
+		assert.False(t, f.cacheConfig.Preload)
+
+		f.PreloadCache(true)
+		assert.True(t, f.cacheConfig.Preload)
+	})
+
+	t.Run("PreloadRefreshInterval", func(t *testing.T) {
+		pdsf := &mockPersistentDataStoreFactory{}
+		f := PersistentDataStore(pdsf)
+		assert.Equal(t, time.Duration(0), f.cacheConfig.PreloadRefreshInterval)
+
+		f.PreloadRefreshInterval(time.Minute)
+		assert.Equal(t, time.Minute, f.cacheConfig.PreloadRefreshInterval)
 	})
 
 	t.Run("diagnostic description", func(t *testing.T) {
@@ -75,10 +75,20 @@ func (b *PollingDataSourceBuilderV2) PayloadFilter(filterKey string) *PollingDat
 	return b
 }
 
+// AsInitializer returns a configurer for using a single polling request as a data system initializer.
+// See [DataSystemConfigurationBuilder.Initializers].
+func (b *PollingDataSourceBuilderV2) AsInitializer() subsystems.ComponentConfigurer[subsystems.DataInitializer] {
+	return pollingInitializerBuilderV2{b}
+}
+
 // Build is called internally by the SDK.
 func (b *PollingDataSourceBuilderV2) Build(context subsystems.ClientContext) (subsystems.DataSource, error) {
 	context.GetLogging().Loggers.Warn(
 		"You should only disable the streaming API if instructed to do so by LaunchDarkly support")
+	return b.build(context)
+}
+
+func (b *PollingDataSourceBuilderV2) build(context subsystems.ClientContext) (*datasourcev2.PollingProcessor, error) {
 	filterKey, wasSet := b.filterKey.Get()
 	if wasSet && filterKey == "" {
 		return nil, errors.New("payload filter key cannot be an empty string")
@@ -94,7 +104,7 @@ func (b *PollingDataSourceBuilderV2) Build(context subsystems.ClientContext) (su
 		FilterKey:    filterKey,
 	}
 	return datasourcev2.NewPollingProcessor(context, context.GetDataDestination(),
-		context.GetDataSourceStatusReporter(), cfg), nil
+		context.GetSelectorStore(), context.GetDataSourceStatusReporter(), cfg), nil
 }
 
 // DescribeConfiguration is used internally by the SDK to inspect the configuration.
@@ -107,3 +117,11 @@ func (b *PollingDataSourceBuilderV2) DescribeConfiguration(context subsystems.Cl
 		SetBool("usingRelayDaemon", false).
 		Build()
 }
+
+type pollingInitializerBuilderV2 struct {
+	builder *PollingDataSourceBuilderV2
+}
+
+func (b pollingInitializerBuilderV2) Build(context subsystems.ClientContext) (subsystems.DataInitializer, error) {
+	return b.builder.build(context)
+}
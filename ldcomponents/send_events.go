@@ -1,7 +1,8 @@
 package ldcomponents
 
 import (
-	"net/http"
+	"io"
+	"math"
 	"time"
 
 	"github.com/launchdarkly/go-sdk-common/v3/ldattr"
@@ -29,6 +30,9 @@ const (
 	MinimumDiagnosticRecordingInterval = 60 * time.Second
 )
 
+// neverFlushInterval is the flush interval of an event processor that is flushed by an EventFlushScheduler.
+const neverFlushInterval = time.Duration(math.MaxInt64)
+
 // EventProcessorBuilder provides methods for configuring analytics event behavior.
 //
 // See [SendEvents] for usage.
@@ -43,6 +47,7 @@ type EventProcessorBuilder struct {
 	contextKeysFlushInterval    time.Duration
 	omitAnonymousContexts       bool
 	enableGzip                  bool
+	eventSender                 subsystems.ComponentConfigurer[ldevents.EventSender]
 }
 
 // SendEvents returns a configuration builder for analytics event delivery.
@@ -72,23 +77,15 @@ func (b *EventProcessorBuilder) Build(
 ) (ldevents.EventProcessor, error) {
 	loggers := context.GetLogging().Loggers
 
-	configuredBaseURI := endpoints.SelectBaseURI(
-		context.GetServiceEndpoints(),
-		endpoints.EventsService,
-		loggers,
-	)
-
-	headers := context.GetHTTP().DefaultHeaders
-	eventSender := ldevents.NewServerSideEventSender(
-		ldevents.EventSenderConfiguration{
-			Client:            context.GetHTTP().CreateHTTPClient(),
-			BaseURI:           configuredBaseURI,
-			BaseHeaders:       func() http.Header { return headers },
-			Loggers:           loggers,
-			EnableCompression: b.enableGzip,
-		},
-		context.GetSDKKey(),
-	)
+	senderFactory := b.eventSender
+	if senderFactory == nil {
+		senderFactory = LaunchDarklyEventSender().EnableGzip(b.enableGzip)
+	}
+	eventSender, err := senderFactory.Build(context)
+	if err != nil {
+		return nil, err
+	}
+
 	eventsConfig := ldevents.EventsConfiguration{
 		AllAttributesPrivate:        b.allAttributesPrivate,
 		Capacity:                    b.capacity,
@@ -102,10 +99,55 @@ func (b *EventProcessorBuilder) Build(
 		UserKeysFlushInterval:       b.contextKeysFlushInterval,
 		OmitAnonymousContexts:       b.omitAnonymousContexts,
 	}
+	var flushScheduler *internal.EventFlushScheduler
 	if cci, ok := context.(*internal.ClientContextImpl); ok {
 		eventsConfig.DiagnosticsManager = cci.DiagnosticsManager
+		flushScheduler = cci.EventFlushScheduler
+	}
+	if flushScheduler != nil {
+		// The processor is flushed on the shared schedule instead. ldevents always runs a flush timer, so
+		// the closest it can come to having none is a timer that never fires.
+		eventsConfig.FlushInterval = neverFlushInterval
+	}
+	var processor ldevents.EventProcessor = ldevents.NewDefaultEventProcessor(eventsConfig)
+	if closer, ok := eventSender.(io.Closer); ok {
+		processor = &eventProcessorWithSenderCloser{EventProcessor: processor, sender: closer}
+	}
+	if flushScheduler != nil {
+		flushInterval := b.flushInterval
+		if flushInterval <= 0 {
+			flushInterval = DefaultFlushInterval
+		}
+		unschedule := flushScheduler.Add(processor, flushInterval)
+		processor = &scheduledEventProcessor{EventProcessor: processor, unschedule: unschedule}
 	}
-	return ldevents.NewDefaultEventProcessor(eventsConfig), nil
+	return processor, nil
+}
+
+// scheduledEventProcessor stops flushing an event processor on a shared schedule before it is closed.
+type scheduledEventProcessor struct {
+	ldevents.EventProcessor
+	unschedule func()
+}
+
+func (p *scheduledEventProcessor) Close() error { //nolint:revive // no doc comment for standard method
+	p.unschedule()
+	return p.EventProcessor.Close()
+}
+
+// eventProcessorWithSenderCloser closes the event sender once the event processor has delivered its last
+// payload, for senders that have their own resources or background work.
+type eventProcessorWithSenderCloser struct {
+	ldevents.EventProcessor
+	sender io.Closer
+}
+
+func (p *eventProcessorWithSenderCloser) Close() error { //nolint:revive // no doc comment for standard method
+	err := p.EventProcessor.Close()
+	if senderErr := p.sender.Close(); err == nil {
+		err = senderErr
+	}
+	return err
 }
 
 // AllAttributesPrivate sets whether or not all optional context attributes should be hidden from LaunchDarkly.
@@ -213,12 +255,42 @@ func (b *EventProcessorBuilder) OmitAnonymousContexts(omitAnonymousContexts bool
 
 // EnableGzip sets whether event payloads should be compressed prior to being sent to LaunchDarkly.
 //
-// The default value is false.
+// The default value is false. This setting is ignored if you specify a different destination with
+// [EventProcessorBuilder.EventSender].
 func (b *EventProcessorBuilder) EnableGzip(enableGzip bool) *EventProcessorBuilder {
 	b.enableGzip = enableGzip
 	return b
 }
 
+// EventSender specifies a component that delivers analytics event payloads, instead of sending them to
+// LaunchDarkly.
+//
+// The SDK still does all of the same processing of events, including summarizing evaluations,
+// generating index events, and removing private attributes; it then passes each batch of events to
+// the [ldevents.EventSender] as a JSON array, in the same format that would be sent to LaunchDarkly, with
+// the kind [ldevents.AnalyticsEventDataKind]. Unless Config.DiagnosticOptOut is set, it also passes
+// diagnostic payloads with the kind [ldevents.DiagnosticEventDataKind], which a custom sender can ignore.
+// The sender is called from a background goroutine, which can block while the payload is delivered. If it
+// also implements [io.Closer], it is closed when the SDK client is closed, after the last payload has been
+// passed to it.
+//
+// To send events to LaunchDarkly as well as to your own destination, use [TeeEventSender] with
+// [LaunchDarklyEventSender]:
+//
+//	config := ld.Config{
+//	    Events: ldcomponents.SendEvents().EventSender(
+//	        ldcomponents.TeeEventSender(ldcomponents.LaunchDarklyEventSender(), myWarehouseSender),
+//	    ),
+//	}
+//
+// Passing nil restores the default behavior of sending events to LaunchDarkly.
+func (b *EventProcessorBuilder) EventSender(
+	eventSender subsystems.ComponentConfigurer[ldevents.EventSender],
+) *EventProcessorBuilder {
+	b.eventSender = eventSender
+	return b
+}
+
 // DescribeConfiguration is used internally by the SDK to inspect the configuration.
 func (b *EventProcessorBuilder) DescribeConfiguration(context subsystems.ClientContext) ldvalue.Value {
 	return ldvalue.ObjectBuild().
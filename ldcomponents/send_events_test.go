@@ -11,8 +11,11 @@ import (
 	"github.com/launchdarkly/go-sdk-common/v3/lduser"
 	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
 	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/internal"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
 	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldservices"
 
+	th "github.com/launchdarkly/go-test-helpers/v3"
 	"github.com/launchdarkly/go-test-helpers/v3/httphelpers"
 	m "github.com/launchdarkly/go-test-helpers/v3/matchers"
 
@@ -250,3 +253,39 @@ func TestEventsOmitAnonymousContexts(t *testing.T) {
 		))
 	})
 }
+
+func TestEventsFlushedBySharedScheduler(t *testing.T) {
+	makeProcessor := func(t *testing.T, scheduler *internal.EventFlushScheduler) *capturingEventSender {
+		sender := newCapturingEventSender(ldevents.EventSenderResult{Success: true})
+		ep, err := SendEvents().
+			EventSender(sender.configurer()).
+			FlushInterval(time.Millisecond * 10).
+			Build(&internal.ClientContextImpl{
+				BasicClientContext:  sharedtest.NewTestContext(testSdkKey, nil, nil),
+				EventFlushScheduler: scheduler,
+			})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ep.Close() })
+
+		ef := ldevents.NewEventFactory(false, nil)
+		ep.RecordIdentifyEvent(ef.NewIdentifyEventData(ldevents.Context(lduser.NewUser("key")), ldvalue.OptionalInt{}))
+		return sender
+	}
+
+	t.Run("flushes at the configured interval", func(t *testing.T) {
+		scheduler := internal.NewEventFlushScheduler()
+		defer scheduler.Close()
+		sender := makeProcessor(t, scheduler)
+
+		payload := th.RequireValue(t, sender.payloads, time.Second)
+		assert.Equal(t, ldevents.AnalyticsEventDataKind, payload.kind)
+	})
+
+	t.Run("event processor does not flush on its own timer", func(t *testing.T) {
+		scheduler := internal.NewEventFlushScheduler()
+		scheduler.Close()
+		sender := makeProcessor(t, scheduler)
+
+		th.AssertNoMoreValues(t, sender.payloads, time.Millisecond*100)
+	})
+}
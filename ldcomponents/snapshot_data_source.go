@@ -0,0 +1,175 @@
+package ldcomponents
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+)
+
+// BootstrapFromSnapshot returns a configurable data source that loads flag data from a snapshot file when the
+// SDK starts, and then starts another data source to get data from LaunchDarkly as usual.
+//
+// The snapshot file is created with [github.com/launchdarkly/go-server-sdk/v7.LDClient.WriteSnapshot]. Until the
+// other data source has received data, the SDK evaluates flags using the snapshot, in the same way as it would
+// use data from a persistent data store; so if LaunchDarkly is unreachable when the application starts, it can
+// still serve last-known-good values instead of application defaults. Once the other data source receives
+// data, the snapshot data is replaced.
+//
+// If the file cannot be read, or is not a valid snapshot, the SDK logs a warning and starts the other data
+// source without it. If dataSource is nil, the default [StreamingDataSource] is used.
+//
+//	config := ld.Config{
+//	    DataSource: ldcomponents.BootstrapFromSnapshot("./flags-snapshot.json", nil),
+//	}
+//
+// This should not be used with a persistent data store, since the snapshot would overwrite the data in the
+// store, which may be newer. To use a snapshot with the FDv2 data system, see [SnapshotInitializer].
+func BootstrapFromSnapshot(
+	filePath string,
+	dataSource subsystems.ComponentConfigurer[subsystems.DataSource],
+) subsystems.ComponentConfigurer[subsystems.DataSource] {
+	return snapshotBootstrapFactory{filePath: filePath, dataSource: dataSource}
+}
+
+// SnapshotInitializer returns a configurer for a data system initializer that loads flag data from a snapshot
+// file that was created with [github.com/launchdarkly/go-server-sdk/v7.LDClient.WriteSnapshot]. See
+// [DataSystemConfigurationBuilder.Initializers].
+//
+// This initializer is not stable, and not subject to any backwards
+// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+//
+// Since the first initializer that obtains data wins, the snapshot initializer is normally listed last, so
+// that it is only used if LaunchDarkly cannot be reached:
+//
+//	config.DataSystem = ldcomponents.DataSystem().
+//	    Initializers(ldcomponents.PollingDataSourceV2().AsInitializer(),
+//	        ldcomponents.SnapshotInitializer("./flags-snapshot.json"))
+//
+// As with [BootstrapFromSnapshot], data from a snapshot is considered cached rather than up to date, since it
+// may be arbitrarily old: until a synchronizer has received data from LaunchDarkly, the SDK logs a warning
+// when it evaluates flags, and [github.com/launchdarkly/go-server-sdk/v7.MakeClient] waits for the
+// synchronizer rather than returning as soon as the snapshot is loaded. Data from a snapshot is never written
+// to a persistent store.
+func SnapshotInitializer(filePath string) subsystems.ComponentConfigurer[subsystems.DataInitializer] {
+	return snapshotInitializerFactory{filePath: filePath}
+}
+
+func readSnapshotFile(filePath string) ([]ldstoretypes.Collection, error) {
+	file, err := os.Open(filePath) //nolint:gosec // the file path is provided by the application
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return ldstoreimpl.ReadSnapshot(file)
+}
+
+type snapshotBootstrapFactory struct {
+	filePath   string
+	dataSource subsystems.ComponentConfigurer[subsystems.DataSource]
+}
+
+func (f snapshotBootstrapFactory) Build(context subsystems.ClientContext) (subsystems.DataSource, error) {
+	factory := f.dataSource
+	if factory == nil {
+		factory = StreamingDataSource()
+	}
+	dataSource, err := factory.Build(context)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotBootstrapDataSource{
+		filePath:       f.filePath,
+		dataSource:     dataSource,
+		dataSourceSink: context.GetDataSourceUpdateSink(),
+		loggers:        context.GetLogging().Loggers,
+	}, nil
+}
+
+// DiagnosticDescription implementation
+func (f snapshotBootstrapFactory) DescribeConfiguration(context subsystems.ClientContext) ldvalue.Value {
+	factory := f.dataSource
+	if factory == nil {
+		factory = StreamingDataSource()
+	}
+	if dd, ok := factory.(subsystems.DiagnosticDescription); ok {
+		return dd.DescribeConfiguration(context)
+	}
+	return ldvalue.Null()
+}
+
+type snapshotBootstrapDataSource struct {
+	filePath       string
+	dataSource     subsystems.DataSource
+	dataSourceSink subsystems.DataSourceUpdateSink
+	loggers        ldlog.Loggers
+}
+
+func (s *snapshotBootstrapDataSource) IsInitialized() bool {
+	return s.dataSource.IsInitialized()
+}
+
+func (s *snapshotBootstrapDataSource) Start(closeWhenReady chan<- struct{}) {
+	if allData, err := readSnapshotFile(s.filePath); err != nil {
+		s.loggers.Warnf("Unable to load flag data snapshot from %s: %s", s.filePath, err)
+	} else if s.dataSourceSink.Init(allData) {
+		s.loggers.Infof("Loaded flag data snapshot from %s", s.filePath)
+	}
+	s.dataSource.Start(closeWhenReady)
+}
+
+func (s *snapshotBootstrapDataSource) Close() error {
+	return s.dataSource.Close()
+}
+
+type snapshotInitializerFactory struct {
+	filePath string
+}
+
+func (f snapshotInitializerFactory) Build(context subsystems.ClientContext) (subsystems.DataInitializer, error) {
+	return snapshotInitializer{filePath: f.filePath}, nil
+}
+
+type snapshotInitializer struct {
+	filePath string
+}
+
+func (s snapshotInitializer) Name() string {
+	return "SnapshotInitializer"
+}
+
+func (s snapshotInitializer) Fetch(ctx context.Context) (*subsystems.Basis, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	allData, err := readSnapshotFile(s.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load flag data snapshot from %s: %w", s.filePath, err)
+	}
+	var events []fdv2proto.Event
+	for _, coll := range allData {
+		kind := fdv2proto.FlagKind
+		if coll.Kind == datakinds.Segments {
+			kind = fdv2proto.SegmentKind
+		}
+		for _, item := range coll.Items {
+			events = append(events, fdv2proto.PutObject{
+				Version: item.Item.Version,
+				Kind:    kind,
+				Key:     item.Key,
+				Object:  item.Item.Item,
+			})
+		}
+	}
+	return &subsystems.Basis{Events: events}, nil
+}
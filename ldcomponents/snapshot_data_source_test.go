@@ -0,0 +1,107 @@
+package ldcomponents
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-sdk-common/v3/ldlogtest"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datastore"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeSnapshotFile(t *testing.T) (string, []ldstoretypes.Collection) {
+	flag := ldbuilders.NewFlagBuilder("flag1").Version(1).On(true).Variations(ldvalue.Bool(true)).Build()
+	segment := ldbuilders.NewSegmentBuilder("segment1").Version(2).Build()
+	data := sharedtest.NewDataSetBuilder().Flags(flag).Segments(segment).Build()
+
+	store := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+	require.NoError(t, store.Init(data))
+	filePath := filepath.Join(t.TempDir(), "snapshot.json")
+	file, err := os.Create(filePath)
+	require.NoError(t, err)
+	require.NoError(t, ldstoreimpl.WriteSnapshot(file, store))
+	require.NoError(t, file.Close())
+	return filePath, data
+}
+
+func TestBootstrapFromSnapshot(t *testing.T) {
+	t.Run("loads snapshot before starting data source", func(t *testing.T) {
+		filePath, data := makeSnapshotFile(t)
+		store := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+		dsu := mocks.NewMockDataSourceUpdates(store)
+		context := subsystems.BasicClientContext{DataSourceUpdateSink: dsu, Logging: sharedtest.TestLoggingConfig()}
+		ds, err := BootstrapFromSnapshot(filePath, mocks.DataSourceThatNeverInitializes()).Build(context)
+		require.NoError(t, err)
+		defer ds.Close()
+		ds.Start(make(chan struct{}))
+
+		assert.False(t, ds.IsInitialized())
+		assert.True(t, store.IsInitialized())
+		allData, err := store.GetAll(data[0].Kind)
+		require.NoError(t, err)
+		assert.Equal(t, data[0].Items, allData)
+	})
+
+	t.Run("starts data source without snapshot if file cannot be read", func(t *testing.T) {
+		mockLog := ldlogtest.NewMockLog()
+		store := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+		dsu := mocks.NewMockDataSourceUpdates(store)
+		context := subsystems.BasicClientContext{
+			DataSourceUpdateSink: dsu,
+			Logging:              subsystems.LoggingConfiguration{Loggers: mockLog.Loggers},
+		}
+
+		ds, err := BootstrapFromSnapshot(filepath.Join(t.TempDir(), "missing.json"),
+			mocks.DataSourceThatIsAlwaysInitialized()).Build(context)
+		require.NoError(t, err)
+		defer ds.Close()
+		ds.Start(make(chan struct{}, 1))
+
+		assert.True(t, ds.IsInitialized())
+		assert.False(t, store.IsInitialized())
+		mockLog.AssertMessageMatch(t, true, ldlog.Warn, "Unable to load flag data snapshot")
+	})
+}
+
+func TestSnapshotInitializer(t *testing.T) {
+	t.Run("fetches snapshot data", func(t *testing.T) {
+		filePath, _ := makeSnapshotFile(t)
+		initializer, err := SnapshotInitializer(filePath).Build(basicClientContext())
+		require.NoError(t, err)
+
+		basis, err := initializer.Fetch(context.Background())
+		require.NoError(t, err)
+
+		assert.False(t, basis.Persist)
+		assert.Nil(t, basis.Selector)
+		require.Len(t, basis.Events, 2)
+		flagEvent := basis.Events[0].(fdv2proto.PutObject)
+		assert.Equal(t, fdv2proto.FlagKind, flagEvent.Kind)
+		assert.Equal(t, "flag1", flagEvent.Key)
+		assert.Equal(t, 1, flagEvent.Version)
+		segmentEvent := basis.Events[1].(fdv2proto.PutObject)
+		assert.Equal(t, fdv2proto.SegmentKind, segmentEvent.Kind)
+		assert.Equal(t, "segment1", segmentEvent.Key)
+	})
+
+	t.Run("returns error if file cannot be read", func(t *testing.T) {
+		initializer, err := SnapshotInitializer(filepath.Join(t.TempDir(), "missing.json")).Build(basicClientContext())
+		require.NoError(t, err)
+
+		_, err = initializer.Fetch(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -89,6 +89,7 @@ func (b *StreamingDataSourceBuilderV2) Build(context subsystems.ClientContext) (
 	return datasourcev2.NewStreamProcessor(
 		context,
 		context.GetDataDestination(),
+		context.GetSelectorStore(),
 		context.GetDataSourceStatusReporter(),
 		cfg,
 	), nil
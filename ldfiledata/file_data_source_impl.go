@@ -248,6 +248,11 @@ func makeFlagWithValue(key string, v interface{}) *ldmodel.FeatureFlag {
 	return &flag
 }
 
+// Name returns the name that the SDK reports for this data source in flag change events.
+func (fs *fileDataSource) Name() string {
+	return "FileDataSource"
+}
+
 // Close is called automatically when the client is closed.
 func (fs *fileDataSource) Close() (err error) {
 	fs.closeOnce.Do(func() {
@@ -0,0 +1,74 @@
+package ldfilestore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+// DefaultPrefix is the string that is used as the name of the data subdirectory if no other prefix is
+// specified.
+const DefaultPrefix = "launchdarkly"
+
+// DataStoreBuilder is a builder for configuring the file-based persistent data store.
+//
+// Obtain an instance of this type by calling [DataStore]. After calling its methods to specify any desired
+// custom settings, wrap it in a PersistentDataStoreBuilder by calling
+// [github.com/launchdarkly/go-server-sdk/v7/ldcomponents.PersistentDataStore], and then store this in the
+// SDK configuration's DataStore field.
+//
+// Builder calls can be chained, for example:
+//
+//	config.DataStore = ldcomponents.PersistentDataStore(
+//	    ldfilestore.DataStore().Directory("/var/lib/my-app").Prefix("my-env"),
+//	)
+//
+// You do not need to call the builder's Build method yourself; that will be done by the SDK.
+type DataStoreBuilder struct {
+	directory string
+	prefix    string
+}
+
+// DataStore returns a configurable builder for a file-based persistent data store.
+//
+// A directory must be specified with [DataStoreBuilder.Directory].
+func DataStore() *DataStoreBuilder {
+	return &DataStoreBuilder{prefix: DefaultPrefix}
+}
+
+// Directory specifies the directory in which the data store keeps its files. The directory is created
+// when data is first written, if it does not already exist.
+func (b *DataStoreBuilder) Directory(directory string) *DataStoreBuilder {
+	b.directory = directory
+	return b
+}
+
+// Prefix specifies a name that distinguishes this SDK instance's data from that of any others that use
+// the same directory. It is used as the name of a subdirectory, so it cannot be "." or "..". If not
+// specified, the default is [DefaultPrefix].
+func (b *DataStoreBuilder) Prefix(prefix string) *DataStoreBuilder {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	b.prefix = prefix
+	return b
+}
+
+// Build is called internally by the SDK.
+func (b *DataStoreBuilder) Build(context subsystems.ClientContext) (subsystems.PersistentDataStore, error) {
+	if b.directory == "" {
+		return nil, errors.New("a directory must be specified for the file data store")
+	}
+	if b.prefix == "." || b.prefix == ".." {
+		// These would make the store use, and delete files from, the directory itself or its parent.
+		return nil, fmt.Errorf("%q is not a valid prefix for the file data store", b.prefix)
+	}
+	return newFileDataStoreImpl(b.directory, b.prefix, context.GetLogging().Loggers), nil
+}
+
+// DescribeConfiguration is used internally by the SDK to inspect the configuration.
+func (b *DataStoreBuilder) DescribeConfiguration(context subsystems.ClientContext) ldvalue.Value {
+	return ldvalue.String("File")
+}
@@ -0,0 +1,306 @@
+package ldfilestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldlog"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+)
+
+// Internal implementation of the PersistentDataStore interface for the local file system.
+//
+// Each prefix has its own subdirectory, which contains:
+//
+// - One file per data kind, named for the kind with a ".json" suffix. It contains a JSON object whose "items"
+// property maps each key to its version, deleted state, and serialized item.
+//
+// - A file called "$inited", whose existence indicates that the store has been initialized.
+//
+// - A file called ".lock", which is used for an advisory lock that is held while reading or writing. It is
+// created by the first write; readers do without it if it does not exist or cannot be opened.
+//
+// Files are never modified in place: a new version is written to a temporary file in the same directory,
+// which is then renamed over the old one. Readers take a shared lock and writers take an exclusive lock.
+// The same is done with an in-process mutex, which is the only coordination on platforms where file locking
+// is not implemented.
+
+const (
+	lockFileName   = ".lock"
+	initedFileName = "$inited"
+	dataFileSuffix = ".json"
+	tempFilePrefix = ".tmp-"
+
+	dirPermissions  = 0o750
+	filePermissions = 0o600
+)
+
+type fileDataStoreImpl struct {
+	dir        string
+	loggers    ldlog.Loggers
+	lock       sync.RWMutex
+	testTxHook func()
+}
+
+type kindFileData struct {
+	Items map[string]storedItem `json:"items"`
+}
+
+type storedItem struct {
+	Version int     `json:"version"`
+	Deleted bool    `json:"deleted,omitempty"`
+	Item    *string `json:"item,omitempty"`
+}
+
+func newFileDataStoreImpl(directory, prefix string, loggers ldlog.Loggers) *fileDataStoreImpl {
+	dir := filepath.Join(directory, url.PathEscape(prefix))
+	loggers.Infof("FileDataStore: Using directory %s", dir)
+	return &fileDataStoreImpl{dir: dir, loggers: loggers}
+}
+
+func (store *fileDataStoreImpl) Init(allData []ldstoretypes.SerializedCollection) error {
+	return store.withLock(true, func() error {
+		written := make(map[string]bool)
+		for _, coll := range allData {
+			data := kindFileData{Items: make(map[string]storedItem, len(coll.Items))}
+			for _, item := range coll.Items {
+				data.Items[item.Key] = toStoredItem(item.Item)
+			}
+			fileName := kindFileName(coll.Kind)
+			if err := store.writeKindFile(fileName, data); err != nil {
+				return err
+			}
+			written[fileName] = true
+		}
+		// Remove any data for kinds that are no longer present, so that the new data completely replaces the old.
+		entries, err := os.ReadDir(store.dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if name := entry.Name(); strings.HasSuffix(name, dataFileSuffix) && !written[name] {
+				if err := os.Remove(filepath.Join(store.dir, name)); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
+		return writeFileAtomically(store.dir, initedFileName, nil)
+	})
+}
+
+func (store *fileDataStoreImpl) Get(
+	kind ldstoretypes.DataKind,
+	key string,
+) (ldstoretypes.SerializedItemDescriptor, error) {
+	var data kindFileData
+	err := store.withLock(false, func() (err error) {
+		data, err = store.readKindFile(kindFileName(kind))
+		return err
+	})
+	if err != nil {
+		return ldstoretypes.SerializedItemDescriptor{}.NotFound(), err
+	}
+	item, ok := data.Items[key]
+	if !ok {
+		if store.loggers.IsDebugEnabled() {
+			store.loggers.Debugf("FileDataStore: Key: %s not found in \"%s\"", key, kind.GetName())
+		}
+		return ldstoretypes.SerializedItemDescriptor{}.NotFound(), nil
+	}
+	return item.toDescriptor(), nil
+}
+
+func (store *fileDataStoreImpl) GetAll(
+	kind ldstoretypes.DataKind,
+) ([]ldstoretypes.KeyedSerializedItemDescriptor, error) {
+	var data kindFileData
+	err := store.withLock(false, func() (err error) {
+		data, err = store.readKindFile(kindFileName(kind))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]ldstoretypes.KeyedSerializedItemDescriptor, 0, len(data.Items))
+	for key, item := range data.Items {
+		results = append(results, ldstoretypes.KeyedSerializedItemDescriptor{Key: key, Item: item.toDescriptor()})
+	}
+	return results, nil
+}
+
+func (store *fileDataStoreImpl) Upsert(
+	kind ldstoretypes.DataKind,
+	key string,
+	newItem ldstoretypes.SerializedItemDescriptor,
+) (bool, error) {
+	fileName := kindFileName(kind)
+
+	// Most outdated updates can be rejected while holding only a shared lock. The version has to be checked
+	// again once we have the exclusive lock, since another process may have written in between.
+	oldItem, err := store.Get(kind, key)
+	if err != nil {
+		return false, err
+	}
+	if oldItem.Version >= newItem.Version {
+		store.logUpsertRejected(kind, key, oldItem.Version, newItem.Version)
+		return false, nil
+	}
+
+	if store.testTxHook != nil { // instrumentation for unit tests
+		store.testTxHook()
+	}
+
+	updated := false
+	err = store.withLock(true, func() error {
+		data, err := store.readKindFile(fileName)
+		if err != nil {
+			return err
+		}
+		if old, ok := data.Items[key]; ok && old.Version >= newItem.Version {
+			store.logUpsertRejected(kind, key, old.Version, newItem.Version)
+			return nil
+		}
+		data.Items[key] = toStoredItem(newItem)
+		if err := store.writeKindFile(fileName, data); err != nil {
+			return err
+		}
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+func (store *fileDataStoreImpl) IsInitialized() bool {
+	_, err := os.Stat(filepath.Join(store.dir, initedFileName))
+	return err == nil
+}
+
+func (store *fileDataStoreImpl) IsStoreAvailable() bool {
+	// The subdirectory does not exist until something has been written, but its parent must be accessible.
+	_, err := os.Stat(store.dir)
+	return err == nil || os.IsNotExist(err)
+}
+
+func (store *fileDataStoreImpl) Close() error {
+	return nil
+}
+
+func (store *fileDataStoreImpl) logUpsertRejected(kind ldstoretypes.DataKind, key string, oldVersion, newVersion int) {
+	if store.loggers.IsDebugEnabled() {
+		store.loggers.Debugf(`FileDataStore: Attempted to update key: %s version: %d in "%s" with a version `+
+			"that is the same or older: %d", key, oldVersion, kind.GetName(), newVersion)
+	}
+}
+
+// withLock runs an action while holding the in-process lock and the file lock, either exclusively (for
+// writing) or shared (for reading).
+//
+// A reader opens the lock file read-only, and if it cannot do that, it reads without a file lock: the
+// subdirectory might not exist yet, or it might be on a read-only file system that was populated ahead of
+// time. That is safe because files are only ever replaced by renaming, so a reader never sees a partly
+// written file; the lock only ensures that a reader sees all of the files from the same update.
+func (store *fileDataStoreImpl) withLock(exclusive bool, action func() error) error {
+	lockPath := filepath.Join(store.dir, lockFileName)
+	var lockFile *os.File
+	if exclusive {
+		store.lock.Lock()
+		defer store.lock.Unlock()
+		if err := os.MkdirAll(store.dir, dirPermissions); err != nil {
+			return err
+		}
+		var err error
+		if lockFile, err = os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, filePermissions); err != nil {
+			return err
+		}
+	} else {
+		store.lock.RLock()
+		defer store.lock.RUnlock()
+		var err error
+		if lockFile, err = os.Open(lockPath); err != nil { //nolint:gosec // the path is not user input
+			return action()
+		}
+	}
+	defer func() { _ = lockFile.Close() }()
+	if err := lockFileHandle(lockFile, exclusive); err != nil {
+		return fmt.Errorf("unable to lock %s: %w", lockFile.Name(), err)
+	}
+	defer func() { _ = unlockFileHandle(lockFile) }()
+	return action()
+}
+
+func (store *fileDataStoreImpl) readKindFile(fileName string) (kindFileData, error) {
+	data := kindFileData{Items: make(map[string]storedItem)}
+	bytes, err := os.ReadFile(filepath.Join(store.dir, fileName)) //nolint:gosec // the path is not user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return data, nil
+		}
+		return data, err
+	}
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return data, fmt.Errorf("malformed data in %s: %w", fileName, err)
+	}
+	if data.Items == nil {
+		data.Items = make(map[string]storedItem)
+	}
+	return data, nil
+}
+
+func (store *fileDataStoreImpl) writeKindFile(fileName string, data kindFileData) error {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(store.dir, fileName, bytes)
+}
+
+// writeFileAtomically writes the data to a temporary file in the same directory, flushes it to disk, and
+// then renames it to the target name, so that the target file always has either its old or its new content.
+func writeFileAtomically(dir, fileName string, data []byte) error {
+	tempFile, err := os.CreateTemp(dir, tempFilePrefix+fileName+"-*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	_, err = tempFile.Write(data)
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempPath, filepath.Join(dir, fileName))
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+func kindFileName(kind ldstoretypes.DataKind) string {
+	return url.PathEscape(kind.GetName()) + dataFileSuffix
+}
+
+func toStoredItem(item ldstoretypes.SerializedItemDescriptor) storedItem {
+	ret := storedItem{Version: item.Version, Deleted: item.Deleted}
+	if item.SerializedItem != nil {
+		s := string(item.SerializedItem)
+		ret.Item = &s
+	}
+	return ret
+}
+
+func (item storedItem) toDescriptor() ldstoretypes.SerializedItemDescriptor {
+	ret := ldstoretypes.SerializedItemDescriptor{Version: item.Version, Deleted: item.Deleted}
+	if item.Item != nil {
+		ret.SerializedItem = []byte(*item.Item)
+	}
+	return ret
+}
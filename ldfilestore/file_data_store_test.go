@@ -0,0 +1,116 @@
+package ldfilestore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/storetest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDataStore(t *testing.T) {
+	dir := t.TempDir()
+
+	notADirectory := filepath.Join(dir, "not-a-directory")
+	require.NoError(t, os.WriteFile(notADirectory, []byte("x"), 0o600))
+
+	storetest.NewPersistentDataStoreTestSuite(
+		func(prefix string) subsystems.ComponentConfigurer[subsystems.PersistentDataStore] {
+			return DataStore().Directory(dir).Prefix(prefix)
+		},
+		func(prefix string) error {
+			if prefix == "" {
+				prefix = DefaultPrefix
+			}
+			return os.RemoveAll(filepath.Join(dir, prefix))
+		},
+	).ErrorStoreFactory(
+		DataStore().Directory(notADirectory),
+		nil,
+	).ConcurrentModificationHook(
+		func(store subsystems.PersistentDataStore, hook func()) {
+			store.(*fileDataStoreImpl).testTxHook = hook
+		},
+	).Run(t)
+}
+
+func TestFileDataStoreRequiresDirectory(t *testing.T) {
+	_, err := DataStore().Build(sharedtest.NewSimpleTestContext(""))
+	assert.Error(t, err)
+}
+
+func TestFileDataStoreRejectsPrefixThatIsNotASubdirectory(t *testing.T) {
+	for _, prefix := range []string{".", ".."} {
+		t.Run(prefix, func(t *testing.T) {
+			_, err := DataStore().Directory(t.TempDir()).Prefix(prefix).Build(sharedtest.NewSimpleTestContext(""))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFileDataStoreWritesNoTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := DataStore().Directory(dir).Build(sharedtest.NewSimpleTestContext(""))
+	require.NoError(t, err)
+	defer store.Close()
+
+	item := mocks.MockDataItem{Key: "flag", Version: 1}
+	require.NoError(t, store.Init(mocks.MakeSerializedMockDataSet(item)))
+	updated, err := store.Upsert(mocks.MockData, item.Key, mocks.MockDataItem{Key: "flag", Version: 2}.
+		ToSerializedItemDescriptor())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	entries, err := os.ReadDir(filepath.Join(dir, DefaultPrefix))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{lockFileName, initedFileName, "mock1.json", "mock2.json"}, names)
+}
+
+func TestFileDataStoreReturnsErrorForMalformedFile(t *testing.T) {
+	dir := t.TempDir()
+	store, err := DataStore().Directory(dir).Build(sharedtest.NewSimpleTestContext(""))
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, DefaultPrefix), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DefaultPrefix, "mock1.json"), []byte("{no"), 0o600))
+
+	_, err = store.Get(mocks.MockData, "flag")
+	assert.ErrorContains(t, err, "malformed data in mock1.json")
+}
+
+func TestFileDataStoreCanBeReadFromReadOnlyDirectory(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("file permissions are not enforced for root")
+	}
+	dir := t.TempDir()
+	store, err := DataStore().Directory(dir).Build(sharedtest.NewSimpleTestContext(""))
+	require.NoError(t, err)
+	defer store.Close()
+
+	item := mocks.MockDataItem{Key: "flag", Version: 1}
+	require.NoError(t, store.Init(mocks.MakeSerializedMockDataSet(item)))
+
+	// This is like a directory that was populated ahead of time and then deployed without the lock file.
+	subdir := filepath.Join(dir, DefaultPrefix)
+	require.NoError(t, os.Remove(filepath.Join(subdir, lockFileName)))
+	require.NoError(t, os.Chmod(subdir, 0o500))
+	defer func() { _ = os.Chmod(subdir, 0o700) }()
+
+	result, err := store.Get(mocks.MockData, "flag")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Version)
+	items, err := store.GetAll(mocks.MockData)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+}
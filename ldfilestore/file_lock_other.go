@@ -0,0 +1,17 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package ldfilestore
+
+import "os"
+
+// On platforms without flock, access is only coordinated within a single store instance, by its own mutex.
+// Separate store instances that use the same directory, whether in the same process or not, are not
+// coordinated.
+
+func lockFileHandle(file *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlockFileHandle(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,30 @@
+// Package ldfilestore provides a persistent data store for the LaunchDarkly Go SDK that keeps flag data in
+// files on the local file system.
+//
+// This allows an application to retain the last known flag data across restarts, or to share it between
+// several processes on the same host, without running a database. To use it, call ldfilestore.[DataStore]
+// and pass the result to [github.com/launchdarkly/go-server-sdk/v7/ldcomponents.PersistentDataStore], which
+// adds an in-memory cache in front of the file store:
+//
+//	config := ld.Config{
+//	    DataStore: ldcomponents.PersistentDataStore(
+//	        ldfilestore.DataStore().Directory("/var/lib/my-app/launchdarkly"),
+//	    ).CacheSeconds(30),
+//	}
+//
+// Within the configured directory, each prefix has its own subdirectory containing one JSON file per
+// kind of data (flags, segments). Every update is written to a temporary file which then replaces the
+// original with an atomic rename, so a reader never sees a partially written file. On Unix-like systems,
+// access is also coordinated between store instances and processes with an advisory lock on a ".lock" file
+// in the same subdirectory. On other platforms, only the operations of a single store instance are
+// coordinated with each other; separate store instances that use the same directory are not, even in the
+// same process.
+//
+// The store does not keep any data in memory. Every Get or GetAll call reads and parses the whole file for
+// that kind of data, and every update rewrites it, so the cost of each call grows with the number of flags
+// or segments. Use the in-memory cache of [github.com/launchdarkly/go-server-sdk/v7/ldcomponents.PersistentDataStore]
+// so that most evaluations do not need to read the files.
+//
+// These files are an implementation detail of the store, and their format may change; applications should
+// not read or modify them directly.
+package ldfilestore
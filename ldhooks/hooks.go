@@ -3,6 +3,7 @@ package ldhooks
 import (
 	"context"
 
+	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
 	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
 )
 
@@ -18,6 +19,9 @@ import (
 //	type MyHook struct {
 //	  ldhooks.Unimplemented
 //	}
+//
+// A Hook may also implement any of the optional series TrackSeries, IdentifySeries, and MigrationSeries. The SDK
+// checks for these with a type assertion, so a hook that does not implement them is not affected.
 type Hook interface {
 	Metadata() Metadata
 	EvaluationSeries
@@ -53,6 +57,9 @@ type EvaluationSeries interface {
 // hookInterfaces is an interface for implementation by the Unimplemented
 type hookInterfaces interface {
 	EvaluationSeries
+	TrackSeries
+	IdentifySeries
+	MigrationSeries
 }
 
 // Unimplemented implements all Hook methods with empty functions.
@@ -86,6 +93,54 @@ func (h Unimplemented) AfterEvaluation(
 	return data, nil
 }
 
+// BeforeTrack is a default implementation of the BeforeTrack stage.
+func (h Unimplemented) BeforeTrack(_ context.Context, _ TrackSeriesContext, data SeriesData) (SeriesData, error) {
+	return data, nil
+}
+
+// AfterTrack is a default implementation of the AfterTrack stage.
+func (h Unimplemented) AfterTrack(_ context.Context, _ TrackSeriesContext, data SeriesData) (SeriesData, error) {
+	return data, nil
+}
+
+// BeforeIdentify is a default implementation of the BeforeIdentify stage.
+func (h Unimplemented) BeforeIdentify(
+	_ context.Context,
+	_ IdentifySeriesContext,
+	data SeriesData,
+) (SeriesData, error) {
+	return data, nil
+}
+
+// AfterIdentify is a default implementation of the AfterIdentify stage.
+func (h Unimplemented) AfterIdentify(
+	_ context.Context,
+	_ IdentifySeriesContext,
+	data SeriesData,
+) (SeriesData, error) {
+	return data, nil
+}
+
+// BeforeMigrationOp is a default implementation of the BeforeMigrationOp stage.
+func (h Unimplemented) BeforeMigrationOp(
+	_ context.Context,
+	_ MigrationSeriesContext,
+	data SeriesData,
+) (SeriesData, error) {
+	return data, nil
+}
+
+// AfterMigrationOp is a default implementation of the AfterMigrationOp stage.
+func (h Unimplemented) AfterMigrationOp(
+	_ context.Context,
+	_ MigrationSeriesContext,
+	data SeriesData,
+	_ ldmigration.Stage,
+	_ error,
+) (SeriesData, error) {
+	return data, nil
+}
+
 // Implementation note: Unimplemented does not implement GetMetaData because that must be implemented by hook
 // implementors.
 
@@ -0,0 +1,47 @@
+package ldhooks
+
+import (
+	"context"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+)
+
+// IdentifySeries is an optional series of stages that are called when the application calls one of the
+// LDClient Identify methods. A Hook that implements this interface will have these stages called in addition to
+// the EvaluationSeries.
+//
+// The stages are called even if events are disabled in the SDK configuration.
+type IdentifySeries interface {
+	// BeforeIdentify is called before the identify event is sent. The method returns SeriesData that will be
+	// passed to AfterIdentify.
+	BeforeIdentify(ctx context.Context, seriesContext IdentifySeriesContext, data SeriesData) (SeriesData, error)
+
+	// AfterIdentify is called after the identify event has been sent. The method returns SeriesData, which is
+	// currently unused but is returned for consistency with the other series.
+	AfterIdentify(ctx context.Context, seriesContext IdentifySeriesContext, data SeriesData) (SeriesData, error)
+}
+
+// IdentifySeriesContext contains contextual information for the execution of stages in the identify series.
+type IdentifySeriesContext struct {
+	context ldcontext.Context
+	method  string
+}
+
+// NewIdentifySeriesContext creates a new IdentifySeriesContext. Hook implementations do not need to use this
+// function.
+func NewIdentifySeriesContext(evalContext ldcontext.Context, method string) IdentifySeriesContext {
+	return IdentifySeriesContext{
+		context: evalContext,
+		method:  method,
+	}
+}
+
+// Context gets the evaluation context that is being identified.
+func (c IdentifySeriesContext) Context() ldcontext.Context {
+	return c.context
+}
+
+// Method gets a string represent of the LDClient method being executed.
+func (c IdentifySeriesContext) Method() string {
+	return c.method
+}
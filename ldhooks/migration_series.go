@@ -0,0 +1,79 @@
+package ldhooks
+
+import (
+	"context"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
+)
+
+// MigrationSeries is an optional series of stages that are called when the application performs a read or write
+// operation with a Migrator. A Hook that implements this interface will have these stages called in addition to
+// the EvaluationSeries; the evaluation of the migration flag is still reported to the EvaluationSeries.
+type MigrationSeries interface {
+	// BeforeMigrationOp is called before the migration flag is evaluated. The method returns SeriesData that will
+	// be passed to AfterMigrationOp.
+	BeforeMigrationOp(
+		ctx context.Context,
+		seriesContext MigrationSeriesContext,
+		data SeriesData,
+	) (SeriesData, error)
+
+	// AfterMigrationOp is called after the operation has completed. The stage is the migration stage that was used,
+	// and err is the error, if any, from the authoritative origin. The method returns SeriesData, which is currently
+	// unused but is returned for consistency with the other series.
+	AfterMigrationOp(
+		ctx context.Context,
+		seriesContext MigrationSeriesContext,
+		data SeriesData,
+		stage ldmigration.Stage,
+		err error,
+	) (SeriesData, error)
+}
+
+// MigrationSeriesContext contains contextual information for the execution of stages in the migration series.
+type MigrationSeriesContext struct {
+	flagKey      string
+	context      ldcontext.Context
+	defaultStage ldmigration.Stage
+	operation    ldmigration.Operation
+	method       string
+}
+
+// NewMigrationSeriesContext creates a new MigrationSeriesContext. Hook implementations do not need to use this
+// function.
+func NewMigrationSeriesContext(flagKey string, evalContext ldcontext.Context, defaultStage ldmigration.Stage,
+	operation ldmigration.Operation, method string) MigrationSeriesContext {
+	return MigrationSeriesContext{
+		flagKey:      flagKey,
+		context:      evalContext,
+		defaultStage: defaultStage,
+		operation:    operation,
+		method:       method,
+	}
+}
+
+// FlagKey gets the key of the migration flag.
+func (c MigrationSeriesContext) FlagKey() string {
+	return c.flagKey
+}
+
+// Context gets the evaluation context the operation is being performed for.
+func (c MigrationSeriesContext) Context() ldcontext.Context {
+	return c.context
+}
+
+// DefaultStage gets the stage that is used if the migration flag cannot be evaluated.
+func (c MigrationSeriesContext) DefaultStage() ldmigration.Stage {
+	return c.defaultStage
+}
+
+// Operation gets the kind of operation, ldmigration.Read or ldmigration.Write.
+func (c MigrationSeriesContext) Operation() ldmigration.Operation {
+	return c.operation
+}
+
+// Method gets a string represent of the Migrator method being executed.
+func (c MigrationSeriesContext) Method() string {
+	return c.method
+}
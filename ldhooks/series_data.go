@@ -0,0 +1,16 @@
+package ldhooks
+
+// SeriesData is the type used for passing implementation-specific data between the stages of the track,
+// identify, and migration series. It is the same type as EvaluationSeriesData, so hook implementations can use
+// NewEvaluationSeriesBuilder to add data to it.
+//
+//	func(h MyHook) BeforeTrack(ctx context.Context, seriesContext TrackSeriesContext,
+//		data SeriesData) (SeriesData, error) {
+//		return NewEvaluationSeriesBuilder(data).Set("start", time.Now()).Build(), nil
+//	}
+type SeriesData = EvaluationSeriesData
+
+// EmptySeriesData returns empty series data. This function is not intended for use by hook implementors.
+func EmptySeriesData() SeriesData {
+	return EmptyEvaluationSeriesData()
+}
@@ -0,0 +1,81 @@
+package ldhooks
+
+import (
+	"context"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+// TrackSeries is an optional series of stages that are called when the application sends a custom event with
+// one of the LDClient Track methods. A Hook that implements this interface will have these stages called in
+// addition to the EvaluationSeries.
+//
+// The stages are called even if events are disabled in the SDK configuration, so that a hook can observe every
+// custom event that the application emits.
+type TrackSeries interface {
+	// BeforeTrack is called before the custom event is sent. The method returns SeriesData that will be passed to
+	// AfterTrack.
+	BeforeTrack(ctx context.Context, seriesContext TrackSeriesContext, data SeriesData) (SeriesData, error)
+
+	// AfterTrack is called after the custom event has been sent. The method returns SeriesData, which is
+	// currently unused but is returned for consistency with the other series.
+	AfterTrack(ctx context.Context, seriesContext TrackSeriesContext, data SeriesData) (SeriesData, error)
+}
+
+// TrackSeriesContext contains contextual information for the execution of stages in the track series.
+type TrackSeriesContext struct {
+	key         string
+	context     ldcontext.Context
+	data        ldvalue.Value
+	metricValue float64
+	hasMetric   bool
+	method      string
+}
+
+// NewTrackSeriesContext creates a new TrackSeriesContext for an event that has no metric value. Hook
+// implementations do not need to use this function.
+func NewTrackSeriesContext(key string, evalContext ldcontext.Context, data ldvalue.Value,
+	method string) TrackSeriesContext {
+	return TrackSeriesContext{
+		key:     key,
+		context: evalContext,
+		data:    data,
+		method:  method,
+	}
+}
+
+// NewTrackSeriesContextWithMetric creates a new TrackSeriesContext for an event that has a metric value. Hook
+// implementations do not need to use this function.
+func NewTrackSeriesContextWithMetric(key string, evalContext ldcontext.Context, data ldvalue.Value,
+	metricValue float64, method string) TrackSeriesContext {
+	c := NewTrackSeriesContext(key, evalContext, data, method)
+	c.metricValue = metricValue
+	c.hasMetric = true
+	return c
+}
+
+// Key gets the event name that the application provided.
+func (c TrackSeriesContext) Key() string {
+	return c.key
+}
+
+// Context gets the evaluation context that the event is associated with.
+func (c TrackSeriesContext) Context() ldcontext.Context {
+	return c.context
+}
+
+// Data gets the custom data that the application provided, or ldvalue.Null() if there was none.
+func (c TrackSeriesContext) Data() ldvalue.Value {
+	return c.data
+}
+
+// MetricValue gets the numeric metric value that the application provided. If there was none, ok will be false.
+func (c TrackSeriesContext) MetricValue() (value float64, ok bool) {
+	return c.metricValue, c.hasMetric
+}
+
+// Method gets a string represent of the LDClient method being executed.
+func (c TrackSeriesContext) Method() string {
+	return c.method
+}
@@ -0,0 +1,98 @@
+package ldotel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+)
+
+const diagnosticPeriodicEventKind = "diagnostic"
+
+// EventSender returns a configuration object for an event sender that records metrics for the analytics and
+// diagnostic event payloads that the SDK delivers, and then passes them to another event sender. It is meant
+// to be passed to [github.com/launchdarkly/go-server-sdk/v7/ldcomponents.EventProcessorBuilder.EventSender].
+//
+// The sender parameter is the event sender that actually delivers the payloads. If it is nil, they are sent to
+// LaunchDarkly with [github.com/launchdarkly/go-server-sdk/v7/ldcomponents.LaunchDarklyEventSender]; in that
+// case, the EventProcessorBuilder.EnableGzip setting does not apply, as with any other event sender.
+func (m *DataSystemMetrics) EventSender(
+	sender subsystems.ComponentConfigurer[ldevents.EventSender],
+) subsystems.ComponentConfigurer[ldevents.EventSender] {
+	if sender == nil {
+		sender = ldcomponents.LaunchDarklyEventSender()
+	}
+	return instrumentedEventSenderFactory{base: sender, metrics: m}
+}
+
+type instrumentedEventSenderFactory struct {
+	base    subsystems.ComponentConfigurer[ldevents.EventSender]
+	metrics *DataSystemMetrics
+}
+
+func (f instrumentedEventSenderFactory) Build(context subsystems.ClientContext) (ldevents.EventSender, error) {
+	base, err := f.base.Build(context)
+	if err != nil {
+		return nil, err
+	}
+	return instrumentedEventSender{base: base, metrics: f.metrics}, nil
+}
+
+type instrumentedEventSender struct {
+	base    ldevents.EventSender
+	metrics *DataSystemMetrics
+}
+
+func (s instrumentedEventSender) SendEventData( //nolint:revive // no doc comment for standard method
+	kind ldevents.EventDataKind,
+	data []byte,
+	eventCount int,
+) ldevents.EventSenderResult {
+	ctx := context.Background()
+	payloadKind := eventPayloadKindAnalytics
+	if kind == ldevents.DiagnosticEventDataKind {
+		payloadKind = eventPayloadKindDiagnostic
+		s.metrics.recordDroppedEvents(ctx, data)
+	}
+
+	result := s.base.SendEventData(kind, data, eventCount)
+
+	outcome := resultFailure
+	if result.Success {
+		outcome = resultSuccess
+	}
+	s.metrics.eventFlushes.Add(ctx, 1, metric.WithAttributes(
+		attribute.String(eventPayloadKindAttributeName, payloadKind),
+		attribute.String(resultAttributeName, outcome),
+	))
+	return result
+}
+
+// Close closes the underlying event sender, if it implements io.Closer.
+func (s instrumentedEventSender) Close() error {
+	if closer, ok := s.base.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// recordDroppedEvents reads the number of dropped events from a periodic diagnostic event payload, which the
+// event processor passes to the event sender as uncompressed JSON.
+func (m *DataSystemMetrics) recordDroppedEvents(ctx context.Context, data []byte) {
+	var payload struct {
+		Kind          string `json:"kind"`
+		DroppedEvents int64  `json:"droppedEvents"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return
+	}
+	if payload.Kind == diagnosticPeriodicEventKind && payload.DroppedEvents > 0 {
+		m.eventsDropped.Add(ctx, payload.DroppedEvents)
+	}
+}
@@ -0,0 +1,111 @@
+package ldotel
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
+
+	"github.com/launchdarkly/go-server-sdk/v7/ldhttp"
+)
+
+type requestKind int
+
+const (
+	otherRequest requestKind = iota
+	streamingRequest
+	pollingRequest
+)
+
+// These correspond to the request paths used by the SDK's data sources. A custom base URI may add a prefix to
+// the path, so only the end of the path is compared.
+const (
+	streamingRequestPathSuffix = "/all"
+	pollingRequestPathSuffix   = "/sdk/latest-all"
+)
+
+// HTTPClientFactory returns a function for creating HTTP clients that record metrics for the SDK's streaming
+// and polling requests. It is meant to be passed to
+// [github.com/launchdarkly/go-server-sdk/v7/ldcomponents.HTTPConfigurationBuilder.HTTPClientFactory].
+//
+// The factory parameter creates the underlying clients whose transports are wrapped. If it is nil, clients are
+// created with the same defaults that the SDK would use; in that case, HTTPConfigurationBuilder options such as
+// ConnectTimeout and ProxyURL have no effect, as with any other custom HTTP client factory.
+func (m *DataSystemMetrics) HTTPClientFactory(factory func() *http.Client) func() *http.Client {
+	return func() *http.Client {
+		var client http.Client
+		if factory != nil {
+			client = *factory()
+		}
+		base := client.Transport
+		if base == nil {
+			if transport, _, err := ldhttp.NewHTTPTransport(); err == nil {
+				base = transport
+			} else {
+				base = http.DefaultTransport // COVERAGE: NewHTTPTransport cannot fail without options
+			}
+		}
+		client.Transport = instrumentedTransport{base: base, metrics: m}
+		return &client
+	}
+}
+
+type instrumentedTransport struct {
+	base    http.RoundTripper
+	metrics *DataSystemMetrics
+}
+
+func (t instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	kind := classifyRequest(req)
+
+	startTime := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(startTime)
+
+	result := resultFailure
+	var attrs []attribute.KeyValue
+	if err == nil {
+		if resp.StatusCode >= 200 && resp.StatusCode < 400 {
+			result = resultSuccess
+		}
+		attrs = append(attrs, semconv.HTTPStatusCode(resp.StatusCode))
+	}
+	attrs = append(attrs, attribute.String(resultAttributeName, result))
+
+	ctx := req.Context()
+	switch kind {
+	case streamingRequest:
+		t.metrics.recordStreamConnection(ctx, attrs)
+	case pollingRequest:
+		t.metrics.pollingDuration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+	}
+	return resp, err
+}
+
+func classifyRequest(req *http.Request) requestKind {
+	path := strings.TrimRight(req.URL.Path, "/")
+	switch {
+	case req.Method == http.MethodGet && strings.HasSuffix(path, pollingRequestPathSuffix):
+		return pollingRequest
+	case req.Method == http.MethodGet && strings.HasSuffix(path, streamingRequestPathSuffix):
+		return streamingRequest
+	default:
+		return otherRequest
+	}
+}
+
+func (m *DataSystemMetrics) recordStreamConnection(ctx context.Context, attrs []attribute.KeyValue) {
+	m.lock.Lock()
+	m.streamAttempts++
+	reconnect := m.streamAttempts > 1
+	m.lock.Unlock()
+
+	m.streamConnections.Add(ctx, 1, metric.WithAttributes(attrs...))
+	if reconnect {
+		m.streamReconnects.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+}
@@ -0,0 +1,258 @@
+package ldotel
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
+
+	ldclient "github.com/launchdarkly/go-server-sdk/v7"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+)
+
+const (
+	dataSourceStateMetricName        = "launchdarkly.data_source.state"
+	dataSourceStateChangesMetricName = "launchdarkly.data_source.state_changes"
+	dataSourceErrorsMetricName       = "launchdarkly.data_source.errors"
+	streamConnectionsMetricName      = "launchdarkly.data_source.stream.connections"
+	streamReconnectsMetricName       = "launchdarkly.data_source.stream.reconnects"
+	pollingDurationMetricName        = "launchdarkly.data_source.polling.duration"
+	dataStoreAvailableMetricName     = "launchdarkly.data_store.available"
+	dataStoreOperationDurationMetric = "launchdarkly.data_store.operation.duration"
+	dataStoreOperationErrorsMetric   = "launchdarkly.data_store.operation.errors"
+	bigSegmentStoreAvailableMetric   = "launchdarkly.big_segment_store.available"
+	bigSegmentStoreStaleMetricName   = "launchdarkly.big_segment_store.stale"
+	eventFlushesMetricName           = "launchdarkly.events.flushes"
+	eventsDroppedMetricName          = "launchdarkly.events.dropped"
+
+	dataSourceStateAttributeName     = "launchdarkly.data_source.state"
+	dataSourceErrorKindAttributeName = "launchdarkly.data_source.error_kind"
+	dataStoreOperationAttributeName  = "launchdarkly.data_store.operation"
+	dataStoreDataKindAttributeName   = "launchdarkly.data_store.data_kind"
+	eventPayloadKindAttributeName    = "launchdarkly.events.payload_kind"
+	resultAttributeName              = "launchdarkly.result"
+
+	resultSuccess              = "success"
+	resultFailure              = "failure"
+	eventPayloadKindAnalytics  = "analytics"
+	eventPayloadKindDiagnostic = "diagnostic"
+)
+
+var allDataSourceStates = []interfaces.DataSourceState{ //nolint:gochecknoglobals
+	interfaces.DataSourceStateInitializing,
+	interfaces.DataSourceStateValid,
+	interfaces.DataSourceStateInterrupted,
+	interfaces.DataSourceStateOff,
+}
+
+// DataSystemMetricsOption is used to implement functional options for DataSystemMetrics.
+type DataSystemMetricsOption func(metrics *DataSystemMetrics)
+
+// WithDataSystemMeterProvider sets the OpenTelemetry MeterProvider that DataSystemMetrics uses to create its
+// instruments. By default, it uses the global MeterProvider from otel.GetMeterProvider.
+func WithDataSystemMeterProvider(provider metric.MeterProvider) DataSystemMetricsOption {
+	return func(m *DataSystemMetrics) {
+		m.meterProvider = provider
+	}
+}
+
+// DataSystemMetrics records OpenTelemetry metrics about the health of the SDK's data source, data store, and
+// event delivery, as a complement to the evaluation metrics recorded by MetricsHook.
+//
+// The SDK components are instrumented in four ways, which can be used independently:
+//   - Observe subscribes to the client's status providers. This provides the gauge "launchdarkly.data_source.state"
+//     (1 for the current state and 0 for others), the counters "launchdarkly.data_source.state_changes" and
+//     "launchdarkly.data_source.errors", the gauge "launchdarkly.data_store.available", and the gauges
+//     "launchdarkly.big_segment_store.available" and "launchdarkly.big_segment_store.stale".
+//   - HTTPClientFactory wraps the HTTP clients that the SDK uses. This provides the counters
+//     "launchdarkly.data_source.stream.connections" and "launchdarkly.data_source.stream.reconnects", and the
+//     histogram "launchdarkly.data_source.polling.duration".
+//   - EventSender wraps the component that delivers event payloads. This provides the counters
+//     "launchdarkly.events.flushes" and "launchdarkly.events.dropped". The dropped event count is taken from the
+//     SDK's periodic diagnostic events, so it is only reported if diagnostic events are enabled, which is the
+//     default.
+//   - PersistentDataStore wraps a persistent data store. This provides the histogram
+//     "launchdarkly.data_store.operation.duration" and the counter "launchdarkly.data_store.operation.errors".
+//
+// For instance:
+//
+//	metrics := ldotel.NewDataSystemMetrics()
+//	config := ld.Config{
+//	    DataStore: ldcomponents.PersistentDataStore(metrics.PersistentDataStore(ldredis.DataStore())),
+//	    Events:    ldcomponents.SendEvents().EventSender(metrics.EventSender(nil)),
+//	    HTTP:      ldcomponents.HTTPConfiguration().HTTPClientFactory(metrics.HTTPClientFactory(nil)),
+//	}
+//	client, _ := ld.MakeCustomClient("sdk-key", config, 5*time.Second)
+//	metrics.Observe(client)
+//	defer metrics.Close()
+type DataSystemMetrics struct {
+	meterProvider       metric.MeterProvider
+	dataSourceStates    metric.Int64ObservableGauge
+	dataSourceChanges   metric.Int64Counter
+	dataSourceErrors    metric.Int64Counter
+	streamConnections   metric.Int64Counter
+	streamReconnects    metric.Int64Counter
+	pollingDuration     metric.Float64Histogram
+	dataStoreAvailable  metric.Int64ObservableGauge
+	storeOpDuration     metric.Float64Histogram
+	storeOpErrors       metric.Int64Counter
+	bigSegmentAvailable metric.Int64ObservableGauge
+	bigSegmentStale     metric.Int64ObservableGauge
+	eventFlushes        metric.Int64Counter
+	eventsDropped       metric.Int64Counter
+	registration        metric.Registration
+	client              *ldclient.LDClient
+	streamAttempts      int
+	bigSegmentsChecked  bool
+	closers             []func()
+	lock                sync.Mutex
+}
+
+// NewDataSystemMetrics creates a new DataSystemMetrics instance.
+func NewDataSystemMetrics(opts ...DataSystemMetricsOption) *DataSystemMetrics {
+	m := &DataSystemMetrics{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.meterProvider == nil {
+		m.meterProvider = otel.GetMeterProvider()
+	}
+	meter := m.meterProvider.Meter(meterName, metric.WithInstrumentationVersion(Version))
+
+	// As in NewMetricsHook, errors from creating instruments can be ignored because a usable no-op instrument
+	// is always returned.
+	m.dataSourceStates, _ = meter.Int64ObservableGauge(dataSourceStateMetricName,
+		metric.WithDescription("Whether the data source is in each state (1) or not (0)"))
+	m.dataSourceChanges, _ = meter.Int64Counter(dataSourceStateChangesMetricName,
+		metric.WithDescription("The number of times the data source has changed to each state"))
+	m.dataSourceErrors, _ = meter.Int64Counter(dataSourceErrorsMetricName,
+		metric.WithDescription("The number of errors reported by the data source"))
+	m.streamConnections, _ = meter.Int64Counter(streamConnectionsMetricName,
+		metric.WithDescription("The number of attempts to connect to the streaming service"))
+	m.streamReconnects, _ = meter.Int64Counter(streamReconnectsMetricName,
+		metric.WithDescription("The number of attempts to reconnect to the streaming service after the first one"))
+	m.pollingDuration, _ = meter.Float64Histogram(pollingDurationMetricName,
+		metric.WithDescription("The duration of polling requests"), metric.WithUnit("s"))
+	m.dataStoreAvailable, _ = meter.Int64ObservableGauge(dataStoreAvailableMetricName,
+		metric.WithDescription("Whether the data store is available (1) or not (0)"))
+	m.storeOpDuration, _ = meter.Float64Histogram(dataStoreOperationDurationMetric,
+		metric.WithDescription("The duration of persistent data store operations"), metric.WithUnit("s"))
+	m.storeOpErrors, _ = meter.Int64Counter(dataStoreOperationErrorsMetric,
+		metric.WithDescription("The number of persistent data store operations that returned an error"))
+	m.bigSegmentAvailable, _ = meter.Int64ObservableGauge(bigSegmentStoreAvailableMetric,
+		metric.WithDescription("Whether the Big Segment store is available (1) or not (0)"))
+	m.bigSegmentStale, _ = meter.Int64ObservableGauge(bigSegmentStoreStaleMetricName,
+		metric.WithDescription("Whether the Big Segment store data is stale (1) or not (0)"))
+	m.eventFlushes, _ = meter.Int64Counter(eventFlushesMetricName,
+		metric.WithDescription("The number of analytics or diagnostic event payloads that the SDK tried to deliver"))
+	m.eventsDropped, _ = meter.Int64Counter(eventsDroppedMetricName,
+		metric.WithDescription("The number of analytics events dropped because the event buffer was full"))
+
+	m.registration, _ = meter.RegisterCallback(m.observeStatus,
+		m.dataSourceStates, m.dataStoreAvailable, m.bigSegmentAvailable, m.bigSegmentStale)
+	return m
+}
+
+// Observe subscribes to the status providers of a client, so that the status gauges and the data source state
+// change and error counters are recorded for it. It should be called only once.
+//
+// State changes that happened before Observe was called, such as the initial change to the "VALID" state while
+// the client was being created, are not counted; the gauges always reflect the current status.
+func (m *DataSystemMetrics) Observe(client *ldclient.LDClient) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.client = client
+
+	dataSourceStatusProvider := client.GetDataSourceStatusProvider()
+	dataSourceCh := dataSourceStatusProvider.AddStatusListener()
+	m.closers = append(m.closers, func() { dataSourceStatusProvider.RemoveStatusListener(dataSourceCh) })
+	go m.consumeDataSourceStatus(dataSourceStatusProvider.GetStatus(), dataSourceCh)
+
+	bigSegmentStatusProvider := client.GetBigSegmentStoreStatusProvider()
+	bigSegmentCh := bigSegmentStatusProvider.AddStatusListener()
+	m.closers = append(m.closers, func() { bigSegmentStatusProvider.RemoveStatusListener(bigSegmentCh) })
+	go m.consumeBigSegmentStoreStatus(bigSegmentCh)
+}
+
+// Close unsubscribes from the client's status providers and stops observing the status gauges.
+func (m *DataSystemMetrics) Close() error {
+	m.lock.Lock()
+	closers := m.closers
+	m.closers = nil
+	m.client = nil
+	m.lock.Unlock()
+	for _, closer := range closers {
+		closer()
+	}
+	if m.registration != nil {
+		return m.registration.Unregister()
+	}
+	return nil
+}
+
+func (m *DataSystemMetrics) consumeDataSourceStatus(
+	lastStatus interfaces.DataSourceStatus,
+	statusCh <-chan interfaces.DataSourceStatus,
+) {
+	ctx := context.Background()
+	for status := range statusCh {
+		if status.State != lastStatus.State {
+			m.dataSourceChanges.Add(ctx, 1, metric.WithAttributes(
+				attribute.String(dataSourceStateAttributeName, string(status.State))))
+		}
+		if status.LastError.Kind != "" && !status.LastError.Time.Equal(lastStatus.LastError.Time) {
+			attrs := []attribute.KeyValue{
+				attribute.String(dataSourceErrorKindAttributeName, string(status.LastError.Kind)),
+			}
+			if status.LastError.StatusCode > 0 {
+				attrs = append(attrs, semconv.HTTPStatusCode(status.LastError.StatusCode))
+			}
+			m.dataSourceErrors.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+		lastStatus = status
+	}
+}
+
+func (m *DataSystemMetrics) consumeBigSegmentStoreStatus(statusCh <-chan interfaces.BigSegmentStoreStatus) {
+	for range statusCh {
+		m.lock.Lock()
+		m.bigSegmentsChecked = true
+		m.lock.Unlock()
+	}
+}
+
+func (m *DataSystemMetrics) observeStatus(_ context.Context, o metric.Observer) error {
+	m.lock.Lock()
+	client, bigSegmentsChecked := m.client, m.bigSegmentsChecked
+	m.lock.Unlock()
+	if client == nil {
+		return nil
+	}
+
+	currentState := client.GetDataSourceStatusProvider().GetStatus().State
+	for _, state := range allDataSourceStates {
+		o.ObserveInt64(m.dataSourceStates, boolToInt64(state == currentState),
+			metric.WithAttributes(attribute.String(dataSourceStateAttributeName, string(state))))
+	}
+
+	o.ObserveInt64(m.dataStoreAvailable, boolToInt64(client.GetDataStoreStatusProvider().GetStatus().Available))
+
+	// The Big Segment store status provider exists even if no Big Segment store is configured, so the Big Segment
+	// gauges are only reported once the store has been found to be available or has reported a status change.
+	bigSegmentStatus := client.GetBigSegmentStoreStatusProvider().GetStatus()
+	if bigSegmentsChecked || bigSegmentStatus.Available {
+		o.ObserveInt64(m.bigSegmentAvailable, boolToInt64(bigSegmentStatus.Available))
+		o.ObserveInt64(m.bigSegmentStale, boolToInt64(bigSegmentStatus.Stale))
+	}
+	return nil
+}
+
+func boolToInt64(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,276 @@
+package ldotel
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	ldevents "github.com/launchdarkly/go-sdk-events/v3"
+	ldclient "github.com/launchdarkly/go-server-sdk/v7"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func createDataSystemMetrics() (*DataSystemMetrics, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	return NewDataSystemMetrics(WithDataSystemMeterProvider(provider)), reader
+}
+
+func makeFakeLaunchDarklyServer(streamStatus int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sdk/latest-all":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"flags":{},"segments":{}}`))
+		case "/all":
+			w.WriteHeader(streamStatus)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+}
+
+func TestDataSystemMetricsRecordsPollingAndEventRequests(t *testing.T) {
+	metrics, reader := createDataSystemMetrics()
+	defer metrics.Close()
+	server := makeFakeLaunchDarklyServer(http.StatusOK)
+	defer server.Close()
+
+	client, err := ldclient.MakeCustomClient("sdk-key", ldclient.Config{
+		DataSource:       ldcomponents.PollingDataSource(),
+		Events:           ldcomponents.SendEvents().EventSender(metrics.EventSender(nil)),
+		HTTP:             ldcomponents.HTTPConfiguration().HTTPClientFactory(metrics.HTTPClientFactory(nil)),
+		Logging:          ldcomponents.NoLogging(),
+		ServiceEndpoints: ldcomponents.RelayProxyEndpoints(server.URL),
+	}, 5*time.Second)
+	require.NoError(t, err)
+	metrics.Observe(client)
+
+	_, _ = client.BoolVariation("flag-key", ldcontext.New("test-context"), false)
+	require.NoError(t, client.Close())
+
+	histogram, ok := collectMetric(t, reader, pollingDurationMetricName).(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+	result, _ := histogram.DataPoints[0].Attributes.Value(resultAttributeName)
+	assert.Equal(t, resultSuccess, result.AsString())
+
+	flushes := sumsByAttribute(t, reader, eventFlushesMetricName, eventPayloadKindAttributeName)
+	assert.Equal(t, int64(1), flushes[eventPayloadKindAnalytics])
+	assert.Equal(t, int64(1), flushes[eventPayloadKindDiagnostic]) // the diagnostic init event
+}
+
+func TestDataSystemMetricsRecordsStreamReconnects(t *testing.T) {
+	metrics, reader := createDataSystemMetrics()
+	defer metrics.Close()
+	server := makeFakeLaunchDarklyServer(http.StatusServiceUnavailable)
+	defer server.Close()
+
+	client, err := ldclient.MakeCustomClient("sdk-key", ldclient.Config{
+		DataSource:       ldcomponents.StreamingDataSource().InitialReconnectDelay(time.Millisecond),
+		Events:           ldcomponents.NoEvents(),
+		HTTP:             ldcomponents.HTTPConfiguration().HTTPClientFactory(metrics.HTTPClientFactory(nil)),
+		Logging:          ldcomponents.NoLogging(),
+		ServiceEndpoints: ldcomponents.RelayProxyEndpoints(server.URL),
+	}, 0)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Eventually(t, func() bool {
+		reconnects, ok := collectMetric(t, reader, streamReconnectsMetricName).(metricdata.Sum[int64])
+		return ok && len(reconnects.DataPoints) > 0
+	}, time.Second*5, time.Millisecond*10)
+	connections := sumsByAttribute(t, reader, streamConnectionsMetricName, resultAttributeName)
+	assert.Greater(t, connections[resultFailure], int64(1))
+	assert.Equal(t, int64(0), connections[resultSuccess])
+}
+
+type fakeEventSender struct {
+	result ldevents.EventSenderResult
+	closed bool
+}
+
+func (s *fakeEventSender) Build(subsystems.ClientContext) (ldevents.EventSender, error) {
+	return s, nil
+}
+
+func (s *fakeEventSender) SendEventData(ldevents.EventDataKind, []byte, int) ldevents.EventSenderResult {
+	return s.result
+}
+
+func (s *fakeEventSender) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestDataSystemMetricsRecordsDroppedEvents(t *testing.T) {
+	metrics, reader := createDataSystemMetrics()
+	defer metrics.Close()
+	base := &fakeEventSender{result: ldevents.EventSenderResult{Success: true}}
+	sender, err := metrics.EventSender(base).
+		Build(subsystems.BasicClientContext{})
+	require.NoError(t, err)
+
+	send := func(payload string) {
+		result := sender.SendEventData(ldevents.DiagnosticEventDataKind, []byte(payload), 1)
+		assert.True(t, result.Success)
+	}
+	send(`{"kind":"diagnostic-init"}`)
+	send(`{"kind":"diagnostic","droppedEvents":3}`)
+	send(`{"kind":"diagnostic","droppedEvents":4}`)
+	send(`{"kind":"diagnostic","droppedEvents":0}`)
+
+	dropped, ok := collectMetric(t, reader, eventsDroppedMetricName).(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, dropped.DataPoints, 1)
+	assert.Equal(t, int64(7), dropped.DataPoints[0].Value)
+
+	require.NoError(t, sender.(io.Closer).Close())
+	assert.True(t, base.closed)
+}
+
+func TestDataSystemMetricsRecordsFailedEventFlushes(t *testing.T) {
+	metrics, reader := createDataSystemMetrics()
+	defer metrics.Close()
+	base := &fakeEventSender{result: ldevents.EventSenderResult{Success: false}}
+	sender, err := metrics.EventSender(base).
+		Build(subsystems.BasicClientContext{})
+	require.NoError(t, err)
+
+	_ = sender.SendEventData(ldevents.AnalyticsEventDataKind, []byte(`[]`), 0)
+
+	flushes := sumsByAttribute(t, reader, eventFlushesMetricName, resultAttributeName)
+	assert.Equal(t, map[string]int64{resultFailure: 1}, flushes)
+}
+
+func TestDataSystemMetricsObservesStatus(t *testing.T) {
+	metrics, reader := createDataSystemMetrics()
+	defer metrics.Close()
+	server := makeFakeLaunchDarklyServer(http.StatusOK)
+	defer server.Close()
+
+	client, err := ldclient.MakeCustomClient("sdk-key", ldclient.Config{
+		DataSource:       ldcomponents.PollingDataSource(),
+		Events:           ldcomponents.NoEvents(),
+		Logging:          ldcomponents.NoLogging(),
+		ServiceEndpoints: ldcomponents.RelayProxyEndpoints(server.URL),
+	}, 5*time.Second)
+	require.NoError(t, err)
+	defer client.Close()
+	metrics.Observe(client)
+
+	states, ok := collectMetric(t, reader, dataSourceStateMetricName).(metricdata.Gauge[int64])
+	require.True(t, ok)
+	values := make(map[string]int64)
+	for _, point := range states.DataPoints {
+		state, _ := point.Attributes.Value(dataSourceStateAttributeName)
+		values[state.AsString()] = point.Value
+	}
+	assert.Equal(t, map[string]int64{"INITIALIZING": 0, "VALID": 1, "INTERRUPTED": 0, "OFF": 0}, values)
+
+	available, ok := collectMetric(t, reader, dataStoreAvailableMetricName).(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, available.DataPoints, 1)
+	assert.Equal(t, int64(1), available.DataPoints[0].Value)
+
+	assert.Nil(t, collectMetric(t, reader, bigSegmentStoreAvailableMetric))
+
+	require.NoError(t, metrics.Close())
+	assert.Nil(t, collectMetric(t, reader, dataSourceStateMetricName))
+}
+
+func TestDataSystemMetricsCountsDataSourceStateChangesAndErrors(t *testing.T) {
+	metrics, reader := createDataSystemMetrics()
+	defer metrics.Close()
+	statusCh := make(chan interfaces.DataSourceStatus, 10)
+	errorTime := time.Now()
+	networkError := interfaces.DataSourceErrorInfo{Kind: interfaces.DataSourceErrorKindNetworkError, Time: errorTime}
+	responseError := interfaces.DataSourceErrorInfo{Kind: interfaces.DataSourceErrorKindErrorResponse,
+		StatusCode: 503, Time: errorTime.Add(time.Second)}
+
+	statusCh <- interfaces.DataSourceStatus{State: interfaces.DataSourceStateValid}
+	statusCh <- interfaces.DataSourceStatus{State: interfaces.DataSourceStateInterrupted, LastError: networkError}
+	statusCh <- interfaces.DataSourceStatus{State: interfaces.DataSourceStateInterrupted, LastError: responseError}
+	statusCh <- interfaces.DataSourceStatus{State: interfaces.DataSourceStateValid, LastError: responseError}
+	close(statusCh)
+	metrics.consumeDataSourceStatus(interfaces.DataSourceStatus{State: interfaces.DataSourceStateInitializing},
+		statusCh)
+
+	assert.Equal(t, map[string]int64{"VALID": 2, "INTERRUPTED": 1},
+		sumsByAttribute(t, reader, dataSourceStateChangesMetricName, dataSourceStateAttributeName))
+	assert.Equal(t, map[string]int64{"NETWORK_ERROR": 1, "ERROR_RESPONSE": 1},
+		sumsByAttribute(t, reader, dataSourceErrorsMetricName, dataSourceErrorKindAttributeName))
+}
+
+type fakePersistentStore struct {
+	err error
+}
+
+func (s fakePersistentStore) Init([]ldstoretypes.SerializedCollection) error { return s.err }
+
+func (s fakePersistentStore) Get(ldstoretypes.DataKind, string) (ldstoretypes.SerializedItemDescriptor, error) {
+	return ldstoretypes.SerializedItemDescriptor{}.NotFound(), s.err
+}
+
+func (s fakePersistentStore) GetAll(ldstoretypes.DataKind) ([]ldstoretypes.KeyedSerializedItemDescriptor, error) {
+	return nil, s.err
+}
+
+func (s fakePersistentStore) Upsert(ldstoretypes.DataKind, string, ldstoretypes.SerializedItemDescriptor) (
+	bool, error) {
+	return false, s.err
+}
+
+func (s fakePersistentStore) IsInitialized() bool    { return true }
+func (s fakePersistentStore) IsStoreAvailable() bool { return true }
+func (s fakePersistentStore) Close() error           { return nil }
+
+type fakePersistentStoreConfigurer struct {
+	store fakePersistentStore
+}
+
+func (c fakePersistentStoreConfigurer) Build(subsystems.ClientContext) (subsystems.PersistentDataStore, error) {
+	return c.store, nil
+}
+
+func TestDataSystemMetricsRecordsPersistentStoreOperations(t *testing.T) {
+	metrics, reader := createDataSystemMetrics()
+	defer metrics.Close()
+	configurer := metrics.PersistentDataStore(fakePersistentStoreConfigurer{store: fakePersistentStore{}})
+	store, err := configurer.Build(nil)
+	require.NoError(t, err)
+
+	features := ldstoreimpl.Features()
+	_ = store.Init(nil)
+	_, _ = store.Get(features, "key")
+	_, _ = store.GetAll(features)
+
+	assert.Nil(t, collectMetric(t, reader, dataStoreOperationErrorsMetric))
+	histogram, ok := collectMetric(t, reader, dataStoreOperationDurationMetric).(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Len(t, histogram.DataPoints, 3)
+
+	failingConfigurer := metrics.PersistentDataStore(fakePersistentStoreConfigurer{
+		store: fakePersistentStore{err: errors.New("sorry")},
+	})
+	failingStore, err := failingConfigurer.Build(nil)
+	require.NoError(t, err)
+	_, _ = failingStore.Get(features, "key")
+	_, _ = failingStore.Upsert(features, "key", ldstoretypes.SerializedItemDescriptor{})
+
+	assert.Equal(t, map[string]int64{"get": 1, "upsert": 1},
+		sumsByAttribute(t, reader, dataStoreOperationErrorsMetric, dataStoreOperationAttributeName))
+}
@@ -0,0 +1,124 @@
+package ldotel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+)
+
+const (
+	storeOperationInit   = "init"
+	storeOperationGet    = "get"
+	storeOperationGetAll = "get_all"
+	storeOperationUpsert = "upsert"
+)
+
+// PersistentDataStore wraps the configuration of a persistent data store, such as ldredis.DataStore(), so that
+// the latency and errors of its operations are recorded. The result is meant to be passed to
+// [github.com/launchdarkly/go-server-sdk/v7/ldcomponents.PersistentDataStore].
+//
+// Because the SDK caches data from the persistent store by default, the recorded operations are the ones that
+// actually reach the store, rather than every flag evaluation.
+func (m *DataSystemMetrics) PersistentDataStore(
+	configurer subsystems.ComponentConfigurer[subsystems.PersistentDataStore],
+) subsystems.ComponentConfigurer[subsystems.PersistentDataStore] {
+	return instrumentedStoreConfigurer{configurer: configurer, metrics: m}
+}
+
+type instrumentedStoreConfigurer struct {
+	configurer subsystems.ComponentConfigurer[subsystems.PersistentDataStore]
+	metrics    *DataSystemMetrics
+}
+
+func (c instrumentedStoreConfigurer) Build(
+	clientContext subsystems.ClientContext,
+) (subsystems.PersistentDataStore, error) {
+	store, err := c.configurer.Build(clientContext)
+	if err != nil || store == nil {
+		return store, err
+	}
+	return instrumentedStore{store: store, metrics: c.metrics}, nil
+}
+
+// DescribeConfiguration passes through the diagnostic description of the wrapped configuration, so that the
+// SDK's diagnostic events still identify the type of store.
+func (c instrumentedStoreConfigurer) DescribeConfiguration(clientContext subsystems.ClientContext) ldvalue.Value {
+	if dd, ok := c.configurer.(subsystems.DiagnosticDescription); ok {
+		return dd.DescribeConfiguration(clientContext)
+	}
+	return ldvalue.String("custom")
+}
+
+type instrumentedStore struct {
+	store   subsystems.PersistentDataStore
+	metrics *DataSystemMetrics
+}
+
+func (s instrumentedStore) Init(allData []ldstoretypes.SerializedCollection) error {
+	startTime := time.Now()
+	err := s.store.Init(allData)
+	s.metrics.recordStoreOperation(startTime, storeOperationInit, "", err)
+	return err
+}
+
+func (s instrumentedStore) Get(
+	kind ldstoretypes.DataKind,
+	key string,
+) (ldstoretypes.SerializedItemDescriptor, error) {
+	startTime := time.Now()
+	item, err := s.store.Get(kind, key)
+	s.metrics.recordStoreOperation(startTime, storeOperationGet, kind.GetName(), err)
+	return item, err
+}
+
+func (s instrumentedStore) GetAll(
+	kind ldstoretypes.DataKind,
+) ([]ldstoretypes.KeyedSerializedItemDescriptor, error) {
+	startTime := time.Now()
+	items, err := s.store.GetAll(kind)
+	s.metrics.recordStoreOperation(startTime, storeOperationGetAll, kind.GetName(), err)
+	return items, err
+}
+
+func (s instrumentedStore) Upsert(
+	kind ldstoretypes.DataKind,
+	key string,
+	item ldstoretypes.SerializedItemDescriptor,
+) (bool, error) {
+	startTime := time.Now()
+	updated, err := s.store.Upsert(kind, key, item)
+	s.metrics.recordStoreOperation(startTime, storeOperationUpsert, kind.GetName(), err)
+	return updated, err
+}
+
+func (s instrumentedStore) IsInitialized() bool {
+	return s.store.IsInitialized()
+}
+
+func (s instrumentedStore) IsStoreAvailable() bool {
+	return s.store.IsStoreAvailable()
+}
+
+func (s instrumentedStore) Close() error {
+	return s.store.Close()
+}
+
+func (m *DataSystemMetrics) recordStoreOperation(startTime time.Time, operation, dataKind string, err error) {
+	attrs := []attribute.KeyValue{attribute.String(dataStoreOperationAttributeName, operation)}
+	if dataKind != "" {
+		attrs = append(attrs, attribute.String(dataStoreDataKindAttributeName, dataKind))
+	}
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+		m.storeOpErrors.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+	}
+	m.storeOpDuration.Record(context.Background(), time.Since(startTime).Seconds(),
+		metric.WithAttributes(append(attrs, attribute.String(resultAttributeName, result))...))
+}
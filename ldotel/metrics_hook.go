@@ -0,0 +1,218 @@
+package ldotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+)
+
+const (
+	meterName = "launchdarkly-client"
+
+	evaluationsMetricName       = "feature_flag.evaluations"
+	evaluationErrorsMetricName  = "feature_flag.evaluation.errors"
+	evaluationDurationMetric    = "feature_flag.evaluation.duration"
+	variationIndexAttributeName = "feature_flag.variation_index"
+	reasonKindAttributeName     = "feature_flag.reason_kind"
+	errorKindAttributeName      = "feature_flag.error_kind"
+
+	// OtherFlagsKey is the value of the feature_flag.key attribute that the MetricsHook uses for flags whose keys
+	// are not recorded because of WithMaxFlagKeys or WithFlagKeyFilter.
+	OtherFlagsKey = "__other__"
+
+	startTimeDataKey = "metricsStartTime"
+)
+
+// MetricsHookOption is used to implement functional options for the MetricsHook.
+type MetricsHookOption func(hook *MetricsHook)
+
+// WithMeterProvider sets the OpenTelemetry MeterProvider that the MetricsHook uses to create its instruments. By
+// default, it uses the global MeterProvider from otel.GetMeterProvider.
+func WithMeterProvider(provider metric.MeterProvider) MetricsHookOption {
+	return func(h *MetricsHook) {
+		h.meterProvider = provider
+	}
+}
+
+// WithoutFlagKeys is an option that removes the feature_flag.key attribute from all metrics, so that they are
+// aggregated across all flags. This gives the lowest possible cardinality.
+func WithoutFlagKeys() MetricsHookOption {
+	return func(h *MetricsHook) {
+		h.omitFlagKeys = true
+	}
+}
+
+// WithMaxFlagKeys limits the number of distinct flag keys that the MetricsHook records in the feature_flag.key
+// attribute. Once that many keys have been seen, evaluations of any other flag are recorded with the key
+// OtherFlagsKey. A limit of zero or less means no limit, which is the default.
+func WithMaxFlagKeys(limit int) MetricsHookOption {
+	return func(h *MetricsHook) {
+		h.maxFlagKeys = limit
+	}
+}
+
+// WithFlagKeyFilter specifies which flag keys the MetricsHook records in the feature_flag.key attribute.
+// Evaluations of flags for which the filter returns false are recorded with the key OtherFlagsKey.
+func WithFlagKeyFilter(filter func(flagKey string) bool) MetricsHookOption {
+	return func(h *MetricsHook) {
+		h.flagKeyFilter = filter
+	}
+}
+
+// WithoutVariationIndex is an option that removes the feature_flag.variation_index attribute from the evaluation
+// counter. This can reduce cardinality for flags with many variations.
+func WithoutVariationIndex() MetricsHookOption {
+	return func(h *MetricsHook) {
+		h.omitVariationIndex = true
+	}
+}
+
+// A MetricsHook records OpenTelemetry metrics for flag evaluations. It is a companion to TracingHook, and the two
+// can be used together.
+//
+// It records the following instruments:
+//   - "feature_flag.evaluations", a counter of evaluations, with the attributes feature_flag.key,
+//     feature_flag.variation_index (omitted if the evaluation did not produce a variation), and
+//     feature_flag.reason_kind, such as "FALLTHROUGH" or "ERROR".
+//   - "feature_flag.evaluation.errors", a counter of evaluations whose reason kind is "ERROR", with the attributes
+//     feature_flag.key and feature_flag.error_kind, such as "FLAG_NOT_FOUND".
+//   - "feature_flag.evaluation.duration", a histogram of the time each evaluation took, in seconds, with the
+//     attribute feature_flag.key.
+//
+// All of them also have the attribute feature_flag.provider_name with the value "LaunchDarkly". Because the number
+// of flags can be large, options such as WithMaxFlagKeys and WithoutFlagKeys can be used to limit the cardinality
+// of the feature_flag.key attribute.
+//
+//	client, _ = ld.MakeCustomClient("sdk-key", ld.Config{
+//		    Hooks: []ldhooks.Hook{ldotel.NewMetricsHook(ldotel.WithMaxFlagKeys(100))},
+//		}, 5*time.Second)
+type MetricsHook struct {
+	ldhooks.Unimplemented
+	metadata           ldhooks.Metadata
+	meterProvider      metric.MeterProvider
+	omitFlagKeys       bool
+	omitVariationIndex bool
+	maxFlagKeys        int
+	flagKeyFilter      func(string) bool
+	flagKeys           *flagKeySet
+	evaluations        metric.Int64Counter
+	errors             metric.Int64Counter
+	duration           metric.Float64Histogram
+}
+
+type flagKeySet struct {
+	keys map[string]struct{}
+	lock sync.RWMutex
+}
+
+// Metadata returns meta-data about the metrics hook.
+func (h MetricsHook) Metadata() ldhooks.Metadata {
+	return h.metadata
+}
+
+// NewMetricsHook creates a new MetricsHook instance. The MetricsHook can be provided to the LaunchDarkly client
+// in order to record OpenTelemetry metrics for flag evaluations.
+func NewMetricsHook(opts ...MetricsHookOption) MetricsHook {
+	hook := MetricsHook{
+		metadata: ldhooks.NewMetadata("LaunchDarkly Metrics Hook"),
+		flagKeys: &flagKeySet{keys: make(map[string]struct{})},
+	}
+	for _, opt := range opts {
+		opt(&hook)
+	}
+	if hook.meterProvider == nil {
+		hook.meterProvider = otel.GetMeterProvider()
+	}
+	meter := hook.meterProvider.Meter(meterName, metric.WithInstrumentationVersion(Version))
+
+	// Creating an instrument only fails if the name or options are invalid, and even then a usable no-op
+	// instrument is returned, so the errors can be ignored.
+	hook.evaluations, _ = meter.Int64Counter(evaluationsMetricName,
+		metric.WithDescription("The number of feature flag evaluations"),
+		metric.WithUnit("{evaluation}"))
+	hook.errors, _ = meter.Int64Counter(evaluationErrorsMetricName,
+		metric.WithDescription("The number of feature flag evaluations that returned an error"),
+		metric.WithUnit("{evaluation}"))
+	hook.duration, _ = meter.Float64Histogram(evaluationDurationMetric,
+		metric.WithDescription("The duration of feature flag evaluations"),
+		metric.WithUnit("s"))
+	return hook
+}
+
+// BeforeEvaluation implements the BeforeEvaluation evaluation stage.
+func (h MetricsHook) BeforeEvaluation(_ context.Context, _ ldhooks.EvaluationSeriesContext,
+	data ldhooks.EvaluationSeriesData) (ldhooks.EvaluationSeriesData, error) {
+	return ldhooks.NewEvaluationSeriesBuilder(data).Set(startTimeDataKey, time.Now()).Build(), nil
+}
+
+// AfterEvaluation implements the AfterEvaluation evaluation stage.
+func (h MetricsHook) AfterEvaluation(ctx context.Context, seriesContext ldhooks.EvaluationSeriesContext,
+	data ldhooks.EvaluationSeriesData, detail ldreason.EvaluationDetail) (ldhooks.EvaluationSeriesData, error) {
+	common := []attribute.KeyValue{semconv.FeatureFlagProviderName("LaunchDarkly")}
+	if !h.omitFlagKeys {
+		common = append(common, semconv.FeatureFlagKey(h.flagKeyAttribute(seriesContext.FlagKey())))
+	}
+
+	if startTime, ok := data.Get(startTimeDataKey); ok {
+		if asTime, ok := startTime.(time.Time); ok {
+			h.duration.Record(ctx, time.Since(asTime).Seconds(), metric.WithAttributes(common...))
+		}
+	}
+
+	evalAttribs := withAttributes(common, attribute.String(reasonKindAttributeName, string(detail.Reason.GetKind())))
+	if index, ok := detail.VariationIndex.Get(); ok && !h.omitVariationIndex {
+		evalAttribs = append(evalAttribs, attribute.Int(variationIndexAttributeName, index))
+	}
+	h.evaluations.Add(ctx, 1, metric.WithAttributes(evalAttribs...))
+
+	if detail.Reason.GetKind() == ldreason.EvalReasonError {
+		errorKind := attribute.String(errorKindAttributeName, string(detail.Reason.GetErrorKind()))
+		h.errors.Add(ctx, 1, metric.WithAttributes(withAttributes(common, errorKind)...))
+	}
+	return data, nil
+}
+
+func (h MetricsHook) flagKeyAttribute(flagKey string) string {
+	if h.flagKeyFilter != nil && !h.flagKeyFilter(flagKey) {
+		return OtherFlagsKey
+	}
+	if h.maxFlagKeys <= 0 {
+		return flagKey
+	}
+	set := h.flagKeys
+	set.lock.RLock()
+	_, seen := set.keys[flagKey]
+	count := len(set.keys)
+	set.lock.RUnlock()
+	if seen {
+		return flagKey
+	}
+	if count >= h.maxFlagKeys {
+		return OtherFlagsKey
+	}
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	if _, seen := set.keys[flagKey]; !seen {
+		if len(set.keys) >= h.maxFlagKeys {
+			return OtherFlagsKey
+		}
+		set.keys[flagKey] = struct{}{}
+	}
+	return flagKey
+}
+
+func withAttributes(common []attribute.KeyValue, extra ...attribute.KeyValue) []attribute.KeyValue {
+	ret := make([]attribute.KeyValue, 0, len(common)+len(extra))
+	return append(append(ret, common...), extra...)
+}
+
+// Ensure that MetricsHook conforms to the ldhooks.Hook interface.
+var _ ldhooks.Hook = MetricsHook{}
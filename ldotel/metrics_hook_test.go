@@ -0,0 +1,167 @@
+package ldotel
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	ldclient "github.com/launchdarkly/go-server-sdk/v7"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func createClientWithMetrics(t *testing.T, options ...MetricsHookOption) (*ldclient.LDClient, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	options = append([]MetricsHookOption{WithMeterProvider(provider)}, options...)
+
+	td := ldtestdata.DataSource()
+	td.Update(td.Flag(flagKey).VariationForAll(true))
+	td.Update(td.Flag("other-flag").VariationForAll(false))
+	client, err := ldclient.MakeCustomClient("", ldclient.Config{
+		DataSource: td,
+		Events:     ldcomponents.NoEvents(),
+		Logging:    ldcomponents.NoLogging(),
+		Hooks:      []ldhooks.Hook{NewMetricsHook(options...)},
+	}, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, reader
+}
+
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(gocontext.Background(), &rm))
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
+
+// sumsByAttribute returns the counter values for a metric keyed by the value of one attribute.
+func sumsByAttribute(t *testing.T, reader *sdkmetric.ManualReader, name string, key attribute.Key) map[string]int64 {
+	sum, ok := collectMetric(t, reader, name).(metricdata.Sum[int64])
+	require.True(t, ok, "metric %s was not recorded", name)
+	ret := make(map[string]int64)
+	for _, point := range sum.DataPoints {
+		if value, ok := point.Attributes.Value(key); ok {
+			ret[value.Emit()] += point.Value
+		} else {
+			ret[""] += point.Value
+		}
+	}
+	return ret
+}
+
+func TestMetricsHookCountsEvaluations(t *testing.T) {
+	client, reader := createClientWithMetrics(t)
+	context := ldcontext.New("test-context")
+
+	_, _ = client.BoolVariation(flagKey, context, false)
+	_, _ = client.BoolVariation(flagKey, context, false)
+	_, _ = client.BoolVariation("other-flag", context, false)
+
+	sum, ok := collectMetric(t, reader, evaluationsMetricName).(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 2)
+	for _, point := range sum.DataPoints {
+		key, _ := point.Attributes.Value("feature_flag.key")
+		providerName, _ := point.Attributes.Value("feature_flag.provider_name")
+		reasonKind, _ := point.Attributes.Value(reasonKindAttributeName)
+		variationIndex, _ := point.Attributes.Value(variationIndexAttributeName)
+		assert.Equal(t, "LaunchDarkly", providerName.AsString())
+		assert.Equal(t, "FALLTHROUGH", reasonKind.AsString())
+		switch key.AsString() {
+		case flagKey:
+			assert.Equal(t, int64(2), point.Value)
+			assert.Equal(t, int64(0), variationIndex.AsInt64()) // ldtestdata uses index 0 for true
+		case "other-flag":
+			assert.Equal(t, int64(1), point.Value)
+			assert.Equal(t, int64(1), variationIndex.AsInt64())
+		default:
+			assert.Fail(t, "unexpected flag key", key.AsString())
+		}
+	}
+
+	assert.Nil(t, collectMetric(t, reader, evaluationErrorsMetricName))
+}
+
+func TestMetricsHookCountsErrors(t *testing.T) {
+	client, reader := createClientWithMetrics(t)
+	context := ldcontext.New("test-context")
+
+	_, _ = client.BoolVariation("unknown-flag", context, false)
+
+	assert.Equal(t, map[string]int64{"ERROR": 1},
+		sumsByAttribute(t, reader, evaluationsMetricName, reasonKindAttributeName))
+	assert.Equal(t, map[string]int64{"FLAG_NOT_FOUND": 1},
+		sumsByAttribute(t, reader, evaluationErrorsMetricName, errorKindAttributeName))
+	assert.Equal(t, map[string]int64{"": 1},
+		sumsByAttribute(t, reader, evaluationsMetricName, variationIndexAttributeName))
+}
+
+func TestMetricsHookRecordsDuration(t *testing.T) {
+	client, reader := createClientWithMetrics(t)
+	context := ldcontext.New("test-context")
+
+	_, _ = client.BoolVariation(flagKey, context, false)
+
+	histogram, ok := collectMetric(t, reader, evaluationDurationMetric).(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+	key, _ := histogram.DataPoints[0].Attributes.Value("feature_flag.key")
+	assert.Equal(t, flagKey, key.AsString())
+}
+
+func TestMetricsHookCardinalityOptions(t *testing.T) {
+	context := ldcontext.New("test-context")
+	evaluateAll := func(client *ldclient.LDClient) {
+		_, _ = client.BoolVariation(flagKey, context, false)
+		_, _ = client.BoolVariation("other-flag", context, false)
+		_, _ = client.BoolVariation("unknown-flag", context, false)
+	}
+
+	t.Run("max flag keys", func(t *testing.T) {
+		client, reader := createClientWithMetrics(t, WithMaxFlagKeys(1))
+		evaluateAll(client)
+		evaluateAll(client)
+		assert.Equal(t, map[string]int64{flagKey: 2, OtherFlagsKey: 4},
+			sumsByAttribute(t, reader, evaluationsMetricName, "feature_flag.key"))
+	})
+
+	t.Run("flag key filter", func(t *testing.T) {
+		client, reader := createClientWithMetrics(t, WithFlagKeyFilter(func(key string) bool {
+			return key == "other-flag"
+		}))
+		evaluateAll(client)
+		assert.Equal(t, map[string]int64{"other-flag": 1, OtherFlagsKey: 2},
+			sumsByAttribute(t, reader, evaluationsMetricName, "feature_flag.key"))
+	})
+
+	t.Run("without flag keys", func(t *testing.T) {
+		client, reader := createClientWithMetrics(t, WithoutFlagKeys())
+		evaluateAll(client)
+		assert.Equal(t, map[string]int64{"": 3},
+			sumsByAttribute(t, reader, evaluationsMetricName, "feature_flag.key"))
+		assert.Equal(t, map[string]int64{"": 1},
+			sumsByAttribute(t, reader, evaluationErrorsMetricName, "feature_flag.key"))
+	})
+
+	t.Run("without variation index", func(t *testing.T) {
+		client, reader := createClientWithMetrics(t, WithoutVariationIndex())
+		evaluateAll(client)
+		assert.Equal(t, map[string]int64{"": 3},
+			sumsByAttribute(t, reader, evaluationsMetricName, variationIndexAttributeName))
+	})
+}
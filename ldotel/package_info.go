@@ -5,6 +5,10 @@
 //	client, _ = ld.MakeCustomClient("sdk-key", ld.Config{
 //		    Hooks: []ldhooks.Hook{ldotel.NewTracingHook()},
 //		}, 5*time.Second)
+//
+// To record OpenTelemetry metrics for flag evaluations, such as evaluation counts and latency, one would use the
+// MetricsHook, which can be combined with the TracingHook. Metrics about the SDK's data source, data store, and event
+// delivery are recorded by DataSystemMetrics.
 package ldotel
 
 // Version is the current version string of the ldotel package. This is updated by our release scripts.
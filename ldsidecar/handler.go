@@ -0,0 +1,266 @@
+package ldsidecar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldtime"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldclient "github.com/launchdarkly/go-server-sdk/v7"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/interfaces/flagstate"
+)
+
+const (
+	flagsPath       = "/flags"
+	statusPath      = "/status"
+	flagChangesPath = "/flag-changes"
+
+	flagChangeEventName = "flag-change"
+
+	// Request bodies only contain a context and a default value, so anything larger than this is rejected
+	// rather than read into memory.
+	maxRequestBodyBytes = 1024 * 1024
+)
+
+type handler struct {
+	client *ldclient.LDClient
+	mux    *http.ServeMux
+}
+
+type evaluateRequest struct {
+	Context      ldcontext.Context `json:"context"`
+	DefaultValue ldvalue.Value     `json:"defaultValue"`
+}
+
+type evaluateResponse struct {
+	Key            string                    `json:"key"`
+	Value          ldvalue.Value             `json:"value"`
+	VariationIndex ldvalue.OptionalInt       `json:"variationIndex"`
+	Reason         ldreason.EvaluationReason `json:"reason"`
+}
+
+type evaluateAllRequest struct {
+	Context                    ldcontext.Context `json:"context"`
+	ClientSideOnly             bool              `json:"clientSideOnly"`
+	WithReasons                bool              `json:"withReasons"`
+	DetailsOnlyForTrackedFlags bool              `json:"detailsOnlyForTrackedFlags"`
+}
+
+type statusResponse struct {
+	Initialized bool                 `json:"initialized"`
+	DataSource  dataSourceStatusJSON `json:"dataSource"`
+	DataStore   dataStoreStatusJSON  `json:"dataStore"`
+}
+
+type dataSourceStatusJSON struct {
+	State      interfaces.DataSourceState `json:"state"`
+	StateSince ldtime.UnixMillisecondTime `json:"stateSince"`
+	LastError  *dataSourceErrorJSON       `json:"lastError,omitempty"`
+}
+
+type dataSourceErrorJSON struct {
+	Kind       interfaces.DataSourceErrorKind `json:"kind"`
+	StatusCode int                            `json:"statusCode,omitempty"`
+	Message    string                         `json:"message,omitempty"`
+	Time       ldtime.UnixMillisecondTime     `json:"time"`
+}
+
+type dataStoreStatusJSON struct {
+	Available    bool `json:"available"`
+	NeedsRefresh bool `json:"needsRefresh"`
+}
+
+type flagChangeJSON struct {
+	Key        string                     `json:"key"`
+	Kind       interfaces.FlagChangeKind  `json:"kind"`
+	Cause      interfaces.FlagChangeCause `json:"cause"`
+	CauseKey   string                     `json:"causeKey,omitempty"`
+	OldVersion ldvalue.OptionalInt        `json:"oldVersion"`
+	NewVersion ldvalue.OptionalInt        `json:"newVersion"`
+	Source     string                     `json:"source,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler returns an HTTP handler that evaluates flags and reports status using the specified client.
+// See the package documentation for a description of the endpoints.
+//
+// The handler does not close the client; the application remains responsible for that.
+func NewHandler(client *ldclient.LDClient) http.Handler {
+	h := &handler{client: client, mux: http.NewServeMux()}
+	h.mux.HandleFunc(flagsPath, h.handleEvaluateAll)
+	h.mux.HandleFunc(flagsPath+"/", h.handleEvaluate)
+	h.mux.HandleFunc(statusPath, h.handleStatus)
+	h.mux.HandleFunc(flagChangesPath, h.handleFlagChanges)
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *handler) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	flagKey := strings.TrimPrefix(r.URL.Path, flagsPath+"/")
+	if flagKey == "" || strings.Contains(flagKey, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	var req evaluateRequest
+	if !readRequest(w, r, &req) || !validateContext(w, req.Context) {
+		return
+	}
+	// An evaluation error is reported in the reason, just as it is for JSONVariationDetail, so it is not
+	// treated as a failed request.
+	_, detail, _ := h.client.JSONVariationDetailCtx(r.Context(), flagKey, req.Context, req.DefaultValue)
+	writeJSON(w, http.StatusOK, evaluateResponse{
+		Key:            flagKey,
+		Value:          detail.Value,
+		VariationIndex: detail.VariationIndex,
+		Reason:         detail.Reason,
+	})
+}
+
+func (h *handler) handleEvaluateAll(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	var req evaluateAllRequest
+	if !readRequest(w, r, &req) || !validateContext(w, req.Context) {
+		return
+	}
+	var options []flagstate.Option
+	if req.ClientSideOnly {
+		options = append(options, flagstate.OptionClientSideOnly())
+	}
+	if req.WithReasons {
+		options = append(options, flagstate.OptionWithReasons())
+	}
+	if req.DetailsOnlyForTrackedFlags {
+		options = append(options, flagstate.OptionDetailsOnlyForTrackedFlags())
+	}
+	writeJSON(w, http.StatusOK, h.client.AllFlagsState(req.Context, options...))
+}
+
+func (h *handler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	dataSourceStatus := h.client.GetDataSourceStatusProvider().GetStatus()
+	dataStoreStatus := h.client.GetDataStoreStatusProvider().GetStatus()
+	resp := statusResponse{
+		Initialized: h.client.Initialized(),
+		DataSource: dataSourceStatusJSON{
+			State:      dataSourceStatus.State,
+			StateSince: ldtime.UnixMillisFromTime(dataSourceStatus.StateSince),
+		},
+		DataStore: dataStoreStatusJSON{
+			Available:    dataStoreStatus.Available,
+			NeedsRefresh: dataStoreStatus.NeedsRefresh,
+		},
+	}
+	if lastError := dataSourceStatus.LastError; lastError.Kind != "" {
+		resp.DataSource.LastError = &dataSourceErrorJSON{
+			Kind:       lastError.Kind,
+			StatusCode: lastError.StatusCode,
+			Message:    lastError.Message,
+			Time:       ldtime.UnixMillisFromTime(lastError.Time),
+		}
+	}
+	status := http.StatusOK
+	if !resp.Initialized {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
+
+func (h *handler) handleFlagChanges(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported by this server")
+		return
+	}
+	// The listener is removed when the request ends, and a slow client cannot block the SDK.
+	ch := h.client.GetFlagTracker().AddFlagChangeListenerCtx(r.Context(), interfaces.ListenerOptions{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		event, ok := <-ch
+		if !ok {
+			return // the request has ended, or the client was closed
+		}
+		cause := event.Cause
+		if cause == "" {
+			cause = interfaces.FlagChangeCauseDirect
+		}
+		data, _ := json.Marshal(flagChangeJSON{
+			Key:        event.Key,
+			Kind:       event.Kind,
+			Cause:      cause,
+			CauseKey:   event.CauseKey,
+			OldVersion: event.OldVersion,
+			NewVersion: event.NewVersion,
+			Source:     event.Source,
+		})
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", flagChangeEventName, data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func requireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", r.Method))
+	return false
+}
+
+func readRequest(w http.ResponseWriter, r *http.Request, target interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(target); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return false
+	}
+	return true
+}
+
+func validateContext(w http.ResponseWriter, context ldcontext.Context) bool {
+	if err := context.Err(); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid context: %s", err))
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		status = http.StatusInternalServerError
+		data, _ = json.Marshal(errorResponse{Error: err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
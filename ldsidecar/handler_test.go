@@ -0,0 +1,200 @@
+package ldsidecar
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	ldclient "github.com/launchdarkly/go-server-sdk/v7"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/ldcomponents"
+	"github.com/launchdarkly/go-server-sdk/v7/testhelpers/ldtestdata"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContextJSON = `{"kind":"user","key":"userkey"}`
+
+func withSidecar(t *testing.T, action func(serverURL string, data *ldtestdata.TestDataSource)) {
+	data := ldtestdata.DataSource()
+	data.Update(data.Flag("flag1").VariationForAll(true))
+	data.Update(data.Flag("flag2").Variations(ldvalue.String("a"), ldvalue.String("b")).
+		FallthroughVariationIndex(1))
+	client, err := ldclient.MakeCustomClient("sdk-key", ldclient.Config{
+		DataSource: data,
+		Events:     ldcomponents.NoEvents(),
+		Logging:    ldcomponents.NoLogging(),
+	}, 0)
+	require.NoError(t, err)
+	defer client.Close()
+
+	server := httptest.NewServer(NewHandler(client))
+	defer server.Close()
+	action(server.URL, data)
+}
+
+func post(t *testing.T, url, body string) (int, map[string]interface{}) {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return readJSONResponse(t, resp)
+}
+
+func readJSONResponse(t *testing.T, resp *http.Response) (int, map[string]interface{}) {
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var result map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	return resp.StatusCode, result
+}
+
+func TestEvaluateFlag(t *testing.T) {
+	withSidecar(t, func(serverURL string, _ *ldtestdata.TestDataSource) {
+		status, result := post(t, serverURL+"/flags/flag2", `{"context":`+testContextJSON+`,"defaultValue":"x"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, map[string]interface{}{
+			"key":            "flag2",
+			"value":          "b",
+			"variationIndex": float64(1),
+			"reason":         map[string]interface{}{"kind": "FALLTHROUGH"},
+		}, result)
+	})
+}
+
+func TestEvaluateUnknownFlagReturnsDefaultValueWithErrorReason(t *testing.T) {
+	withSidecar(t, func(serverURL string, _ *ldtestdata.TestDataSource) {
+		status, result := post(t, serverURL+"/flags/unknown", `{"context":`+testContextJSON+`,"defaultValue":"x"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "x", result["value"])
+		assert.Nil(t, result["variationIndex"])
+		assert.Equal(t, map[string]interface{}{"kind": "ERROR", "errorKind": "FLAG_NOT_FOUND"}, result["reason"])
+	})
+}
+
+func TestEvaluateAllFlags(t *testing.T) {
+	withSidecar(t, func(serverURL string, _ *ldtestdata.TestDataSource) {
+		status, result := post(t, serverURL+"/flags", `{"context":`+testContextJSON+`,"withReasons":true}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, true, result["flag1"])
+		assert.Equal(t, "b", result["flag2"])
+		assert.Equal(t, true, result["$valid"])
+		metadata := result["$flagsState"].(map[string]interface{})["flag2"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"kind": "FALLTHROUGH"}, metadata["reason"])
+	})
+}
+
+func TestBadRequests(t *testing.T) {
+	withSidecar(t, func(serverURL string, _ *ldtestdata.TestDataSource) {
+		for _, path := range []string{"/flags", "/flags/flag1"} {
+			t.Run(path, func(t *testing.T) {
+				t.Run("malformed body", func(t *testing.T) {
+					status, result := post(t, serverURL+path, `{"context":`)
+					assert.Equal(t, http.StatusBadRequest, status)
+					assert.Contains(t, result["error"], "invalid request body")
+				})
+
+				t.Run("body too large", func(t *testing.T) {
+					body := `{"context":{"key":"user"},"defaultValue":"` + strings.Repeat("x", maxRequestBodyBytes) + `"}`
+					status, result := post(t, serverURL+path, body)
+					assert.Equal(t, http.StatusBadRequest, status)
+					assert.Contains(t, result["error"], "too large")
+				})
+
+				t.Run("missing context", func(t *testing.T) {
+					status, result := post(t, serverURL+path, `{}`)
+					assert.Equal(t, http.StatusBadRequest, status)
+					assert.Contains(t, result["error"], "invalid context")
+				})
+
+				t.Run("wrong method", func(t *testing.T) {
+					resp, err := http.Get(serverURL + path)
+					require.NoError(t, err)
+					defer resp.Body.Close()
+					assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+					assert.Equal(t, http.MethodPost, resp.Header.Get("Allow"))
+				})
+			})
+		}
+	})
+}
+
+func TestStatus(t *testing.T) {
+	t.Run("initialized", func(t *testing.T) {
+		withSidecar(t, func(serverURL string, _ *ldtestdata.TestDataSource) {
+			resp, err := http.Get(serverURL + "/status")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			status, result := readJSONResponse(t, resp)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, true, result["initialized"])
+			assert.Equal(t, "VALID", result["dataSource"].(map[string]interface{})["state"])
+			assert.Nil(t, result["dataSource"].(map[string]interface{})["lastError"])
+			assert.Equal(t, map[string]interface{}{"available": true, "needsRefresh": false}, result["dataStore"])
+		})
+	})
+
+	t.Run("not initialized", func(t *testing.T) {
+		client, _ := ldclient.MakeCustomClient("sdk-key", ldclient.Config{
+			DataSource: mocks.DataSourceThatNeverInitializes(),
+			Events:     ldcomponents.NoEvents(),
+			Logging:    ldcomponents.NoLogging(),
+		}, 0)
+		defer client.Close()
+		server := httptest.NewServer(NewHandler(client))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/status")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		status, result := readJSONResponse(t, resp)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, false, result["initialized"])
+		assert.Equal(t, "INITIALIZING", result["dataSource"].(map[string]interface{})["state"])
+	})
+}
+
+func TestFlagChangeStream(t *testing.T) {
+	withSidecar(t, func(serverURL string, data *ldtestdata.TestDataSource) {
+		resp, err := http.Get(serverURL + "/flag-changes")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		lines := make(chan string, 10)
+		go func() {
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+			close(lines)
+		}()
+
+		data.Update(data.Flag("flag1").VariationForAll(false))
+
+		for _, expected := range []string{"event: flag-change", `data: {"key":"flag1","kind":"UPDATED","cause":"DIRECT","oldVersion":1,"newVersion":2,"source":"TestDataSource"}`, ""} {
+			select {
+			case line := <-lines:
+				assert.Equal(t, expected, line)
+			case <-time.After(time.Second):
+				require.Fail(t, "timed out waiting for flag change event")
+			}
+		}
+	})
+}
+
+func TestUnknownPath(t *testing.T) {
+	withSidecar(t, func(serverURL string, _ *ldtestdata.TestDataSource) {
+		resp, err := http.Post(serverURL+"/flags/a/b", "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
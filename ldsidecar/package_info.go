@@ -0,0 +1,35 @@
+// Package ldsidecar provides an HTTP interface to an SDK client, so that services written in other languages
+// can evaluate feature flags through a single SDK instance running alongside them (for instance, in the same
+// pod), rather than each embedding their own SDK.
+//
+// [NewHandler] returns an [http.Handler] which can be served on its own or mounted within an existing
+// server:
+//
+//	client, _ := ld.MakeClient(sdkKey, 5*time.Second)
+//	http.ListenAndServe("localhost:8080", ldsidecar.NewHandler(client))
+//
+// The handler provides the following endpoints. Request and response bodies are JSON, and contexts use the
+// same JSON representation as [github.com/launchdarkly/go-sdk-common/v3/ldcontext.Context].
+//
+//   - POST /flags/{key}: evaluates one flag. The request body is an object with a "context" property and an
+//     optional "defaultValue" property. The response is an object with "key", "value", "variationIndex", and
+//     "reason" properties. If the flag cannot be evaluated, the response has the default value and an error
+//     reason, as it would for [github.com/launchdarkly/go-server-sdk/v7.LDClient.JSONVariationDetail].
+//   - POST /flags: evaluates all flags. The request body is an object with a "context" property, and optional
+//     boolean properties "clientSideOnly", "withReasons", and "detailsOnlyForTrackedFlags" which correspond to
+//     the options in [github.com/launchdarkly/go-server-sdk/v7/interfaces/flagstate]. The response is the JSON
+//     representation of [github.com/launchdarkly/go-server-sdk/v7/interfaces/flagstate.AllFlags].
+//   - GET /status: reports whether the client is initialized, along with the current data source and data
+//     store status. The status code is 200 if the client is initialized and 503 otherwise, so this can be used
+//     as a readiness check.
+//   - GET /flag-changes: a Server-Sent Events stream with a "flag-change" event whenever a flag configuration
+//     changes. The event data is an object with the properties of
+//     [github.com/launchdarkly/go-server-sdk/v7/interfaces.FlagChangeEvent]: "key", "kind", "cause",
+//     "causeKey" (omitted for a direct change), "oldVersion" and "newVersion" (null if unknown), and "source"
+//     (omitted if the data source has no name).
+//
+// Request bodies larger than 1 MiB are rejected with a 400 status.
+// Evaluations are performed by the SDK client as usual, so they generate analytics events and run any
+// configured hooks. The handler does not provide authentication; it should only be reachable by the services
+// that are meant to use it.
+package ldsidecar
@@ -1,6 +1,7 @@
 package ldclient
 
 import (
+	gocontext "context"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,15 @@ import (
 	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
 	"github.com/launchdarkly/go-sdk-common/v3/ldsampling"
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/hooks"
+	"github.com/launchdarkly/go-server-sdk/v7/ldhooks"
+)
+
+const (
+	migratorReadFuncName    = "Migrator.Read"
+	migratorReadExFuncName  = "Migrator.ReadCtx"
+	migratorWriteFuncName   = "Migrator.Write"
+	migratorWriteExFuncName = "Migrator.WriteCtx"
 )
 
 type migratorImpl struct {
@@ -22,12 +32,75 @@ type migratorImpl struct {
 	measureErrors  bool
 
 	sampler *ldsampling.RatioSampler
+
+	hookRunner *hooks.Runner
+}
+
+// This interface is implemented by LDClient, so that a migrator created with it can run the migration series of
+// any configured hooks. Other implementations of MigrationCapableClient do not have hooks.
+type migrationHooksProvider interface {
+	migrationHookRunner() *hooks.Runner
+}
+
+// This interface is implemented by LDClient, so that the context.Context passed to ReadCtx or WriteCtx is also
+// passed to the hooks of the flag evaluation.
+type migrationVariationCtxProvider interface {
+	MigrationVariationCtx(
+		ctx gocontext.Context,
+		key string,
+		context ldcontext.Context,
+		defaultStage ldmigration.Stage,
+	) (ldmigration.Stage, interfaces.LDMigrationOpTracker, error)
 }
 
 func (m *migratorImpl) Read(
 	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
 ) MigrationReadResult {
-	stage, tracker, err := m.client.MigrationVariation(key, context, defaultStage)
+	return m.readWithHooks(gocontext.TODO(), key, context, defaultStage, payload, migratorReadFuncName)
+}
+
+func (m *migratorImpl) ReadCtx(
+	ctx gocontext.Context,
+	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
+) MigrationReadResult {
+	return m.readWithHooks(ctx, key, context, defaultStage, payload, migratorReadExFuncName)
+}
+
+func (m *migratorImpl) readWithHooks(
+	ctx gocontext.Context,
+	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{}, method string,
+) MigrationReadResult {
+	if m.hookRunner == nil {
+		result, _ := m.read(ctx, key, context, defaultStage, payload)
+		return result
+	}
+	var result MigrationReadResult
+	m.hookRunner.RunMigration(
+		ctx,
+		ldhooks.NewMigrationSeriesContext(key, context, defaultStage, ldmigration.Read, method),
+		func() (ldmigration.Stage, error) {
+			var stage ldmigration.Stage
+			result, stage = m.read(ctx, key, context, defaultStage, payload)
+			return stage, result.GetError()
+		},
+	)
+	return result
+}
+
+func (m *migratorImpl) migrationVariation(
+	ctx gocontext.Context, key string, context ldcontext.Context, defaultStage ldmigration.Stage,
+) (ldmigration.Stage, interfaces.LDMigrationOpTracker, error) {
+	if client, ok := m.client.(migrationVariationCtxProvider); ok {
+		return client.MigrationVariationCtx(ctx, key, context, defaultStage)
+	}
+	return m.client.MigrationVariation(key, context, defaultStage)
+}
+
+func (m *migratorImpl) read(
+	ctx gocontext.Context,
+	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
+) (MigrationReadResult, ldmigration.Stage) {
+	stage, tracker, err := m.migrationVariation(ctx, key, context, defaultStage)
 	tracker.Operation(ldmigration.Read)
 
 	if err != nil {
@@ -85,13 +158,48 @@ func (m *migratorImpl) Read(
 
 	m.trackMigrationOp(tracker)
 
-	return readResult
+	return readResult, stage
 }
 
 func (m *migratorImpl) Write(
 	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
 ) MigrationWriteResult {
-	stage, tracker, err := m.client.MigrationVariation(key, context, defaultStage)
+	return m.writeWithHooks(gocontext.TODO(), key, context, defaultStage, payload, migratorWriteFuncName)
+}
+
+func (m *migratorImpl) WriteCtx(
+	ctx gocontext.Context,
+	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
+) MigrationWriteResult {
+	return m.writeWithHooks(ctx, key, context, defaultStage, payload, migratorWriteExFuncName)
+}
+
+func (m *migratorImpl) writeWithHooks(
+	ctx gocontext.Context,
+	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{}, method string,
+) MigrationWriteResult {
+	if m.hookRunner == nil {
+		result, _ := m.write(ctx, key, context, defaultStage, payload)
+		return result
+	}
+	var result MigrationWriteResult
+	m.hookRunner.RunMigration(
+		ctx,
+		ldhooks.NewMigrationSeriesContext(key, context, defaultStage, ldmigration.Write, method),
+		func() (ldmigration.Stage, error) {
+			var stage ldmigration.Stage
+			result, stage = m.write(ctx, key, context, defaultStage, payload)
+			return stage, result.GetAuthoritativeResult().GetError()
+		},
+	)
+	return result
+}
+
+func (m *migratorImpl) write(
+	ctx gocontext.Context,
+	key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
+) (MigrationWriteResult, ldmigration.Stage) {
+	stage, tracker, err := m.migrationVariation(ctx, key, context, defaultStage)
 	tracker.Operation(ldmigration.Write)
 	if err != nil {
 		m.client.Loggers().Error(err)
@@ -148,7 +256,7 @@ func (m *migratorImpl) Write(
 
 	m.trackMigrationOp(tracker)
 
-	return writeResult
+	return writeResult, stage
 }
 
 func (m *migratorImpl) trackMigrationOp(tracker interfaces.LDMigrationOpTracker) {
@@ -1,6 +1,8 @@
 package ldclient
 
 import (
+	gocontext "context"
+
 	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
 	"github.com/launchdarkly/go-sdk-common/v3/ldmigration"
 	ldevents "github.com/launchdarkly/go-sdk-events/v3"
@@ -134,8 +136,24 @@ type Migrator interface {
 	Read(
 		key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
 	) MigrationReadResult
+	// ReadCtx is the same as Read, but with a context.Context.
+	//
+	// Cancelling the context.Context will not cause the operation to be cancelled. The context.Context is used
+	// by hook implementations refer to [github.com/launchdarkly/go-server-sdk/v7/ldhooks.Hook].
+	ReadCtx(
+		ctx gocontext.Context,
+		key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
+	) MigrationReadResult
 	// Write uses the provided flag key and context to execute a migration-backed write operation.
 	Write(
 		key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
 	) MigrationWriteResult
+	// WriteCtx is the same as Write, but with a context.Context.
+	//
+	// Cancelling the context.Context will not cause the operation to be cancelled. The context.Context is used
+	// by hook implementations refer to [github.com/launchdarkly/go-server-sdk/v7/ldhooks.Hook].
+	WriteCtx(
+		ctx gocontext.Context,
+		key string, context ldcontext.Context, defaultStage ldmigration.Stage, payload interface{},
+	) MigrationWriteResult
 }
@@ -111,6 +111,9 @@ func (b *MigratorBuilder) Build() (Migrator, error) {
 		measureErrors:      b.measureErrors,
 		sampler:            ldsampling.NewSampler(),
 	}
+	if provider, ok := b.client.(migrationHooksProvider); ok {
+		migrator.hookRunner = provider.migrationHookRunner()
+	}
 
 	return &migrator, nil
 }
@@ -53,6 +53,10 @@ type ClientContext interface {
 	// GetDataSourceStatusReporter is a FDV2 method, do not use. Not subject to semantic versioning.
 	// This method is a replacement for GetDataSourceUpdateSink when the SDK is in FDv2 mode.
 	GetDataSourceStatusReporter() DataSourceStatusReporter
+
+	// GetSelectorStore is a FDV2 method, do not use. Not subject to semantic versioning.
+	// It returns the component that data sources use to find out which version of the data the SDK has.
+	GetSelectorStore() SelectorStore
 }
 
 // BasicClientContext is the basic implementation of the ClientContext interface, not including any
@@ -68,6 +72,7 @@ type BasicClientContext struct {
 	DataStoreUpdateSink      DataStoreUpdateSink
 	DataDestination          DataDestination
 	DataSourceStatusReporter DataSourceStatusReporter
+	SelectorStore            SelectorStore
 }
 
 func (b BasicClientContext) GetSDKKey() string { return b.SDKKey } //nolint:revive
@@ -108,3 +113,7 @@ func (b BasicClientContext) GetDataDestination() DataDestination { //nolint:revi
 func (b BasicClientContext) GetDataSourceStatusReporter() DataSourceStatusReporter { //nolint:revive
 	return b.DataSourceStatusReporter
 }
+
+func (b BasicClientContext) GetSelectorStore() SelectorStore { //nolint:revive
+	return b.SelectorStore
+}
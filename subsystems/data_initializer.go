@@ -0,0 +1,40 @@
+package subsystems
+
+import (
+	"context"
+
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+)
+
+// Basis is a full set of data obtained by a DataInitializer.
+//
+// This type is not stable, and not subject to any backwards
+// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+type Basis struct {
+	// Events contains the data, in the form of put-object events.
+	Events []fdv2proto.Event
+	// Selector identifies the version of the data, if known.
+	Selector *fdv2proto.Selector
+	// Persist indicates whether the data may be written to a persistent store.
+	Persist bool
+}
+
+// DataInitializer obtains a single, full set of data when the SDK starts up.
+//
+// The FDv2 data system runs each configured initializer in order until one of them returns a basis, and then
+// starts the synchronizers, which keep the data up to date.
+//
+// This interface is not stable, and not subject to any backwards
+// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+type DataInitializer interface {
+	// Name returns a description of the initializer, for logging.
+	Name() string
+	// Fetch obtains the data. It should return promptly with ctx.Err() if ctx is cancelled.
+	Fetch(ctx context.Context) (*Basis, error)
+}
@@ -0,0 +1,32 @@
+package subsystems
+
+import "time"
+
+// DataSystemConfiguration describes the components of the FDv2 data system: an optional persistent
+// store, an ordered list of initializers, and the synchronizers used to keep the data up to date.
+//
+// The components are provided as configurers rather than built instances, because the SDK must supply
+// each of them with a ClientContext that refers to the data system itself.
+//
+// This type is not stable, and not subject to any backwards
+// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+type DataSystemConfiguration struct {
+	// Store is the configurer for an optional persistent store. If nil, only the in-memory store is used.
+	Store ComponentConfigurer[DataStore]
+	// StoreMode determines whether data from initializers and synchronizers is written back to the Store.
+	StoreMode DataStoreMode
+	// Initializers are run in order when the SDK starts, until one of them obtains a basis.
+	Initializers []ComponentConfigurer[DataInitializer]
+	// PrimarySynchronizer is the configurer for the data source that keeps the SDK's data up to date. If
+	// nil, the SDK only uses data from the Initializers and the Store.
+	PrimarySynchronizer ComponentConfigurer[DataSource]
+	// SecondarySynchronizer is the configurer for an optional data source that is used if the primary
+	// synchronizer stays interrupted for longer than FallbackDelay.
+	SecondarySynchronizer ComponentConfigurer[DataSource]
+	// FallbackDelay is how long the primary synchronizer may be interrupted before the data system
+	// switches to the secondary synchronizer.
+	FallbackDelay time.Duration
+}
@@ -0,0 +1,55 @@
+package subsystems
+
+import (
+	"io"
+
+	ldeval "github.com/launchdarkly/go-server-sdk-evaluation/v3"
+)
+
+// EvaluationCache is an interface for a cache of flag evaluation results. If the SDK is configured with
+// one (see the EvaluationCache field of [github.com/launchdarkly/go-server-sdk/v7.Config]), it stores
+// the result of evaluating a flag for a context, and reuses it the next time the same version of the flag
+// is evaluated for an identical context, instead of running the flag's rules again.
+//
+// The SDK only uses the cache for the evaluation itself: analytics events and hooks are generated as usual
+// for every evaluation. It calls InvalidateFlag whenever a flag, or a prerequisite flag or segment that it
+// depends on, is changed; and it never caches a result that depended on a Big Segment, since Big Segment
+// membership can change without any change to the flag data.
+//
+// The SDK provides an in-memory implementation via ldcomponents.InMemoryEvaluationCache. Implementations
+// must be safe for concurrent use.
+type EvaluationCache interface {
+	io.Closer
+
+	// Get returns the cached entry for the given key, or false if there is none.
+	Get(key EvaluationCacheKey) (EvaluationCacheEntry, bool)
+
+	// Set stores an entry. The cache may discard it at any time.
+	Set(key EvaluationCacheKey, entry EvaluationCacheEntry)
+
+	// InvalidateFlag discards all entries for the specified flag key, for any flag version and context.
+	InvalidateFlag(flagKey string)
+}
+
+// EvaluationCacheKey identifies an entry in an EvaluationCache.
+type EvaluationCacheKey struct {
+	// FlagKey is the key of the evaluated flag.
+	FlagKey string
+
+	// FlagVersion is the version of the evaluated flag.
+	FlagVersion int
+
+	// ContextFingerprint uniquely identifies the evaluation context, including all of its attributes.
+	ContextFingerprint string
+}
+
+// EvaluationCacheEntry is a cached evaluation result.
+type EvaluationCacheEntry struct {
+	// Result is the result of the evaluation, as returned by the evaluator. This does not reflect the
+	// default value that the application passed in, which is substituted afterward if necessary.
+	Result ldeval.Result
+
+	// PrerequisiteEvents contains the information about any prerequisite flags that were evaluated, so that
+	// the SDK can generate analytics events for them if the result is reused.
+	PrerequisiteEvents []ldeval.PrerequisiteFlagEvent
+}
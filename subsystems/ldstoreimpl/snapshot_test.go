@@ -0,0 +1,128 @@
+package ldstoreimpl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldbuilders"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datakinds"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/datastore"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/sharedtest/mocks"
+	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeSnapshotTestData() []ldstoretypes.Collection {
+	flag1 := ldbuilders.NewFlagBuilder("flag1").Version(1).On(true).Variations(ldvalue.Bool(true)).Build()
+	flag2 := ldbuilders.NewFlagBuilder("flag2").Version(2).Build()
+	segment1 := ldbuilders.NewSegmentBuilder("segment1").Version(3).Included("a").Build()
+	return sharedtest.NewDataSetBuilder().Flags(flag1, flag2).Segments(segment1).Build()
+}
+
+func writeTestSnapshot(t *testing.T, data []ldstoretypes.Collection) []byte {
+	store := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+	require.NoError(t, store.Init(data))
+	var buf bytes.Buffer
+	require.NoError(t, WriteSnapshot(&buf, store))
+	return buf.Bytes()
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	data := makeSnapshotTestData()
+
+	allData, err := ReadSnapshot(bytes.NewReader(writeTestSnapshot(t, data)))
+	require.NoError(t, err)
+
+	assert.Equal(t, sharedtest.NormalizeDataSet(data), sharedtest.NormalizeDataSet(allData))
+}
+
+func TestSnapshotFormat(t *testing.T) {
+	var file map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(writeTestSnapshot(t, makeSnapshotTestData()), &file))
+
+	assert.JSONEq(t, `1`, string(file["formatVersion"]))
+	assert.Contains(t, string(file["checksum"]), `"sha256:`)
+	assert.NotEmpty(t, file["createdAt"])
+
+	var data map[string]map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(file["data"], &data))
+	assert.Len(t, data["flags"], 2)
+	assert.Len(t, data["segments"], 1)
+}
+
+func TestWriteSnapshotOmitsDeletedItems(t *testing.T) {
+	data := makeSnapshotTestData()
+	data[0].Items = append(data[0].Items, ldstoretypes.KeyedItemDescriptor{
+		Key: "deleted-flag", Item: ldstoretypes.ItemDescriptor{Version: 4, Item: nil},
+	})
+
+	allData, err := ReadSnapshot(bytes.NewReader(writeTestSnapshot(t, data)))
+	require.NoError(t, err)
+
+	assert.Equal(t, sharedtest.NormalizeDataSet(makeSnapshotTestData()), sharedtest.NormalizeDataSet(allData))
+}
+
+func TestWriteSnapshotErrors(t *testing.T) {
+	t.Run("store not initialized", func(t *testing.T) {
+		store := datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers())
+		var buf bytes.Buffer
+		assert.Error(t, WriteSnapshot(&buf, store))
+		assert.Equal(t, 0, buf.Len())
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store := mocks.NewCapturingDataStore(datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers()))
+		_ = store.Init(nil)
+		fakeError := errors.New("sorry")
+		store.SetFakeError(fakeError)
+		var buf bytes.Buffer
+		assert.ErrorIs(t, WriteSnapshot(&buf, store), fakeError)
+		assert.Equal(t, 0, buf.Len())
+	})
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	snapshot := string(writeTestSnapshot(t, makeSnapshotTestData()))
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		_, err := ReadSnapshot(strings.NewReader(`{"formatVersion":`))
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported format version", func(t *testing.T) {
+		_, err := ReadSnapshot(strings.NewReader(strings.Replace(snapshot, `"formatVersion":1`,
+			`"formatVersion":2`, 1)))
+		assert.ErrorContains(t, err, "unsupported snapshot format version 2")
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		_, err := ReadSnapshot(strings.NewReader(strings.Replace(snapshot, `"segment1"`, `"segment2"`, 1)))
+		assert.ErrorContains(t, err, "checksum")
+	})
+
+	t.Run("malformed item", func(t *testing.T) {
+		var file snapshotFile
+		require.NoError(t, json.Unmarshal([]byte(snapshot), &file))
+		file.Data = json.RawMessage(`{"flags":{"flag1":{"key":true}},"segments":{}}`)
+		file.Checksum = snapshotChecksum(file.Data)
+		corrupted, _ := json.Marshal(file)
+
+		_, err := ReadSnapshot(bytes.NewReader(corrupted))
+		assert.ErrorContains(t, err, `"flag1"`)
+	})
+}
+
+func TestReadSnapshotDataKinds(t *testing.T) {
+	allData, err := ReadSnapshot(bytes.NewReader(writeTestSnapshot(t, makeSnapshotTestData())))
+	require.NoError(t, err)
+	require.Len(t, allData, 2)
+	assert.Equal(t, datakinds.Features, allData[0].Kind)
+	assert.Equal(t, datakinds.Segments, allData[1].Kind)
+}
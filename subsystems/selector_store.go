@@ -0,0 +1,18 @@
+package subsystems
+
+import (
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
+)
+
+// SelectorStore provides the selector that identifies the data currently held by the SDK, so that a data
+// source can ask LaunchDarkly for only the changes since that data.
+//
+// This interface is not stable, and not subject to any backwards
+// compatibility guarantees or semantic versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+type SelectorStore interface {
+	// Selector returns the current selector, or fdv2proto.NoSelector() if there is no data.
+	Selector() *fdv2proto.Selector
+}
@@ -0,0 +1,168 @@
+package ldservices
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+	"github.com/launchdarkly/go-test-helpers/v3/httphelpers"
+	"github.com/launchdarkly/go-test-helpers/v3/jsonhelpers"
+)
+
+// Intent codes that can be used with FDv2ServerIntent.
+const (
+	// FDv2IntentTransferFull means that the service will send a full data set.
+	FDv2IntentTransferFull = "xfer-full"
+	// FDv2IntentTransferChanges means that the service will send only the changes since the SDK's basis.
+	FDv2IntentTransferChanges = "xfer-changes"
+	// FDv2IntentNone means that the SDK's basis is already up to date.
+	FDv2IntentNone = "none"
+)
+
+// Object kinds that can be used with FDv2PutObject and FDv2DeleteObject.
+const (
+	// FDv2FlagKind is the object kind for feature flags.
+	FDv2FlagKind = "flag"
+	// FDv2SegmentKind is the object kind for segments.
+	FDv2SegmentKind = "segment"
+)
+
+// FDv2Event is a simple representation of an event in the FDv2 protocol. The same event can be sent in
+// a stream, using ToSSEEvent, or as part of a polling response.
+//
+// Since this package cannot depend on the SDK's internal protocol types, the event data is any value that
+// can be marshaled to JSON. It is usually simpler to use the constructors such as FDv2PutObject.
+type FDv2Event struct {
+	// Name is the event name, such as "put-object".
+	Name string `json:"name"`
+	// Data is the event data, which is marshaled to JSON.
+	Data interface{} `json:"data"`
+}
+
+// ToSSEEvent converts the event to the form that is used by the FDv2 streaming endpoint.
+func (e FDv2Event) ToSSEEvent() httphelpers.SSEEvent {
+	return httphelpers.SSEEvent{Event: e.Name, Data: string(jsonhelpers.ToJSON(e.Data))}
+}
+
+// FDv2ServerIntent creates a "server-intent" event, which begins a payload. The code is normally one of
+// FDv2IntentTransferFull, FDv2IntentTransferChanges, or FDv2IntentNone.
+func FDv2ServerIntent(code, reason string) FDv2Event {
+	return FDv2Event{
+		Name: "server-intent",
+		Data: map[string]interface{}{
+			"payloads": []interface{}{
+				map[string]interface{}{"id": "payload-1", "target": 1, "code": code, "reason": reason},
+			},
+		},
+	}
+}
+
+// FDv2PutObject creates a "put-object" event, which adds or replaces an item. The kind is normally
+// FDv2FlagKind or FDv2SegmentKind.
+//
+// The object may be either a object produced by KeyAndVersionItem or a real data model object from the
+// ldmodel package.
+func FDv2PutObject(kind, key string, version int, object interface{}) FDv2Event {
+	return FDv2Event{
+		Name: "put-object",
+		Data: map[string]interface{}{"kind": kind, "key": key, "version": version, "object": object},
+	}
+}
+
+// FDv2DeleteObject creates a "delete-object" event, which removes an item.
+func FDv2DeleteObject(kind, key string, version int) FDv2Event {
+	return FDv2Event{
+		Name: "delete-object",
+		Data: map[string]interface{}{"kind": kind, "key": key, "version": version},
+	}
+}
+
+// FDv2PayloadTransferred creates a "payload-transferred" event, which ends a payload. The state and version
+// make up the selector that identifies the data; the SDK sends the state back as its basis when it
+// reconnects.
+func FDv2PayloadTransferred(state string, version int) FDv2Event {
+	return FDv2Event{
+		Name: "payload-transferred",
+		Data: map[string]interface{}{"state": state, "version": version},
+	}
+}
+
+// FDv2Heartbeat creates a "heart-beat" event.
+func FDv2Heartbeat() FDv2Event {
+	return FDv2Event{Name: "heart-beat", Data: map[string]interface{}{}}
+}
+
+// FDv2Goodbye creates a "goodbye" event, which tells the SDK that the service is about to close the
+// connection.
+func FDv2Goodbye(reason string) FDv2Event {
+	return FDv2Event{
+		Name: "goodbye",
+		Data: map[string]interface{}{"reason": reason, "silent": false, "catastrophe": false},
+	}
+}
+
+// FDv2Error creates an "error" event, which tells the SDK to discard the payload that is in progress.
+func FDv2Error(payloadID, reason string) FDv2Event {
+	return FDv2Event{
+		Name: "error",
+		Data: map[string]interface{}{"payloadId": payloadID, "reason": reason},
+	}
+}
+
+// FDv2Changes creates the sequence of events for a payload of changes: a "server-intent" event with the code
+// FDv2IntentTransferChanges, the specified changes, and a "payload-transferred" event with the new selector.
+//
+//	events := ldservices.FDv2Changes("state2", 2,
+//	    ldservices.FDv2PutObject(ldservices.FDv2FlagKind, "flag1", 2, flag1v2))
+func FDv2Changes(state string, version int, changes ...FDv2Event) []FDv2Event {
+	events := make([]FDv2Event, 0, len(changes)+2)
+	events = append(events, FDv2ServerIntent(FDv2IntentTransferChanges, "stale"))
+	events = append(events, changes...)
+	return append(events, FDv2PayloadTransferred(state, version))
+}
+
+// FDv2UpToDate creates the sequence of events that tells the SDK that its basis is already up to date.
+func FDv2UpToDate() []FDv2Event {
+	return []FDv2Event{FDv2ServerIntent(FDv2IntentNone, "up-to-date")}
+}
+
+// ToFDv2Events creates the sequence of events for a full payload containing all of the flags and segments:
+// a "server-intent" event with the code FDv2IntentTransferFull, a "put-object" event for each item, and
+// a "payload-transferred" event with the specified selector.
+func (s *ServerSDKData) ToFDv2Events(state string, version int) []FDv2Event {
+	events := []FDv2Event{FDv2ServerIntent(FDv2IntentTransferFull, "payload-missing")}
+	events = append(events, putObjectEvents(FDv2FlagKind, s.FlagsMap)...)
+	events = append(events, putObjectEvents(FDv2SegmentKind, s.SegmentsMap)...)
+	return append(events, FDv2PayloadTransferred(state, version))
+}
+
+func putObjectEvents(kind string, items map[string]interface{}) []FDv2Event {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // for predictable output in tests
+	events := make([]FDv2Event, 0, len(keys))
+	for _, key := range keys {
+		item := items[key]
+		events = append(events, FDv2PutObject(kind, key, getVersionFromJSON(item), item))
+	}
+	return events
+}
+
+func getVersionFromJSON(item interface{}) int {
+	return ldvalue.Parse(jsonhelpers.ToJSON(item)).GetByKey("version").IntValue()
+}
+
+// fdv2PollingResponse is the JSON representation of a response from the FDv2 polling endpoint.
+type fdv2PollingResponse struct {
+	Events []FDv2Event `json:"events"`
+}
+
+func makeFDv2PollingResponse(events []FDv2Event) []byte {
+	if events == nil {
+		events = []FDv2Event{}
+	}
+	bytes, _ := json.Marshal(fdv2PollingResponse{Events: events})
+	return bytes
+}
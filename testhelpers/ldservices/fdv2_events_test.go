@@ -0,0 +1,46 @@
+package ldservices
+
+import (
+	"testing"
+
+	"github.com/launchdarkly/go-test-helpers/v3/httphelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFDv2EventToSSEEvent(t *testing.T) {
+	event := FDv2PutObject(FDv2FlagKind, "flagkey", 2, KeyAndVersionItem("flagkey", 2)).ToSSEEvent()
+	assert.Equal(t, "put-object", event.Event)
+	assert.JSONEq(t, `{"kind":"flag","key":"flagkey","version":2,"object":{"key":"flagkey","version":2}}`, event.Data)
+}
+
+func TestServerSDKDataToFDv2Events(t *testing.T) {
+	data := NewServerSDKData().
+		Flags(KeyAndVersionItem("flagkey2", 2), KeyAndVersionItem("flagkey1", 1)).
+		Segments(KeyAndVersionItem("segkey1", 3))
+
+	events := data.ToFDv2Events("state1", 5)
+	require.Len(t, events, 5)
+
+	sse := make([]httphelpers.SSEEvent, 0, len(events))
+	for _, e := range events {
+		sse = append(sse, e.ToSSEEvent())
+	}
+	assert.Equal(t, "server-intent", sse[0].Event)
+	assert.JSONEq(t, `{"payloads":[{"id":"payload-1","target":1,"code":"xfer-full","reason":"payload-missing"}]}`, sse[0].Data)
+	assert.JSONEq(t, `{"kind":"flag","key":"flagkey1","version":1,"object":{"key":"flagkey1","version":1}}`, sse[1].Data)
+	assert.JSONEq(t, `{"kind":"flag","key":"flagkey2","version":2,"object":{"key":"flagkey2","version":2}}`, sse[2].Data)
+	assert.JSONEq(t, `{"kind":"segment","key":"segkey1","version":3,"object":{"key":"segkey1","version":3}}`, sse[3].Data)
+	assert.Equal(t, "payload-transferred", sse[4].Event)
+	assert.JSONEq(t, `{"state":"state1","version":5}`, sse[4].Data)
+}
+
+func TestFDv2Changes(t *testing.T) {
+	events := FDv2Changes("state2", 2, FDv2DeleteObject(FDv2SegmentKind, "segkey1", 4))
+	require.Len(t, events, 3)
+	assert.JSONEq(t, `{"payloads":[{"id":"payload-1","target":1,"code":"xfer-changes","reason":"stale"}]}`,
+		events[0].ToSSEEvent().Data)
+	assert.JSONEq(t, `{"kind":"segment","key":"segkey1","version":4}`, events[1].ToSSEEvent().Data)
+	assert.JSONEq(t, `{"state":"state2","version":2}`, events[2].ToSSEEvent().Data)
+}
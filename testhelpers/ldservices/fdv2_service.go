@@ -0,0 +1,139 @@
+package ldservices
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/launchdarkly/go-test-helpers/v3/httphelpers"
+)
+
+// FDv2BasisParam is the name of the query parameter in which the SDK sends its basis to the FDv2 endpoints.
+// The basis is the state of the selector that identifies the data the SDK already has.
+const FDv2BasisParam = "basis"
+
+// FDv2Responder determines the events that the simulated FDv2 service provides at the start of each stream
+// connection, or in each polling response. The basis is the value of FDv2BasisParam in the request, or an
+// empty string if the SDK did not provide one.
+type FDv2Responder func(basis string) []FDv2Event
+
+// FDv2Events returns an FDv2Responder that always provides the same events.
+//
+//	handler, stream := ldservices.ServerSideFDv2StreamingServiceHandler(
+//	    ldservices.FDv2Events(ldservices.NewServerSDKData().Flags(flag1).ToFDv2Events("state1", 1)...))
+func FDv2Events(events ...FDv2Event) FDv2Responder {
+	return func(string) []FDv2Event { return events }
+}
+
+// FDv2Sequence returns an FDv2Responder that provides each of the specified sequences of events in turn, for
+// successive stream connections or polling requests. Once all of them have been used, the last one is
+// repeated.
+//
+//	// The first poll receives the full data set; later polls receive an update
+//	handler := ldservices.ServerSideFDv2PollingServiceHandler(ldservices.FDv2Sequence(
+//	    data.ToFDv2Events("state1", 1),
+//	    ldservices.FDv2Changes("state2", 2, ldservices.FDv2PutObject(ldservices.FDv2FlagKind, "flag1", 2, flag1v2)),
+//	))
+func FDv2Sequence(first []FDv2Event, remaining ...[]FDv2Event) FDv2Responder {
+	all := append([][]FDv2Event{first}, remaining...)
+	var lock sync.Mutex
+	next := 0
+	return func(string) []FDv2Event {
+		lock.Lock()
+		defer lock.Unlock()
+		events := all[next]
+		if next < len(all)-1 {
+			next++
+		}
+		return events
+	}
+}
+
+// FDv2ByBasis returns an FDv2Responder that chooses its events according to the basis that the SDK provided.
+// If the basis is a key in the map, the corresponding events are used; otherwise, the defaultEvents are used.
+// This can be used to simulate a service that sends only the changes since the data that the SDK already has.
+//
+//	update := ldservices.FDv2PutObject(ldservices.FDv2FlagKind, "flag1", 2, flag1v2)
+//	responder := ldservices.FDv2ByBasis(data.ToFDv2Events("state2", 2), map[string][]ldservices.FDv2Event{
+//	    "state1": ldservices.FDv2Changes("state2", 2, update),
+//	    "state2": ldservices.FDv2UpToDate(),
+//	})
+func FDv2ByBasis(defaultEvents []FDv2Event, eventsByBasis map[string][]FDv2Event) FDv2Responder {
+	return func(basis string) []FDv2Event {
+		if events, ok := eventsByBasis[basis]; ok {
+			return events
+		}
+		return defaultEvents
+	}
+}
+
+// ServerSideFDv2StreamingServiceHandler creates an HTTP handler to mimic the LaunchDarkly server-side streaming
+// service using the FDv2 protocol. Like ServerSideStreamingServiceHandler, it enforces that the request path is
+// ServerSideSDKStreamingPath and that the method is GET.
+//
+// Each new connection begins with the events provided by the responder. After that, any events sent with the
+// returned SSEStreamControl are copied to all connected clients; SendFDv2Events and EnqueueFDv2Events are
+// convenient ways to send a sequence of FDv2Event values.
+//
+//	data := ldservices.NewServerSDKData().Flags(flag1, flag2)
+//	handler, stream := ldservices.ServerSideFDv2StreamingServiceHandler(
+//	    ldservices.FDv2Events(data.ToFDv2Events("state1", 1)...))
+//	server := httptest.NewServer(handler)
+//	ldservices.SendFDv2Events(stream, ldservices.FDv2Changes("state2", 2,
+//	    ldservices.FDv2DeleteObject(ldservices.FDv2FlagKind, "flag1", 2))...) // push an update
+//	stream.Close() // force any current stream connections to be closed
+func ServerSideFDv2StreamingServiceHandler(
+	responder FDv2Responder,
+) (http.Handler, httphelpers.SSEStreamControl) {
+	streamHandler, stream := httphelpers.SSEHandler(nil)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The initial events are written before delegating to the SSE handler, which does not allow them to
+		// vary between connections. The SSE handler never calls WriteHeader, so it is fine for the response to
+		// have already started.
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream; charset=utf-8")
+		h.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		for _, event := range responder(r.URL.Query().Get(FDv2BasisParam)) {
+			_, _ = w.Write(event.ToSSEEvent().Bytes())
+		}
+		streamHandler.ServeHTTP(w, r)
+	})
+	return httphelpers.HandlerForPath(ServerSideSDKStreamingPath, httphelpers.HandlerForMethod("GET", handler, nil), nil),
+		stream
+}
+
+// ServerSideFDv2PollingServiceHandler creates an HTTP handler to mimic the LaunchDarkly server-side polling
+// service using the FDv2 protocol.
+//
+// This handler returns a JSON object containing the events provided by the responder for requests to the same
+// path as ServerSidePollingServiceHandler, and a 404 error for all other requests.
+//
+//	data := ldservices.NewServerSDKData().Flags(flag1, flag2)
+//	handler := ldservices.ServerSideFDv2PollingServiceHandler(ldservices.FDv2Events(data.ToFDv2Events("state1", 1)...))
+func ServerSideFDv2PollingServiceHandler(responder FDv2Responder) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := makeFDv2PollingResponse(responder(r.URL.Query().Get(FDv2BasisParam)))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+	return httphelpers.HandlerForPath(serverSideSDKPollingPath,
+		httphelpers.HandlerForMethod("GET", handler, nil), nil)
+}
+
+// SendFDv2Events sends each of the events to all clients that are currently connected to the stream. See
+// httphelpers.SSEStreamControl.Send.
+func SendFDv2Events(stream httphelpers.SSEStreamControl, events ...FDv2Event) {
+	for _, event := range events {
+		stream.Send(event.ToSSEEvent())
+	}
+}
+
+// EnqueueFDv2Events is the same as SendFDv2Events, except that if there are currently no open connections,
+// the events are sent to the next client that connects. See httphelpers.SSEStreamControl.Enqueue.
+func EnqueueFDv2Events(stream httphelpers.SSEStreamControl, events ...FDv2Event) {
+	for _, event := range events {
+		stream.Enqueue(event.ToSSEEvent())
+	}
+}
@@ -0,0 +1,92 @@
+package ldservices
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	helpers "github.com/launchdarkly/go-test-helpers/v3"
+	"github.com/launchdarkly/go-test-helpers/v3/httphelpers"
+)
+
+func TestServerSideFDv2StreamingServiceHandler(t *testing.T) {
+	full := NewServerSDKData().Flags(KeyAndVersionItem("flagkey", 1)).ToFDv2Events("state1", 1)
+	handler, stream := ServerSideFDv2StreamingServiceHandler(FDv2ByBasis(full, map[string][]FDv2Event{
+		"state1": FDv2UpToDate(),
+	}))
+	defer stream.Close()
+
+	expectedBytes := func(events []FDv2Event) []byte {
+		var bytes []byte
+		for _, e := range events {
+			bytes = append(bytes, e.ToSSEEvent().Bytes()...)
+		}
+		return bytes
+	}
+
+	httphelpers.WithServer(handler, func(server *httptest.Server) {
+		t.Run("sends initial events and then updates", func(t *testing.T) {
+			resp, err := http.DefaultClient.Get(server.URL + ServerSideSDKStreamingPath)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, "text/event-stream; charset=utf-8", resp.Header.Get("Content-Type"))
+
+			expected := expectedBytes(full)
+			assert.Equal(t, string(expected), string(helpers.ReadWithTimeout(resp.Body, len(expected), time.Second)))
+
+			changes := FDv2Changes("state2", 2, FDv2DeleteObject(FDv2FlagKind, "flagkey", 2))
+			SendFDv2Events(stream, changes...)
+
+			expected = expectedBytes(changes)
+			assert.Equal(t, string(expected), string(helpers.ReadWithTimeout(resp.Body, len(expected), time.Second)))
+		})
+
+		t.Run("initial events depend on basis", func(t *testing.T) {
+			resp, err := http.DefaultClient.Get(server.URL + ServerSideSDKStreamingPath + "?basis=state1")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			expected := expectedBytes(FDv2UpToDate())
+			assert.Equal(t, string(expected), string(helpers.ReadWithTimeout(resp.Body, len(expected), time.Second)))
+		})
+
+		t.Run("returns 404 for wrong URL", func(t *testing.T) {
+			resp, err := http.DefaultClient.Get(server.URL + "/some/other/path")
+			assert.NoError(t, err)
+			assert.Equal(t, 404, resp.StatusCode)
+		})
+	})
+}
+
+func TestServerSideFDv2PollingServiceHandler(t *testing.T) {
+	first := NewServerSDKData().Flags(KeyAndVersionItem("flagkey", 1)).ToFDv2Events("state1", 1)
+	second := FDv2Changes("state2", 2, FDv2PutObject(FDv2FlagKind, "flagkey", 2, KeyAndVersionItem("flagkey", 2)))
+	handler := ServerSideFDv2PollingServiceHandler(FDv2Sequence(first, second))
+	client := httphelpers.ClientFromHandler(handler)
+
+	poll := func() string {
+		resp, err := client.Get(serverSideSDKPollingPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		bytes, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(bytes)
+	}
+
+	assert.JSONEq(t, string(makeFDv2PollingResponse(first)), poll())
+	assert.JSONEq(t, string(makeFDv2PollingResponse(second)), poll())
+	assert.JSONEq(t, string(makeFDv2PollingResponse(second)), poll()) // the last response is repeated
+
+	assert.JSONEq(t, `{"events":[
+		{"name":"server-intent","data":{"payloads":[{"id":"payload-1","target":1,"code":"xfer-changes","reason":"stale"}]}},
+		{"name":"put-object","data":{"kind":"flag","key":"flagkey","version":2,"object":{"key":"flagkey","version":2}}},
+		{"name":"payload-transferred","data":{"state":"state2","version":2}}
+	]}`, string(makeFDv2PollingResponse(second)))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/launchdarkly/go-server-sdk-evaluation/v3/ldmodel"
 	"github.com/launchdarkly/go-server-sdk/v7/interfaces"
+	"github.com/launchdarkly/go-server-sdk/v7/internal/fdv2proto"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoreimpl"
 	"github.com/launchdarkly/go-server-sdk/v7/subsystems/ldstoretypes"
@@ -20,6 +21,7 @@ type TestDataSource struct {
 	currentFlags    map[string]ldstoretypes.ItemDescriptor
 	currentBuilders map[string]*FlagBuilder
 	currentSegments map[string]ldstoretypes.ItemDescriptor
+	dataVersion     int
 	instances       []*testDataSourceImpl
 	lock            sync.Mutex
 }
@@ -27,8 +29,21 @@ type TestDataSource struct {
 type testDataSourceImpl struct {
 	owner   *TestDataSource
 	updates subsystems.DataSourceUpdateSink
+
+	// These are set instead of updates if the data source was created for the FDv2 data system.
+	destination    subsystems.DataDestination
+	statusReporter subsystems.DataSourceStatusReporter
+}
+
+// testDataSourceV2 is the ComponentConfigurer returned by TestDataSource.FDv2.
+type testDataSourceV2 struct {
+	owner *TestDataSource
 }
 
+// testDataSelectorState is the state of the selector that accompanies each update in FDv2 mode; the
+// selector's version is incremented for every update.
+const testDataSelectorState = "test-data"
+
 // DataSource creates an instance of [TestDataSource].
 //
 // Storing this object in the DataSource field of [github.com/launchdarkly/go-server-sdk/v7.Config]
@@ -96,7 +111,7 @@ func (t *TestDataSource) UpdateStatus(
 	t.lock.Unlock()
 
 	for _, instance := range instances {
-		instance.updates.UpdateStatus(newState, newError)
+		instance.updateStatus(newState, newError)
 	}
 
 	return t
@@ -154,11 +169,13 @@ func (t *TestDataSource) UsePreconfiguredSegment(segment ldmodel.Segment) *TestD
 	newSegment.Version = oldItem.Version + 1
 	newItem := ldstoretypes.ItemDescriptor{Version: newSegment.Version, Item: &newSegment}
 	t.currentSegments[segment.Key] = newItem
+	t.dataVersion++
+	dataVersion := t.dataVersion
 	instances := slices.Clone(t.instances)
 	t.lock.Unlock()
 
 	for _, instance := range instances {
-		instance.updates.Upsert(ldstoreimpl.Segments(), segment.Key, newItem)
+		instance.upsert(ldstoreimpl.Segments(), segment.Key, newItem, dataVersion)
 	}
 
 	return t
@@ -176,11 +193,13 @@ func (t *TestDataSource) updateInternal(
 	newItem := ldstoretypes.ItemDescriptor{Version: newVersion, Item: &newFlag}
 	t.currentFlags[key] = newItem
 	t.currentBuilders[key] = builder
+	t.dataVersion++
+	dataVersion := t.dataVersion
 	instances := slices.Clone(t.instances)
 	t.lock.Unlock()
 
 	for _, instance := range instances {
-		instance.updates.Upsert(ldstoreimpl.Features(), key, newItem)
+		instance.upsert(ldstoreimpl.Features(), key, newItem, dataVersion)
 	}
 }
 
@@ -188,13 +207,47 @@ func (t *TestDataSource) updateInternal(
 // LDClient instance. You do not need to call this method.
 func (t *TestDataSource) Build(context subsystems.ClientContext) (subsystems.DataSource, error) {
 	instance := &testDataSourceImpl{owner: t, updates: context.GetDataSourceUpdateSink()}
+	t.addInstance(instance)
+	return instance, nil
+}
+
+// FDv2 returns a configuration builder that associates this test data source with an LDClient instance
+// that uses the FDv2 data system, so that the test data is delivered in the same way as data from an
+// FDv2 synchronizer. Changes made with methods like [TestDataSource.Update] are propagated to all LDClient
+// instances, regardless of which data system they use.
+//
+//	td := ldtestdata.DataSource()
+//	config := ld.Config{
+//	    DataSystem: ldcomponents.DataSystem().Synchronizers(td.FDv2(), nil),
+//	}
+//
+// This method is not stable, and not subject to any backwards compatibility guarantees or semantic
+// versioning. It is not suitable for production usage.
+//
+// Do not use it.
+// You have been warned.
+func (t *TestDataSource) FDv2() subsystems.ComponentConfigurer[subsystems.DataSource] {
+	return testDataSourceV2{owner: t}
+}
+
+// Build is called internally by the SDK.
+func (b testDataSourceV2) Build(context subsystems.ClientContext) (subsystems.DataSource, error) {
+	instance := &testDataSourceImpl{
+		owner:          b.owner,
+		destination:    context.GetDataDestination(),
+		statusReporter: context.GetDataSourceStatusReporter(),
+	}
+	b.owner.addInstance(instance)
+	return instance, nil
+}
+
+func (t *TestDataSource) addInstance(instance *testDataSourceImpl) {
 	t.lock.Lock()
 	t.instances = append(t.instances, instance)
 	t.lock.Unlock()
-	return instance, nil
 }
 
-func (t *TestDataSource) makeInitData() []ldstoretypes.Collection {
+func (t *TestDataSource) makeInitData() ([]ldstoretypes.Collection, int) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 	flags := make([]ldstoretypes.KeyedItemDescriptor, 0, len(t.currentFlags))
@@ -208,7 +261,7 @@ func (t *TestDataSource) makeInitData() []ldstoretypes.Collection {
 	return []ldstoretypes.Collection{
 		{Kind: ldstoreimpl.Features(), Items: flags},
 		{Kind: ldstoreimpl.Segments(), Items: segments},
-	}
+	}, t.dataVersion
 }
 
 func (t *TestDataSource) closedInstance(instance *testDataSourceImpl) {
@@ -224,6 +277,10 @@ func (t *TestDataSource) closedInstance(instance *testDataSourceImpl) {
 	}
 }
 
+func (d *testDataSourceImpl) Name() string {
+	return "TestDataSource"
+}
+
 func (d *testDataSourceImpl) Close() error {
 	d.owner.closedInstance(d)
 	return nil
@@ -234,7 +291,61 @@ func (d *testDataSourceImpl) IsInitialized() bool {
 }
 
 func (d *testDataSourceImpl) Start(closeWhenReady chan<- struct{}) {
-	_ = d.updates.Init(d.owner.makeInitData())
-	d.updates.UpdateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
+	allData, dataVersion := d.owner.makeInitData()
+	if d.destination != nil {
+		d.destination.SetBasis(toFDv2Events(allData), fdv2proto.NewSelector(testDataSelectorState, dataVersion), true)
+	} else {
+		_ = d.updates.Init(allData)
+	}
+	d.updateStatus(interfaces.DataSourceStateValid, interfaces.DataSourceErrorInfo{})
 	close(closeWhenReady)
 }
+
+func (d *testDataSourceImpl) upsert(
+	kind ldstoretypes.DataKind,
+	key string,
+	item ldstoretypes.ItemDescriptor,
+	dataVersion int,
+) {
+	if d.destination != nil {
+		d.destination.ApplyDelta(
+			toFDv2Events([]ldstoretypes.Collection{
+				{Kind: kind, Items: []ldstoretypes.KeyedItemDescriptor{{Key: key, Item: item}}},
+			}),
+			fdv2proto.NewSelector(testDataSelectorState, dataVersion),
+			true,
+		)
+		return
+	}
+	d.updates.Upsert(kind, key, item)
+}
+
+func (d *testDataSourceImpl) updateStatus(
+	newState interfaces.DataSourceState,
+	newError interfaces.DataSourceErrorInfo,
+) {
+	if d.statusReporter != nil {
+		d.statusReporter.UpdateStatus(newState, newError)
+		return
+	}
+	d.updates.UpdateStatus(newState, newError)
+}
+
+func toFDv2Events(allData []ldstoretypes.Collection) []fdv2proto.Event {
+	var events []fdv2proto.Event
+	for _, coll := range allData {
+		kind := fdv2proto.FlagKind
+		if coll.Kind == ldstoreimpl.Segments() {
+			kind = fdv2proto.SegmentKind
+		}
+		for _, item := range coll.Items {
+			events = append(events, fdv2proto.PutObject{
+				Kind:    kind,
+				Key:     item.Key,
+				Version: item.Item.Version,
+				Object:  item.Item.Item,
+			})
+		}
+	}
+	return events
+}
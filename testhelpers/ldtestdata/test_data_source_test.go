@@ -166,3 +166,60 @@ func TestTestDataSource(t *testing.T) {
 		})
 	})
 }
+
+func TestTestDataSourceFDv2(t *testing.T) {
+	withFDv2DataSource := func(t *testing.T, td *TestDataSource, action func(*mocks.MockDataDestination)) {
+		t.Helper()
+		destination := mocks.NewMockDataDestination(datastore.NewInMemoryDataStore(sharedtest.NewTestLoggers()))
+		context := subsystems.BasicClientContext{DataDestination: destination, DataSourceStatusReporter: destination}
+		ds, err := td.FDv2().Build(context)
+		require.NoError(t, err)
+		defer ds.Close()
+
+		closer := make(chan struct{})
+		ds.Start(closer)
+		if !th.AssertChannelClosed(t, closer, time.Millisecond, "start did not close channel") {
+			t.FailNow()
+		}
+		destination.RequireStatusOf(t, interfaces.DataSourceStateValid)
+
+		action(destination)
+	}
+
+	t.Run("initializes with flags", func(t *testing.T) {
+		td := DataSource()
+		td.Update(td.Flag("flag1").On(true))
+		td.UsePreconfiguredSegment(ldbuilders.NewSegmentBuilder("segmentkey").Build())
+
+		withFDv2DataSource(t, td, func(destination *mocks.MockDataDestination) {
+			initData := destination.DataStore.WaitForNextInit(t, time.Millisecond)
+			dataMap := sharedtest.DataSetToMap(initData)
+			require.Len(t, dataMap[ldstoreimpl.Features()], 1)
+			require.Len(t, dataMap[ldstoreimpl.Segments()], 1)
+			assert.True(t, dataMap[ldstoreimpl.Features()]["flag1"].Item.(*ldmodel.FeatureFlag).On)
+		})
+	})
+
+	t.Run("updates flag", func(t *testing.T) {
+		td := DataSource()
+		td.Update(td.Flag("flag1").On(false))
+
+		withFDv2DataSource(t, td, func(destination *mocks.MockDataDestination) {
+			td.Update(td.Flag("flag1").On(true))
+
+			up := destination.DataStore.WaitForUpsert(t, ldstoreimpl.Features(), "flag1", 2, time.Millisecond)
+			assert.True(t, up.Item.Item.(*ldmodel.FeatureFlag).On)
+		})
+	})
+
+	t.Run("updates status", func(t *testing.T) {
+		td := DataSource()
+		withFDv2DataSource(t, td, func(destination *mocks.MockDataDestination) {
+			ei := interfaces.DataSourceErrorInfo{Kind: interfaces.DataSourceErrorKindNetworkError}
+			td.UpdateStatus(interfaces.DataSourceStateInterrupted, ei)
+
+			status := destination.RequireStatusOf(t, interfaces.DataSourceStateInterrupted)
+			assert.Equal(t, ei, status.LastError)
+		})
+	})
+}
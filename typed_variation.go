@@ -0,0 +1,111 @@
+package ldclient
+
+import (
+	gocontext "context"
+	"encoding/json"
+
+	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
+	"github.com/launchdarkly/go-sdk-common/v3/ldreason"
+	"github.com/launchdarkly/go-sdk-common/v3/ldvalue"
+)
+
+const (
+	typedVarFuncName         = "ldclient.Variation"
+	typedVarExFuncName       = "ldclient.VariationCtx"
+	typedVarDetailFuncName   = "ldclient.VariationDetail"
+	typedVarDetailExFuncName = "ldclient.VariationDetailCtx"
+)
+
+// Variation returns the value of a feature flag for a given evaluation context, decoded into a value of
+// type T.
+//
+// This is a convenient way to use a JSON flag whose variations are objects or arrays with a known structure.
+// The flag value is decoded with [encoding/json], so T can use the same struct tags as for [json.Unmarshal].
+//
+//	type bannerConfig struct {
+//	    Text  string `json:"text"`
+//	    Color string `json:"color"`
+//	}
+//	config, err := ldclient.Variation(client, "banner-config", context, bannerConfig{Text: "Welcome"})
+//
+// Returns defaultVal if there is an error, if the flag doesn't exist, or the feature is turned off and
+// has no off variation. If the flag value cannot be decoded into T, it also returns defaultVal along with an
+// error, and the evaluation is reported to analytics events and hooks with the error kind
+// [ldreason.EvalErrorWrongType], just as for a flag of the wrong type in [LDClient.BoolVariation].
+//
+// Because Go does not allow type parameters on methods, this is a function rather than a method of LDClient.
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluating#go
+func Variation[T any](client *LDClient, key string, context ldcontext.Context, defaultVal T) (T, error) {
+	value, _, err := typedVariation(gocontext.TODO(), client, key, context, defaultVal, client.eventsDefault,
+		typedVarFuncName)
+	return value, err
+}
+
+// VariationDetail is the same as [Variation], but also returns further information about how the value was
+// calculated. The "reason" data will also be included in analytics events.
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluation-reasons#go
+func VariationDetail[T any](
+	client *LDClient,
+	key string,
+	context ldcontext.Context,
+	defaultVal T,
+) (T, ldreason.EvaluationDetail, error) {
+	return typedVariation(gocontext.TODO(), client, key, context, defaultVal, client.eventsWithReasons,
+		typedVarDetailFuncName)
+}
+
+// VariationCtx is the same as [Variation], but accepts a context.Context.
+//
+// Cancelling the context.Context will not cause the evaluation to be cancelled. The context.Context is used
+// by hook implementations refer to [ldhooks.Hook].
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluating#go
+func VariationCtx[T any](
+	ctx gocontext.Context,
+	client *LDClient,
+	key string,
+	context ldcontext.Context,
+	defaultVal T,
+) (T, error) {
+	value, _, err := typedVariation(ctx, client, key, context, defaultVal, client.eventsDefault, typedVarExFuncName)
+	return value, err
+}
+
+// VariationDetailCtx is the same as [VariationDetail], but accepts a context.Context.
+//
+// Cancelling the context.Context will not cause the evaluation to be cancelled. The context.Context is used
+// by hook implementations refer to [ldhooks.Hook].
+//
+// For more information, see the Reference Guide: https://docs.launchdarkly.com/sdk/features/evaluation-reasons#go
+func VariationDetailCtx[T any](
+	ctx gocontext.Context,
+	client *LDClient,
+	key string,
+	context ldcontext.Context,
+	defaultVal T,
+) (T, ldreason.EvaluationDetail, error) {
+	return typedVariation(ctx, client, key, context, defaultVal, client.eventsWithReasons, typedVarDetailExFuncName)
+}
+
+func typedVariation[T any](
+	ctx gocontext.Context,
+	client *LDClient,
+	key string,
+	context ldcontext.Context,
+	defaultVal T,
+	eventsScope eventsScope,
+	method string,
+) (T, ldreason.EvaluationDetail, error) {
+	var decoded T
+	decode := func(value ldvalue.Value) error {
+		return json.Unmarshal([]byte(value.JSONString()), &decoded)
+	}
+	detail, _, err := client.variationWithHooks(ctx, client.evaluateInternal, key, context,
+		ldvalue.CopyArbitraryValue(defaultVal), false, decode, eventsScope, method)
+	if err != nil || detail.IsDefaultValue() || detail.Reason.GetKind() == ldreason.EvalReasonError {
+		return defaultVal, detail, err
+	}
+	return decoded, detail, nil
+}